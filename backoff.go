@@ -49,3 +49,11 @@ func (e *exponentialBackoff) wait() {
 
 	time.Sleep(e.backoff)
 }
+
+// failures returns the current number of consecutive failures
+func (e *exponentialBackoff) failures() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.failureCount
+}
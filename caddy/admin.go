@@ -2,10 +2,13 @@ package caddy
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/caddyserver/caddy/v2"
 	"github.com/dunglas/frankenphp"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type FrankenPHPAdmin struct{}
@@ -29,6 +32,26 @@ func (admin FrankenPHPAdmin) Routes() []caddy.AdminRoute {
 			Pattern: "/frankenphp/threads",
 			Handler: caddy.AdminHandlerFunc(admin.threads),
 		},
+		{
+			Pattern: "/frankenphp/threads/",
+			Handler: caddy.AdminHandlerFunc(admin.thread),
+		},
+		{
+			Pattern: "/frankenphp/requests",
+			Handler: caddy.AdminHandlerFunc(admin.requests),
+		},
+		{
+			Pattern: "/frankenphp/health",
+			Handler: caddy.AdminHandlerFunc(admin.health),
+		},
+		{
+			Pattern: "/frankenphp/ready",
+			Handler: caddy.AdminHandlerFunc(admin.ready),
+		},
+		{
+			Pattern: "/frankenphp/cache/purge",
+			Handler: caddy.AdminHandlerFunc(admin.purgeCache),
+		},
 	}
 }
 
@@ -45,8 +68,103 @@ func (admin *FrankenPHPAdmin) restartWorkers(w http.ResponseWriter, r *http.Requ
 }
 
 func (admin *FrankenPHPAdmin) threads(w http.ResponseWriter, _ *http.Request) error {
-	debugState := frankenphp.DebugState()
-	prettyJson, err := json.MarshalIndent(debugState, "", "    ")
+	return admin.json(w, frankenphp.DebugState())
+}
+
+// thread shows the state of a single thread: /frankenphp/threads/{index}
+// the current PHP stack is included if the "stack" query parameter is set
+// POST /frankenphp/threads/{index}/terminate stops the request currently handled by the thread
+func (admin *FrankenPHPAdmin) thread(w http.ResponseWriter, r *http.Request) error {
+	rawIndex, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/frankenphp/threads/"), "/")
+	index, err := strconv.Atoi(rawIndex)
+	if err != nil {
+		return admin.error(http.StatusNotFound, fmt.Errorf("invalid thread index"))
+	}
+
+	switch action {
+	case "":
+		if r.Method != http.MethodGet {
+			return admin.error(http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		}
+	case "terminate":
+		if r.Method != http.MethodPost {
+			return admin.error(http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		}
+
+		return admin.terminate(w, index)
+	default:
+		return admin.error(http.StatusNotFound, fmt.Errorf("unknown action %q", action))
+	}
+
+	threadState, ok := frankenphp.ThreadDebugStateByIndex(index, r.URL.Query().Has("stack"))
+	if !ok {
+		return admin.error(http.StatusNotFound, fmt.Errorf("thread %d not found", index))
+	}
+
+	return admin.json(w, threadState)
+}
+
+func (admin *FrankenPHPAdmin) terminate(w http.ResponseWriter, index int) error {
+	err := frankenphp.TerminateThreadRequest(index)
+	switch {
+	case errors.Is(err, frankenphp.ErrThreadNotFound):
+		return admin.error(http.StatusNotFound, err)
+	case errors.Is(err, frankenphp.ErrThreadIsIdle), errors.Is(err, frankenphp.ErrThreadNotInterruptible):
+		return admin.error(http.StatusConflict, err)
+	case errors.Is(err, frankenphp.ErrTerminationPending):
+		caddy.Log().Warn(fmt.Sprintf("termination of the request handled by thread %d requested from admin api", index))
+		w.WriteHeader(http.StatusAccepted)
+		_, err = w.Write([]byte(err.Error() + "\n"))
+
+		return err
+	case err != nil:
+		return admin.error(http.StatusInternalServerError, err)
+	}
+
+	caddy.Log().Warn(fmt.Sprintf("request handled by thread %d terminated from admin api", index))
+
+	return admin.success(w, "request terminated successfully\n")
+}
+
+// requests lists all requests currently handled by PHP threads
+func (admin *FrankenPHPAdmin) requests(w http.ResponseWriter, _ *http.Request) error {
+	return admin.json(w, frankenphp.InFlightRequests())
+}
+
+// health responds with 200 as long as the PHP runtime is running, without using a PHP thread
+func (admin *FrankenPHPAdmin) health(w http.ResponseWriter, _ *http.Request) error {
+	return writeHealthReport(w, frankenphp.Health(), false)
+}
+
+// ready responds with 200 only if FrankenPHP is able to handle requests right away
+func (admin *FrankenPHPAdmin) ready(w http.ResponseWriter, _ *http.Request) error {
+	return writeHealthReport(w, frankenphp.Health(), true)
+}
+
+// purgeCache removes the cached responses having any of the given tags: {"tags": ["product-1", "products"]}
+func (admin *FrankenPHPAdmin) purgeCache(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return admin.error(http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+	}
+
+	var body struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return admin.error(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+	}
+	if len(body.Tags) == 0 {
+		return admin.error(http.StatusBadRequest, fmt.Errorf("no tags to purge"))
+	}
+
+	purged := frankenphp.PurgeCacheTags(body.Tags...)
+	caddy.Log().Info(fmt.Sprintf("%d cached responses purged from admin api", purged))
+
+	return admin.json(w, map[string]int{"purged": purged})
+}
+
+func (admin *FrankenPHPAdmin) json(w http.ResponseWriter, v any) error {
+	prettyJson, err := json.MarshalIndent(v, "", "    ")
 	if err != nil {
 		return admin.error(http.StatusInternalServerError, err)
 	}
@@ -7,8 +7,10 @@ import (
 	"github.com/dunglas/frankenphp/internal/fastabs"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/caddyserver/caddy/v2/caddytest"
 	"github.com/dunglas/frankenphp"
@@ -297,3 +299,236 @@ func TestAddModuleWorkerViaAdminApi(t *testing.T) {
 	// Make a request to the worker to verify it's working
 	tester.AssertGetResponse("http://localhost:"+testPort+"/worker-with-counter.php", http.StatusOK, "requests:1")
 }
+
+func TestShowInFlightRequestsViaAdminApi(t *testing.T) {
+	tester := caddytest.NewTester(t)
+	tester.InitServer(`
+		{
+			skip_install_trust
+			admin localhost:2999
+			http_port `+testPort+`
+
+			frankenphp {
+				num_threads 2
+			}
+		}
+
+		localhost:`+testPort+` {
+			route {
+				root ../testdata
+				php
+			}
+		}
+		`, "caddyfile")
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		tester.AssertGetResponse("http://localhost:"+testPort+"/busy-loop.php?ms=2000", http.StatusOK, "done")
+		wg.Done()
+	}()
+
+	requests := waitForInFlightRequests(t, tester)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "GET", requests[0].Method)
+	assert.Equal(t, "/busy-loop.php?ms=2000", requests[0].URI)
+
+	var threadState frankenphp.ThreadDebugState
+	body := getAdminResponseBody(t, tester, "GET", fmt.Sprintf("threads/%d?stack", requests[0].ThreadIndex))
+	assert.NoError(t, json.Unmarshal([]byte(body), &threadState))
+	assert.NotNil(t, threadState.CurrentRequest)
+	assert.Greater(t, threadState.CurrentRequest.MemoryUsage, int64(0))
+	assert.Contains(t, threadState.CurrentRequest.Stack, "{main}")
+
+	assertAdminResponse(t, tester, "GET", "threads/999", http.StatusNotFound, "")
+
+	wg.Wait()
+}
+
+func TestTerminateRequestViaAdminApi(t *testing.T) {
+	tester := caddytest.NewTester(t)
+	tester.InitServer(`
+		{
+			skip_install_trust
+			admin localhost:2999
+			http_port `+testPort+`
+
+			frankenphp {
+				num_threads 2
+				worker ../testdata/busy-loop.php 1
+			}
+		}
+
+		localhost:`+testPort+` {
+			route {
+				root ../testdata
+				rewrite busy-loop.php
+				php
+			}
+		}
+		`, "caddyfile")
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		tester.AssertGetResponse("http://localhost:"+testPort+"/?ms=60000", http.StatusServiceUnavailable, "Service Unavailable")
+		wg.Done()
+	}()
+
+	requests := waitForInFlightRequests(t, tester)
+	assert.Len(t, requests, 1)
+
+	assertAdminResponse(t, tester, "POST", fmt.Sprintf("threads/%d/terminate", requests[0].ThreadIndex), http.StatusOK, "request terminated successfully\n")
+	wg.Wait()
+
+	// the worker script is restarted and handles requests again
+	tester.AssertGetResponse("http://localhost:"+testPort+"/?ms=1", http.StatusOK, "done")
+	assertAdminResponse(t, tester, "POST", fmt.Sprintf("threads/%d/terminate", requests[0].ThreadIndex), http.StatusConflict, "")
+}
+
+func waitForInFlightRequests(t *testing.T, tester *caddytest.Tester) []frankenphp.RequestDebugState {
+	t.Helper()
+
+	var requests []frankenphp.RequestDebugState
+	for range 100 {
+		err := json.Unmarshal([]byte(getAdminResponseBody(t, tester, "GET", "requests")), &requests)
+		assert.NoError(t, err)
+		if len(requests) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return requests
+}
+
+func TestHealthAndReadinessViaAdminApi(t *testing.T) {
+	tester := caddytest.NewTester(t)
+	tester.InitServer(`
+		{
+			skip_install_trust
+			admin localhost:2999
+			http_port `+testPort+`
+
+			frankenphp {
+				num_threads 2
+				readiness_max_queue_depth 1
+				worker ../testdata/busy-loop.php 1
+			}
+		}
+
+		localhost:`+testPort+` {
+			route {
+				frankenphp_health /healthz
+				frankenphp_health /readyz ready
+				root ../testdata
+				rewrite busy-loop.php
+				php
+			}
+		}
+		`, "caddyfile")
+
+	assertAdminResponse(t, tester, "GET", "health", http.StatusOK, "")
+	assertAdminResponse(t, tester, "GET", "ready", http.StatusOK, "")
+	assertHealthReport(t, tester, "http://localhost:"+testPort+"/readyz", http.StatusOK)
+
+	// occupy the only worker thread and queue a second request
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	for range 2 {
+		go func() {
+			tester.AssertGetResponse("http://localhost:"+testPort+"/?ms=500", http.StatusOK, "done")
+			wg.Done()
+		}()
+	}
+
+	var report frankenphp.HealthReport
+	for range 100 {
+		report = getHealthReport(t, tester, "http://localhost:2999/frankenphp/ready")
+		if !report.Ready {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.False(t, report.Ready)
+	assert.True(t, report.Healthy)
+	assert.Equal(t, 1, report.QueuedRequests)
+	assert.Equal(t, 1, report.Workers[0].QueuedRequests)
+
+	// the process is still healthy while saturated
+	assertHealthReport(t, tester, "http://localhost:"+testPort+"/healthz", http.StatusOK)
+	assertHealthReport(t, tester, "http://localhost:"+testPort+"/readyz", http.StatusServiceUnavailable)
+	wg.Wait()
+
+	assertAdminResponse(t, tester, "GET", "ready", http.StatusOK, "")
+}
+
+func getHealthReport(t *testing.T, tester *caddytest.Tester, url string) frankenphp.HealthReport {
+	t.Helper()
+	resp, err := tester.Client.Get(url)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	var report frankenphp.HealthReport
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+
+	return report
+}
+
+func assertHealthReport(t *testing.T, tester *caddytest.Tester, url string, expectedStatus int) {
+	t.Helper()
+	resp, err := tester.Client.Get(url)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, expectedStatus, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+}
+
+func TestPurgeCacheViaAdminApi(t *testing.T) {
+	tester := caddytest.NewTester(t)
+	tester.InitServer(`
+		{
+			skip_install_trust
+			admin localhost:2999
+			http_port `+testPort+`
+
+			frankenphp {
+				response_cache_size 1MiB
+			}
+		}
+
+		localhost:`+testPort+` {
+			root ../testdata
+			php_server
+		}
+		`, "caddyfile")
+
+	url := "http://localhost:" + testPort + "/response-cache.php?i=admin"
+	body, cacheStatus := getCachedResponse(t, tester, url)
+	assert.Equal(t, "FrankenPHP; fwd=uri-miss; stored", cacheStatus)
+
+	cached, cacheStatus := getCachedResponse(t, tester, url)
+	assert.Equal(t, body, cached)
+	assert.Contains(t, cacheStatus, "FrankenPHP; hit")
+
+	r, err := http.NewRequest("POST", "http://localhost:2999/frankenphp/cache/purge", strings.NewReader(`{"tags": ["tag-admin"]}`))
+	assert.NoError(t, err)
+	_, _ = tester.AssertResponse(r, http.StatusOK, "{\n    \"purged\": 1\n}")
+
+	_, cacheStatus = getCachedResponse(t, tester, url)
+	assert.Equal(t, "FrankenPHP; fwd=uri-miss; stored", cacheStatus)
+}
+
+func getCachedResponse(t *testing.T, tester *caddytest.Tester, url string) (string, string) {
+	t.Helper()
+	r, err := http.NewRequest("GET", url, nil)
+	assert.NoError(t, err)
+	resp := tester.AssertResponseCode(r, http.StatusOK)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	return string(body), resp.Header.Get("Cache-Status")
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/caddyserver/caddy/v2/modules/caddyhttp"
 	"github.com/dunglas/frankenphp"
 	"github.com/dunglas/frankenphp/internal/fastabs"
+	"github.com/dustin/go-humanize"
 )
 
 // FrankenPHPApp represents the global "frankenphp" directive in the Caddyfile
@@ -37,6 +38,10 @@ type FrankenPHPApp struct {
 	PhpIni map[string]string `json:"php_ini,omitempty"`
 	// The maximum amount of time a request may be stalled waiting for a thread
 	MaxWaitTime time.Duration `json:"max_wait_time,omitempty"`
+	// The number of queued requests from which FrankenPHP is reported as not ready. Default: the number of threads
+	ReadinessMaxQueueDepth int `json:"readiness_max_queue_depth,omitempty"`
+	// The maximum size in bytes of the in-memory cache of PHP responses. Default: 0 (disabled)
+	ResponseCacheSize int64 `json:"response_cache_size,omitempty"`
 
 	metrics frankenphp.Metrics
 	logger  *slog.Logger
@@ -117,6 +122,8 @@ func (f *FrankenPHPApp) Start() error {
 		frankenphp.WithMetrics(f.metrics),
 		frankenphp.WithPhpIni(f.PhpIni),
 		frankenphp.WithMaxWaitTime(f.MaxWaitTime),
+		frankenphp.WithReadinessMaxQueueDepth(f.ReadinessMaxQueueDepth),
+		frankenphp.WithResponseCache(f.ResponseCacheSize),
 	}
 	for _, w := range append(f.Workers) {
 		workerOpts := []frankenphp.WorkerOption{
@@ -150,6 +157,8 @@ func (f *FrankenPHPApp) Stop() error {
 	f.Workers = nil
 	f.NumThreads = 0
 	f.MaxWaitTime = 0
+	f.ReadinessMaxQueueDepth = 0
+	f.ResponseCacheSize = 0
 
 	return nil
 }
@@ -198,6 +207,28 @@ func (f *FrankenPHPApp) UnmarshalCaddyfile(d *caddyfile.Dispenser) error {
 				}
 
 				f.MaxWaitTime = v
+			case "readiness_max_queue_depth":
+				if !d.NextArg() {
+					return d.ArgErr()
+				}
+
+				v, err := strconv.ParseUint(d.Val(), 10, 32)
+				if err != nil {
+					return err
+				}
+
+				f.ReadinessMaxQueueDepth = int(v)
+			case "response_cache_size":
+				if !d.NextArg() {
+					return d.ArgErr()
+				}
+
+				v, err := humanize.ParseBytes(d.Val())
+				if err != nil {
+					return errors.New("response_cache_size must be a valid size (example: 64MiB)")
+				}
+
+				f.ResponseCacheSize = int64(v)
 			case "php_ini":
 				parseIniLine := func(d *caddyfile.Dispenser) error {
 					key := d.Val()
@@ -254,7 +285,7 @@ func (f *FrankenPHPApp) UnmarshalCaddyfile(d *caddyfile.Dispenser) error {
 
 				f.Workers = append(f.Workers, wc)
 			default:
-				allowedDirectives := "num_threads, max_threads, php_ini, worker, max_wait_time"
+				allowedDirectives := "num_threads, max_threads, php_ini, worker, max_wait_time, readiness_max_queue_depth, response_cache_size"
 				return wrongSubDirectiveError("frankenphp", allowedDirectives, d.Val())
 			}
 		}
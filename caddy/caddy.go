@@ -21,6 +21,7 @@ func init() {
 	caddy.RegisterModule(FrankenPHPApp{})
 	caddy.RegisterModule(FrankenPHPModule{})
 	caddy.RegisterModule(FrankenPHPAdmin{})
+	caddy.RegisterModule(FrankenPHPHealth{})
 
 	httpcaddyfile.RegisterGlobalOption("frankenphp", parseGlobalOption)
 
@@ -29,6 +30,9 @@ func init() {
 
 	httpcaddyfile.RegisterDirective("php_server", parsePhpServer)
 	httpcaddyfile.RegisterDirectiveOrder("php_server", "before", "file_server")
+
+	httpcaddyfile.RegisterHandlerDirective("frankenphp_health", parseHealthCaddyfile)
+	httpcaddyfile.RegisterDirectiveOrder("frankenphp_health", "before", "php")
 }
 
 // return a nice error message
@@ -43,4 +47,6 @@ var (
 	_ caddy.Provisioner           = (*FrankenPHPModule)(nil)
 	_ caddyhttp.MiddlewareHandler = (*FrankenPHPModule)(nil)
 	_ caddyfile.Unmarshaler       = (*FrankenPHPModule)(nil)
+	_ caddyhttp.MiddlewareHandler = (*FrankenPHPHealth)(nil)
+	_ caddyfile.Unmarshaler       = (*FrankenPHPHealth)(nil)
 )
@@ -3,6 +3,7 @@ package caddy_test
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -389,6 +390,39 @@ func TestPHPServerDirective(t *testing.T) {
 	tester.AssertGetResponse("http://localhost:"+testPort+"/not-found.txt", http.StatusOK, "I am by birth a Genevese (i not set)")
 }
 
+func TestPHPServerFrontController(t *testing.T) {
+	tester := caddytest.NewTester(t)
+	tester.InitServer(`
+		{
+			skip_install_trust
+			admin localhost:2999
+			http_port `+testPort+`
+			https_port 9443
+		}
+
+		localhost:`+testPort+` {
+			root ../testdata
+			php_server {
+				front_controller server-variable.php
+			}
+		}
+		`, "caddyfile")
+
+	tester.AssertGetResponse("http://localhost:"+testPort+"/hello.txt", http.StatusOK, "Hello")
+
+	for _, path := range []string{"/foo/bar", "/index.php/foo"} {
+		resp, err := tester.Client.Get("http://localhost:" + testPort + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		require.Contains(t, string(body), "[SCRIPT_NAME] => /server-variable.php")
+		require.Contains(t, string(body), "[PATH_INFO] => "+path)
+		require.Contains(t, string(body), "[REQUEST_URI] => "+path)
+	}
+}
+
 func TestPHPServerDirectiveDisableFileServer(t *testing.T) {
 	tester := caddytest.NewTester(t)
 	tester.InitServer(`
@@ -813,6 +847,45 @@ func TestAllDefinedServerVars(t *testing.T) {
 	)
 }
 
+func TestTrustedProxies(t *testing.T) {
+	tester := caddytest.NewTester(t)
+	tester.InitServer(`
+		{
+			skip_install_trust
+			admin localhost:2999
+			http_port `+testPort+`
+			servers {
+				trusted_proxies static private_ranges
+			}
+		}
+
+		localhost:`+testPort+` {
+			route {
+				root ../testdata
+				php
+			}
+		}
+		`, "caddyfile")
+
+	req, err := http.NewRequest("GET", "http://localhost:"+testPort+"/server-variable.php", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "example.com")
+
+	resp, err := tester.Client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Contains(t, string(body), "[REMOTE_ADDR] => 203.0.113.7")
+	require.Contains(t, string(body), "[HTTPS] => on")
+	require.Contains(t, string(body), "[REQUEST_SCHEME] => https")
+	require.Contains(t, string(body), "[SERVER_NAME] => example.com")
+	require.Contains(t, string(body), "[SERVER_PORT] => 443")
+}
+
 func TestPHPIniConfiguration(t *testing.T) {
 	tester := caddytest.NewTester(t)
 	tester.InitServer(`
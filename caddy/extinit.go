@@ -2,11 +2,16 @@ package caddy
 
 import (
 	"errors"
-	"github.com/dunglas/frankenphp/internal/extgen"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+
+	"github.com/dunglas/frankenphp/internal/extgen"
+	"github.com/dunglas/frankenphp/internal/watcher"
 
 	caddycmd "github.com/caddyserver/caddy/v2/cmd"
 	"github.com/spf13/cobra"
@@ -15,12 +20,17 @@ import (
 func init() {
 	caddycmd.RegisterCommand(caddycmd.Command{
 		Name:  "extension-init",
-		Usage: "go_extension.go [--verbose]",
-		Short: "(Experimental) Initializes a PHP extension from a Go file",
+		Usage: "go_extension.go|package_dir [--verbose] [--watch]",
+		Short: "(Experimental) Initializes a PHP extension from a Go file or package",
 		Long: `
-Initializes a PHP extension from a Go file. This command generates the necessary C files for the extension, including the header and source files, as well as the arginfo file.`,
+Initializes a PHP extension from a Go file or package. This command generates the necessary C files for the extension, including the header and source files, as well as the arginfo file.
+
+When a directory is given, all the files of the package it contains and of its subpackages are merged into a single extension.
+
+With --watch, the extension is regenerated every time the Go source changes. Only the files whose content changed are rewritten, and the code in the "USER CODE" regions of the generated files is preserved.`,
 		CobraFunc: func(cmd *cobra.Command) {
 			cmd.Flags().BoolP("debug", "v", false, "Enable verbose debug logs")
+			cmd.Flags().BoolP("watch", "w", false, "Regenerate the extension when the Go source changes")
 
 			cmd.RunE = caddycmd.WrapCommandFuncForCobra(cmdInitExtension)
 		},
@@ -28,17 +38,30 @@ Initializes a PHP extension from a Go file. This command generates the necessary
 }
 
 func cmdInitExtension(fs caddycmd.Flags) (int, error) {
-	if len(os.Args) < 3 {
+	if fs.NArg() < 1 {
 		return 1, errors.New("the path to the Go source is required")
 	}
 
-	sourceFile := os.Args[2]
+	sourceFile, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		return 1, err
+	}
+
+	info, err := os.Stat(sourceFile)
+	if err != nil {
+		return 1, err
+	}
 
 	baseName := strings.TrimSuffix(filepath.Base(sourceFile), ".go")
 
 	baseName = extgen.SanitizePackageName(baseName)
 
 	sourceDir := filepath.Dir(sourceFile)
+	watchPattern := sourceFile
+	if info.IsDir() {
+		sourceDir = sourceFile
+		watchPattern = filepath.Join(sourceDir, "**", "*.go")
+	}
 	buildDir := filepath.Join(sourceDir, "build")
 
 	generator := extgen.Generator{BaseName: baseName, SourceFile: sourceFile, BuildDir: buildDir}
@@ -49,5 +72,31 @@ func cmdInitExtension(fs caddycmd.Flags) (int, error) {
 
 	log.Printf("PHP extension %q initialized successfully in %q", baseName, generator.BuildDir)
 
+	if !fs.Bool("watch") {
+		return 0, nil
+	}
+
+	regenerate := func() {
+		if err := generator.Generate(); err != nil {
+			log.Printf("unable to regenerate the PHP extension %q: %v", baseName, err)
+
+			return
+		}
+
+		log.Printf("PHP extension %q regenerated", baseName)
+	}
+
+	// files written to the build directory also trigger a regeneration, which doesn't rewrite anything
+	if err := watcher.InitWatcher([]string{watchPattern}, regenerate, slog.Default()); err != nil {
+		return 1, err
+	}
+	defer watcher.DrainWatcher()
+
+	log.Printf("watching %q for changes, press Ctrl+C to stop", sourceFile)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+
 	return 0, nil
 }
@@ -13,6 +13,7 @@ require (
 	github.com/dunglas/frankenphp v1.9.1
 	github.com/dunglas/mercure/caddy v0.20.2
 	github.com/dunglas/vulcain/caddy v1.2.1
+	github.com/dustin/go-humanize v1.0.1
 	github.com/prometheus/client_golang v1.23.0
 	github.com/spf13/cobra v1.9.1
 	github.com/stretchr/testify v1.11.1
@@ -62,7 +63,6 @@ require (
 	github.com/dunglas/httpsfv v1.1.0 // indirect
 	github.com/dunglas/mercure v0.20.2 // indirect
 	github.com/dunglas/vulcain v1.2.1 // indirect
-	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/felixge/httpsnoop v1.0.4 // indirect
 	github.com/francoispqt/gojay v1.2.13 // indirect
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
@@ -0,0 +1,79 @@
+package caddy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/caddyserver/caddy/v2"
+	"github.com/caddyserver/caddy/v2/caddyconfig/caddyfile"
+	"github.com/caddyserver/caddy/v2/caddyconfig/httpcaddyfile"
+	"github.com/caddyserver/caddy/v2/modules/caddyhttp"
+	"github.com/dunglas/frankenphp"
+)
+
+// FrankenPHPHealth responds to health checks without using a PHP thread.
+//
+//	frankenphp_health /healthz
+//	frankenphp_health /readyz ready
+type FrankenPHPHealth struct {
+	// Ready reports readiness (all workers ready, no crash backoff, short queue) instead of liveness
+	Ready bool `json:"ready,omitempty"`
+}
+
+// CaddyModule returns the Caddy module information.
+func (FrankenPHPHealth) CaddyModule() caddy.ModuleInfo {
+	return caddy.ModuleInfo{
+		ID:  "http.handlers.frankenphp_health",
+		New: func() caddy.Module { return new(FrankenPHPHealth) },
+	}
+}
+
+// ServeHTTP implements caddyhttp.MiddlewareHandler.
+func (h *FrankenPHPHealth) ServeHTTP(w http.ResponseWriter, _ *http.Request, _ caddyhttp.Handler) error {
+	return writeHealthReport(w, frankenphp.Health(), h.Ready)
+}
+
+// UnmarshalCaddyfile implements caddyfile.Unmarshaler.
+func (h *FrankenPHPHealth) UnmarshalCaddyfile(d *caddyfile.Dispenser) error {
+	for d.Next() {
+		if !d.NextArg() {
+			continue
+		}
+		if d.Val() != "ready" {
+			return d.Errf("unknown frankenphp_health argument: '%s' (allowed argument is: ready)", d.Val())
+		}
+		h.Ready = true
+		if d.NextArg() {
+			return d.ArgErr()
+		}
+	}
+
+	return nil
+}
+
+func parseHealthCaddyfile(h httpcaddyfile.Helper) (caddyhttp.MiddlewareHandler, error) {
+	m := &FrankenPHPHealth{}
+	err := m.UnmarshalCaddyfile(h.Dispenser)
+
+	return m, err
+}
+
+// writeHealthReport responds with 503 Service Unavailable if FrankenPHP is not healthy (or not ready)
+func writeHealthReport(w http.ResponseWriter, report frankenphp.HealthReport, readiness bool) error {
+	status := http.StatusOK
+	if !report.Healthy || (readiness && !report.Ready) {
+		status = http.StatusServiceUnavailable
+	}
+
+	body, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+
+	return err
+}
@@ -1,14 +1,20 @@
 package caddy
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"weak"
 
 	"github.com/caddyserver/caddy/v2"
 	"github.com/caddyserver/caddy/v2/caddyconfig"
@@ -40,11 +46,20 @@ type FrankenPHPModule struct {
 	Env map[string]string `json:"env,omitempty"`
 	// Workers configures the worker scripts to start.
 	Workers []workerConfig `json:"workers,omitempty"`
+	// FrontController sets the script, relative to the root, handling all requests. The path is not split and is passed in PATH_INFO.
+	FrontController string `json:"front_controller,omitempty"`
+	// CoalesceRequests enables coalescing of identical GET requests, cacheable responses are shared between them.
+	CoalesceRequests bool `json:"coalesce_requests,omitempty"`
+	// CoalesceHeaders lists the request headers that must be identical for requests to be coalesced.
+	CoalesceHeaders []string `json:"coalesce_headers,omitempty"`
 
 	resolvedDocumentRoot        string
+	frontControllerOption       frankenphp.RequestOption
 	preparedEnv                 frankenphp.PreparedEnv
 	preparedEnvNeedsReplacement bool
 	logger                      *slog.Logger
+	// the TLS configurations selecting the connection policy of each server, see serverCertificate
+	serverTLSConfigs *sync.Map
 }
 
 // CaddyModule returns the Caddy module information.
@@ -58,6 +73,7 @@ func (FrankenPHPModule) CaddyModule() caddy.ModuleInfo {
 // Provision sets up the module.
 func (f *FrankenPHPModule) Provision(ctx caddy.Context) error {
 	f.logger = ctx.Slogger()
+	f.serverTLSConfigs = &sync.Map{}
 	app, err := ctx.App("frankenphp")
 	if err != nil {
 		return err
@@ -114,6 +130,10 @@ func (f *FrankenPHPModule) Provision(ctx caddy.Context) error {
 		f.ResolveRootSymlink = &rrs
 	}
 
+	if f.FrontController != "" {
+		f.frontControllerOption = frankenphp.WithRequestFrontController(f.FrontController)
+	}
+
 	if !needReplacement(f.Root) {
 		root, err := fastabs.FastAbs(f.Root)
 		if err != nil {
@@ -185,14 +205,35 @@ func (f *FrankenPHPModule) ServeHTTP(w http.ResponseWriter, r *http.Request, _ c
 		}
 	}
 
-	fr, err := frankenphp.NewRequestWithContext(
-		r,
+	opts := []frankenphp.RequestOption{
 		documentRootOption,
 		frankenphp.WithRequestSplitPath(f.SplitPath),
 		frankenphp.WithRequestPreparedEnv(env),
 		frankenphp.WithOriginalRequest(&origReq),
 		frankenphp.WithWorkerName(workerName),
-	)
+	}
+
+	if f.frontControllerOption != nil {
+		opts = append(opts, f.frontControllerOption)
+	}
+
+	if f.CoalesceRequests {
+		opts = append(opts, frankenphp.WithRequestCoalescing(f.CoalesceHeaders...))
+	}
+
+	if r.TLS != nil {
+		opts = append(opts, frankenphp.WithRequestServerCertificate(func() *x509.Certificate {
+			return f.serverCertificate(r)
+		}))
+	}
+
+	// the peer is listed in the trusted_proxies server option, rely on the client IP determined by Caddy
+	if trusted, _ := caddyhttp.GetVar(r.Context(), caddyhttp.TrustedProxyVarKey).(bool); trusted {
+		clientIP, _ := caddyhttp.GetVar(r.Context(), caddyhttp.ClientIPVarKey).(string)
+		opts = append(opts, frankenphp.WithRequestFromTrustedProxy(clientIP))
+	}
+
+	fr, err := frankenphp.NewRequestWithContext(r, opts...)
 
 	if err = frankenphp.ServeHTTP(w, fr); err != nil {
 		return caddyhttp.Error(http.StatusInternalServerError, err)
@@ -251,8 +292,21 @@ func (f *FrankenPHPModule) UnmarshalCaddyfile(d *caddyfile.Dispenser) error {
 				}
 				f.Workers = append(f.Workers, wc)
 
+			case "front_controller":
+				if !d.NextArg() {
+					return d.ArgErr()
+				}
+				f.FrontController = d.Val()
+				if d.NextArg() {
+					return d.ArgErr()
+				}
+
+			case "coalesce_requests":
+				f.CoalesceRequests = true
+				f.CoalesceHeaders = d.RemainingArgs()
+
 			default:
-				allowedDirectives := "root, split, env, resolve_root_symlink, worker"
+				allowedDirectives := "root, split, env, resolve_root_symlink, worker, front_controller, coalesce_requests"
 				return wrongSubDirectiveError("php or php_server", allowedDirectives, d.Val())
 			}
 		}
@@ -424,6 +478,14 @@ func parsePhpServer(h httpcaddyfile.Helper) ([]httpcaddyfile.ConfigValue, error)
 	// set up a route list that we'll append to
 	routes := caddyhttp.RouteList{}
 
+	if phpsrv.FrontController != "" {
+		if len(tryFiles) > 0 {
+			return nil, h.Err(`"try_files" and "front_controller" cannot be used together`)
+		}
+
+		return wrapPhpServerRoutes(h, userMatcherSet, frontControllerRoutes(h, phpsrv, fsrv, disableFsrv)), nil
+	}
+
 	// prepend routes from the 'worker match *' directives
 	routes = prependWorkerRoutes(routes, h, phpsrv, fsrv, disableFsrv)
 
@@ -530,6 +592,39 @@ func parsePhpServer(h httpcaddyfile.Helper) ([]httpcaddyfile.ConfigValue, error)
 		routes = append(routes, fileRoute)
 	}
 
+	return wrapPhpServerRoutes(h, userMatcherSet, routes), nil
+}
+
+// frontControllerRoutes sends all requests to the front controller, except for existing static files
+func frontControllerRoutes(h httpcaddyfile.Helper, phpsrv FrankenPHPModule, fsrv caddy.Module, disableFsrv bool) caddyhttp.RouteList {
+	routes := caddyhttp.RouteList{}
+
+	if !disableFsrv {
+		routes = append(routes, caddyhttp.Route{
+			MatcherSetsRaw: []caddy.ModuleMap{
+				{
+					"file": h.JSON(fileserver.MatchFile{
+						TryFiles: []string{"{http.request.uri.path}"},
+						Root:     phpsrv.Root,
+					}),
+					"not": h.JSON(caddyhttp.MatchNot{
+						MatcherSetsRaw: []caddy.ModuleMap{
+							{"path": h.JSON(caddyhttp.MatchPath{"*.php"})},
+						},
+					}),
+				},
+			},
+			HandlersRaw: []json.RawMessage{caddyconfig.JSONModuleObject(fsrv, "handler", "file_server", nil)},
+		})
+	}
+
+	return append(routes, caddyhttp.Route{
+		HandlersRaw: []json.RawMessage{caddyconfig.JSONModuleObject(phpsrv, "handler", "php", nil)},
+	})
+}
+
+// wrapPhpServerRoutes groups the routes generated by the php_server directive
+func wrapPhpServerRoutes(h httpcaddyfile.Helper, userMatcherSet caddy.ModuleMap, routes caddyhttp.RouteList) []httpcaddyfile.ConfigValue {
 	subroute := caddyhttp.Subroute{
 		Routes: routes,
 	}
@@ -545,7 +640,7 @@ func parsePhpServer(h httpcaddyfile.Helper) ([]httpcaddyfile.ConfigValue, error)
 					HandlersRaw:    []json.RawMessage{caddyconfig.JSONModuleObject(subroute, "handler", "subroute", nil)},
 				},
 			},
-		}, nil
+		}
 	}
 
 	// otherwise, return the literal subroute instead of
@@ -557,7 +652,7 @@ func parsePhpServer(h httpcaddyfile.Helper) ([]httpcaddyfile.ConfigValue, error)
 			Class: "route",
 			Value: subroute,
 		},
-	}, nil
+	}
 }
 
 // workers can also match a path without being in the public directory
@@ -608,3 +703,70 @@ func prependWorkerRoutes(routes caddyhttp.RouteList, h httpcaddyfile.Helper, f F
 
 	return routes
 }
+
+// connCertificates stores the leaf certificate presented on each TLS connection,
+// entries are removed when the connection is garbage collected
+var connCertificates sync.Map // map[weak.Pointer[tls.Conn]]*x509.Certificate
+
+// serverCertificate returns the leaf certificate presented to the client,
+// it is resolved once per connection.
+func (f *FrankenPHPModule) serverCertificate(r *http.Request) *x509.Certificate {
+	conn, ok := r.Context().Value(caddyhttp.ConnCtxKey).(net.Conn)
+	if !ok {
+		return nil
+	}
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return f.resolveServerCertificate(r, conn)
+	}
+
+	key := weak.Make(tlsConn)
+	if cert, ok := connCertificates.Load(key); ok {
+		return cert.(*x509.Certificate)
+	}
+
+	cert := f.resolveServerCertificate(r, conn)
+	if _, loaded := connCertificates.LoadOrStore(key, cert); !loaded {
+		runtime.AddCleanup(tlsConn, func(key weak.Pointer[tls.Conn]) {
+			connCertificates.Delete(key)
+		}, key)
+	}
+
+	return cert
+}
+
+// resolveServerCertificate selects the certificate like the TLS handshake did:
+// the first connection policy of the server matching the ClientHello provides it.
+func (f *FrankenPHPModule) resolveServerCertificate(r *http.Request, conn net.Conn) *x509.Certificate {
+	srv, ok := r.Context().Value(caddyhttp.ServerCtxKey).(*caddyhttp.Server)
+	if !ok || f.serverTLSConfigs == nil {
+		return nil
+	}
+
+	tlsConfig, ok := f.serverTLSConfigs.Load(srv)
+	if !ok {
+		// an empty context prevents the side effects related to ECH, only the policy matching is needed
+		tlsConfig, _ = f.serverTLSConfigs.LoadOrStore(srv, srv.TLSConnPolicies.TLSConfig(caddy.Context{}))
+	}
+
+	hello := &tls.ClientHelloInfo{ServerName: r.TLS.ServerName, Conn: conn}
+	pol, err := tlsConfig.(*tls.Config).GetConfigForClient(hello)
+	if err != nil || pol == nil || pol.GetCertificate == nil {
+		return nil
+	}
+
+	cert, err := pol.GetCertificate(hello)
+	if err != nil || cert == nil || len(cert.Certificate) == 0 {
+		return nil
+	}
+
+	if cert.Leaf != nil {
+		return cert.Leaf
+	}
+	if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil {
+		return leaf
+	}
+
+	return nil
+}
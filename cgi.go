@@ -13,7 +13,10 @@ package frankenphp
 import "C"
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"net"
+	"net/http"
 	"path/filepath"
 	"strings"
 	"unsafe"
@@ -60,8 +63,21 @@ var knownServerKeys = []string{
 	"REMOTE_USER",
 	"REQUEST_METHOD",
 	"REQUEST_URI",
+	"SSL_CLIENT_VERIFY",
+	"SSL_CLIENT_S_DN",
+	"SSL_CLIENT_I_DN",
+	"SSL_CLIENT_M_SERIAL",
+	"SSL_CLIENT_V_START",
+	"SSL_CLIENT_V_END",
+	"SSL_CLIENT_CERT",
+	"SSL_TLS_SNI",
+	"SSL_TLS_ALPN",
+	"SSL_SERVER_S_DN",
 }
 
+// certificate validity dates, in OpenSSL format
+const sslTimeFormat = "Jan _2 15:04:05 2006 GMT"
+
 // computeKnownVariables returns a set of CGI environment variables for the request.
 //
 // TODO: handle this case https://github.com/caddyserver/caddy/issues/3718
@@ -115,6 +131,33 @@ func addKnownVariablesToServer(thread *phpThread, fc *frankenPHPContext, trackVa
 		reqHost = request.Host
 	}
 
+	// the request has been forwarded by a trusted reverse proxy
+	if f := fc.forwarded; f != nil {
+		if f.clientIP != "" {
+			ip = f.clientIP
+			port = f.clientPort
+		}
+
+		if f.scheme != "" && f.scheme != rs {
+			rs = f.scheme
+			if rs == "https" {
+				https = "on"
+			} else {
+				https = ""
+			}
+		}
+
+		if f.host != "" {
+			reqHost = f.host
+			reqPort = f.port
+		} else if f.port != "" {
+			reqPort = f.port
+		} else if f.scheme != "" {
+			// the port of the request received by the proxy is unknown, use the default one
+			reqPort = ""
+		}
+	}
+
 	if reqPort == "" {
 		// compliance with the CGI specification requires that
 		// the SERVER_PORT variable MUST be set to the TCP/IP port number on which this request is received from the client
@@ -131,12 +174,7 @@ func addKnownVariablesToServer(thread *phpThread, fc *frankenPHPContext, trackVa
 	serverPort := reqPort
 	contentLength := request.Header.Get("Content-Length")
 
-	var requestURI string
-	if fc.originalRequest != nil {
-		requestURI = fc.originalRequest.URL.RequestURI()
-	} else {
-		requestURI = request.URL.RequestURI()
-	}
+	requestURI := fc.requestURI()
 
 	C.frankenphp_register_bulk(
 		trackVarsArray,
@@ -182,6 +220,115 @@ func addKnownVariablesToServer(thread *phpThread, fc *frankenPHPContext, trackVa
 	)
 }
 
+// addTLSVariablesToServer registers the client certificate and connection details in a manner compatible with apache's mod_ssl.
+//
+// Like other variables, they are only computed when the script uses $_SERVER (auto_globals_jit),
+// and the certificate variables are only computed if the client sent a certificate.
+func addTLSVariablesToServer(fc *frankenPHPContext, trackVarsArray *C.zval) {
+	state := fc.request.TLS
+	if state == nil {
+		return
+	}
+
+	keys := mainThread.knownServerKeys
+	registerVariable := func(key string, value string) {
+		C.frankenphp_register_single(keys[key], toUnsafeChar(value), C.size_t(len(value)), trackVarsArray)
+	}
+
+	if state.ServerName != "" {
+		registerVariable("SSL_TLS_SNI", state.ServerName)
+	}
+	if state.NegotiatedProtocol != "" {
+		registerVariable("SSL_TLS_ALPN", state.NegotiatedProtocol)
+	}
+	if cert := serverCertificate(fc, state); cert != nil {
+		registerVariable("SSL_SERVER_S_DN", cert.Subject.String())
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		registerVariable("SSL_CLIENT_VERIFY", "NONE")
+
+		return
+	}
+
+	if len(state.VerifiedChains) > 0 {
+		registerVariable("SSL_CLIENT_VERIFY", "SUCCESS")
+	} else {
+		// the certificate was requested but not verified (e.g. RequireAnyClientCert)
+		registerVariable("SSL_CLIENT_VERIFY", "GENEROUS")
+	}
+
+	cert := state.PeerCertificates[0]
+	registerVariable("SSL_CLIENT_S_DN", cert.Subject.String())
+	registerVariable("SSL_CLIENT_I_DN", cert.Issuer.String())
+	registerVariable("SSL_CLIENT_M_SERIAL", strings.ToUpper(cert.SerialNumber.Text(16)))
+	registerVariable("SSL_CLIENT_V_START", cert.NotBefore.UTC().Format(sslTimeFormat))
+	registerVariable("SSL_CLIENT_V_END", cert.NotAfter.UTC().Format(sslTimeFormat))
+	registerVariable("SSL_CLIENT_CERT", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+}
+
+// serverCertificate returns the leaf certificate presented to the client.
+//
+// Go's TLS stack doesn't expose the certificate selected for the connection,
+// so the selection is done again from the TLS configuration of the server.
+func serverCertificate(fc *frankenPHPContext, state *tls.ConnectionState) *x509.Certificate {
+	if fc.serverCertificate != nil {
+		return fc.serverCertificate()
+	}
+
+	srv, ok := fc.request.Context().Value(http.ServerContextKey).(*http.Server)
+	if !ok || srv.TLSConfig == nil {
+		return nil
+	}
+
+	config := srv.TLSConfig
+	hello := &tls.ClientHelloInfo{ServerName: state.ServerName}
+	if config.GetConfigForClient != nil {
+		if c, err := config.GetConfigForClient(hello); err == nil && c != nil {
+			config = c
+		}
+	}
+
+	var cert *tls.Certificate
+	if config.GetCertificate != nil {
+		cert, _ = config.GetCertificate(hello)
+	}
+	if cert == nil {
+		cert = matchingCertificate(config.Certificates, state.ServerName)
+	}
+	if cert == nil || len(cert.Certificate) == 0 {
+		return nil
+	}
+
+	if cert.Leaf != nil {
+		return cert.Leaf
+	}
+	leaf, _ := x509.ParseCertificate(cert.Certificate[0])
+
+	return leaf
+}
+
+// matchingCertificate mimics crypto/tls: the first certificate valid for the server name, or the first one.
+func matchingCertificate(certs []tls.Certificate, serverName string) *tls.Certificate {
+	if len(certs) == 0 {
+		return nil
+	}
+
+	if serverName != "" {
+		for i := range certs {
+			leaf := certs[i].Leaf
+			if leaf == nil && len(certs[i].Certificate) > 0 {
+				leaf, _ = x509.ParseCertificate(certs[i].Certificate[0])
+			}
+			if leaf != nil && leaf.VerifyHostname(serverName) == nil {
+				return &certs[i]
+			}
+		}
+	}
+
+	return &certs[0]
+}
+
 func packCgiVariable(key *C.zend_string, value string) C.ht_key_value_pair {
 	return C.ht_key_value_pair{key, toUnsafeChar(value), C.size_t(len(value))}
 }
@@ -215,6 +362,7 @@ func go_register_variables(threadIndex C.uintptr_t, trackVarsArray *C.zval) {
 	fc := thread.getRequestContext()
 
 	addKnownVariablesToServer(thread, fc, trackVarsArray)
+	addTLSVariablesToServer(fc, trackVarsArray)
 	addHeadersToServer(fc, trackVarsArray)
 
 	// The Prepared Environment is registered last and can overwrite any previous values
@@ -0,0 +1,138 @@
+package frankenphp
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// the maximum size of a response shared between coalesced requests, larger responses aren't buffered
+const maxCoalescedBodySize = 8 << 20
+
+var (
+	coalescedCallsMu sync.Mutex
+	coalescedCalls   = make(map[string]*coalescedCall)
+)
+
+// coalescedCall is a script execution shared by identical requests
+type coalescedCall struct {
+	done chan struct{}
+	// the response to send to the waiting requests, nil if it can't be shared
+	response *cachedResponse
+}
+
+// WithRequestCoalescing enables request coalescing for GET requests.
+//
+// While a script handles a request, identical requests (same method, URI and values of the given headers)
+// wait for it to finish instead of using other PHP threads. If the response is cacheable
+// (see WithResponseCache), it is sent to all waiting requests, otherwise they are handled as usual.
+// The headers the response depends on, such as Cookie or Accept-Language, must be listed.
+func WithRequestCoalescing(headers ...string) RequestOption {
+	return func(o *frankenPHPContext) error {
+		o.coalesce = true
+		o.coalesceHeaders = headers
+
+		return nil
+	}
+}
+
+// coalesceKey identifies identical requests
+func (fc *frankenPHPContext) coalesceKey() string {
+	var b strings.Builder
+	b.WriteString(fc.request.Method + " " + cacheURL(fc))
+	for _, name := range fc.coalesceHeaders {
+		b.WriteString("\n" + name + ":" + strings.Join(fc.request.Header.Values(name), ","))
+	}
+
+	return b.String()
+}
+
+// serveCoalesced waits for an identical request being handled and sends its response,
+// it returns false if the request must be handled by PHP
+func (fc *frankenPHPContext) serveCoalesced() bool {
+	if !fc.coalesce || fc.request.Method != http.MethodGet {
+		return false
+	}
+
+	call := fc.joinCoalescedCall(fc.coalesceKey())
+	if call == nil {
+		return false
+	}
+
+	select {
+	case <-call.done:
+	case <-fc.request.Context().Done():
+		// the client is gone, nothing to send
+		return true
+	}
+
+	// the response can't be shared, the request is handled by PHP right away
+	if call.response == nil {
+		return false
+	}
+
+	if fc.cacheRecorder != nil {
+		// the response has already been stored by the first request, don't store it again
+		fc.responseWriter = fc.cacheRecorder.ResponseWriter
+		fc.cacheRecorder = nil
+	}
+
+	fc.writeCachedResponse(call.response, "FrankenPHP; fwd=uri-miss; collapsed")
+
+	return true
+}
+
+// joinCoalescedCall returns the call handling an identical request,
+// or nil if this request is the first one and must be handled by PHP
+func (fc *frankenPHPContext) joinCoalescedCall(key string) *coalescedCall {
+	coalescedCallsMu.Lock()
+	defer coalescedCallsMu.Unlock()
+
+	if call, ok := coalescedCalls[key]; ok {
+		return call
+	}
+
+	// this request is the first one, the others will wait for its response
+	fc.coalescedCall = &coalescedCall{done: make(chan struct{})}
+	fc.coalescedKey = key
+	coalescedCalls[key] = fc.coalescedCall
+
+	if fc.cacheRecorder == nil {
+		fc.cacheRecorder = &cacheRecorder{ResponseWriter: fc.responseWriter, maxBodySize: maxCoalescedBodySize, authorized: isAuthorizedRequest(fc.request)}
+		fc.responseWriter = fc.cacheRecorder
+	}
+
+	return nil
+}
+
+// finishCoalescedCall releases the waiting requests, the response is sent to them if completed is true
+func (fc *frankenPHPContext) finishCoalescedCall(completed bool) {
+	coalescedCallsMu.Lock()
+	delete(coalescedCalls, fc.coalescedKey)
+	coalescedCallsMu.Unlock()
+
+	if !completed {
+		close(fc.coalescedCall.done)
+
+		return
+	}
+
+	// the response may be truncated if the client disconnected
+	if response := fc.cacheRecorder.response(); response != nil && !fc.clientHasClosed() && fc.variesOnCoalesceHeaders(response) {
+		fc.coalescedCall.response = response
+	}
+
+	close(fc.coalescedCall.done)
+}
+
+// variesOnCoalesceHeaders checks that the response doesn't depend on headers ignored when coalescing requests
+func (fc *frankenPHPContext) variesOnCoalesceHeaders(response *cachedResponse) bool {
+	for _, name := range varyHeaders(response.header) {
+		if !slices.ContainsFunc(fc.coalesceHeaders, func(h string) bool { return strings.EqualFold(h, name) }) {
+			return false
+		}
+	}
+
+	return true
+}
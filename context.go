@@ -2,6 +2,8 @@ package frankenphp
 
 import (
 	"context"
+	"crypto/x509"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
@@ -25,6 +27,40 @@ type frankenPHPContext struct {
 	scriptName     string
 	scriptFilename string
 
+	// client information reported by a trusted reverse proxy
+	forwarded *forwardedInfo
+	// returns the certificate presented to the client, if set
+	serverCertificate func() *x509.Certificate
+
+	frontController *frontController
+	tryFiles        []string
+
+	responseFilters []ResponseFilter
+	// the writer receiving the output of the script, if replaced by a response filter
+	bodyWriter io.Writer
+
+	// the route used to cache the links sent as automatic early hints
+	earlyHintsRoute string
+	// the links already sent as early hints, to not send them twice
+	sentEarlyHints map[string]struct{}
+
+	// captures the response to store it in the cache, if the request is cacheable
+	cacheRecorder *cacheRecorder
+	// true if the request refreshes a stale cached response in the background
+	revalidating bool
+
+	coalesce        bool
+	coalesceHeaders []string
+	// the execution shared with identical requests, if this request is the first one
+	coalescedCall *coalescedCall
+	coalescedKey  string
+
+	sendfileRoot string
+	// the file to send instead of the output of the script, if any
+	sendfile *sendfileResponse
+	// true if none of the tryFiles exists
+	notFound bool
+
 	// Whether the request is already closed by us
 	isDone bool
 
@@ -71,15 +107,21 @@ func NewRequestWithContext(r *http.Request, opts ...RequestOption) (*http.Reques
 	// If a worker is already assigned explicitly, use its filename and skip parsing path variables
 	if fc.worker != nil {
 		fc.scriptFilename = fc.worker.fileName
+	} else if fc.frontController != nil {
+		fc.frontController.apply(fc)
 	} else {
+		if fc.tryFiles != nil {
+			resolveTryFiles(fc)
+		}
+
 		// If no worker was assigned, split the path into the "traditional" CGI path variables.
 		// This needs to already happen here in case a worker script still matches the path.
 		splitCgiPath(fc)
 	}
 
-	c := context.WithValue(r.Context(), contextKey, fc)
+	c := context.WithValue(fc.request.Context(), contextKey, fc)
 
-	return r.WithContext(c), nil
+	return fc.request.WithContext(c), nil
 }
 
 // newDummyContext creates a fake context from a request path
@@ -105,12 +147,19 @@ func (fc *frankenPHPContext) closeContext() {
 		return
 	}
 
+	fc.closeBodyWriter()
 	close(fc.done)
 	fc.isDone = true
 }
 
 // validate checks if the request should be outright rejected
 func (fc *frankenPHPContext) validate() bool {
+	if fc.notFound {
+		fc.reject(http.StatusNotFound, "Not Found")
+
+		return false
+	}
+
 	if strings.Contains(fc.request.URL.Path, "\x00") {
 		fc.rejectBadRequest("Invalid request path")
 
@@ -129,6 +178,15 @@ func (fc *frankenPHPContext) validate() bool {
 	return true
 }
 
+// requestURI returns the URI of the original request, as exposed in $_SERVER['REQUEST_URI']
+func (fc *frankenPHPContext) requestURI() string {
+	if fc.originalRequest != nil {
+		return fc.originalRequest.URL.RequestURI()
+	}
+
+	return fc.request.URL.RequestURI()
+}
+
 func (fc *frankenPHPContext) clientHasClosed() bool {
 	select {
 	case <-fc.request.Context().Done():
@@ -144,6 +202,9 @@ func (fc *frankenPHPContext) reject(statusCode int, message string) {
 		return
 	}
 
+	// the error response replaces the file requested with X-Sendfile
+	fc.sendfile = nil
+
 	rw := fc.responseWriter
 	if rw != nil {
 		rw.WriteHeader(statusCode)
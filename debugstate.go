@@ -1,5 +1,9 @@
 package frankenphp
 
+import (
+	"time"
+)
+
 // EXPERIMENTAL: ThreadDebugState prints the state of a single PHP thread - debugging purposes only
 type ThreadDebugState struct {
 	Index                    int
@@ -8,6 +12,7 @@ type ThreadDebugState struct {
 	IsWaiting                bool
 	IsBusy                   bool
 	WaitingSinceMilliseconds int64
+	CurrentRequest           *RequestDebugState `json:",omitempty"`
 }
 
 // EXPERIMENTAL: FrankenPHPDebugState prints the state of all PHP threads - debugging purposes only
@@ -16,6 +21,21 @@ type FrankenPHPDebugState struct {
 	ReservedThreadCount int
 }
 
+// EXPERIMENTAL: RequestDebugState prints the request currently handled by a PHP thread - debugging purposes only
+//
+// MemoryUsage, PeakMemoryUsage and Stack are only available when inspecting a single thread.
+type RequestDebugState struct {
+	ThreadIndex         int
+	Method              string
+	URI                 string
+	RemoteAddr          string
+	Worker              string `json:",omitempty"`
+	ElapsedMilliseconds int64
+	MemoryUsage         int64  `json:",omitempty"`
+	PeakMemoryUsage     int64  `json:",omitempty"`
+	Stack               string `json:",omitempty"`
+}
+
 // EXPERIMENTAL: DebugState prints the state of all PHP threads - debugging purposes only
 func DebugState() FrankenPHPDebugState {
 	fullState := FrankenPHPDebugState{
@@ -33,6 +53,42 @@ func DebugState() FrankenPHPDebugState {
 	return fullState
 }
 
+// EXPERIMENTAL: ThreadDebugStateByIndex prints the state of a single PHP thread - debugging purposes only
+//
+// If the thread is handling a request, the running script is briefly interrupted to collect
+// its memory usage and, if withStack is true, its current PHP stack.
+func ThreadDebugStateByIndex(index int, withStack bool) (ThreadDebugState, bool) {
+	if index < 0 || index >= len(phpThreads) {
+		return ThreadDebugState{}, false
+	}
+
+	thread := phpThreads[index]
+	state := threadDebugState(thread)
+	if state.CurrentRequest == nil {
+		return state, true
+	}
+
+	if snapshot, ok := thread.requestSnapshot(withStack); ok {
+		state.CurrentRequest.MemoryUsage = snapshot.memoryUsage
+		state.CurrentRequest.PeakMemoryUsage = snapshot.peakMemoryUsage
+		state.CurrentRequest.Stack = snapshot.stack
+	}
+
+	return state, true
+}
+
+// EXPERIMENTAL: InFlightRequests prints all requests currently handled by PHP threads - debugging purposes only
+func InFlightRequests() []RequestDebugState {
+	requests := make([]RequestDebugState, 0)
+	for _, thread := range phpThreads {
+		if r := requestDebugState(thread); r != nil {
+			requests = append(requests, *r)
+		}
+	}
+
+	return requests
+}
+
 // threadDebugState creates a small jsonable status message for debugging purposes
 func threadDebugState(thread *phpThread) ThreadDebugState {
 	return ThreadDebugState{
@@ -42,5 +98,27 @@ func threadDebugState(thread *phpThread) ThreadDebugState {
 		IsWaiting:                thread.state.isInWaitingState(),
 		IsBusy:                   !thread.state.isInWaitingState(),
 		WaitingSinceMilliseconds: thread.state.waitTime(),
+		CurrentRequest:           requestDebugState(thread),
+	}
+}
+
+// requestDebugState describes the request currently handled by the thread, if any
+func requestDebugState(thread *phpThread) *RequestDebugState {
+	fc := thread.activeRequest.Load()
+	if fc == nil {
+		return nil
+	}
+
+	r := &RequestDebugState{
+		ThreadIndex:         thread.threadIndex,
+		Method:              fc.request.Method,
+		URI:                 fc.requestURI(),
+		RemoteAddr:          fc.request.RemoteAddr,
+		ElapsedMilliseconds: time.Since(fc.startedAt).Milliseconds(),
+	}
+	if fc.worker != nil {
+		r.Worker = fc.worker.name
 	}
+
+	return r
 }
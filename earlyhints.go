@@ -0,0 +1,375 @@
+package frankenphp
+
+import (
+	"container/list"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// relations worth sending as early hints
+var earlyHintsRelations = []string{"preload", "modulepreload", "preconnect"}
+
+// the maximum number of routes in the cache, this is a totally arbitrary value
+const earlyHintsCacheSize = 4096
+
+var (
+	earlyHintsCache = newEarlyHintsCache(earlyHintsCacheSize)
+
+	errInvalidLink = errors.New("invalid link")
+
+	quotedStringReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// linksCache is an LRU cache of the early hints links of routes
+type linksCache struct {
+	mu      sync.Mutex
+	maxLen  int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type linksCacheEntry struct {
+	route string
+	links []string
+}
+
+func newEarlyHintsCache(maxLen int) *linksCache {
+	return &linksCache{
+		maxLen:  maxLen,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the links cached for the route
+func (c *linksCache) get(route string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[route]
+	if !ok {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(e)
+
+	return e.Value.(*linksCacheEntry).links, true
+}
+
+// set caches the links of the route, evicting the least recently used route if the cache is full
+func (c *linksCache) set(route string, links []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[route]; ok {
+		e.Value.(*linksCacheEntry).links = links
+		c.lru.MoveToFront(e)
+
+		return
+	}
+
+	for c.lru.Len() >= c.maxLen {
+		back := c.lru.Back()
+		c.lru.Remove(back)
+		delete(c.entries, back.Value.(*linksCacheEntry).route)
+	}
+
+	c.entries[route] = c.lru.PushFront(&linksCacheEntry{route: route, links: links})
+}
+
+// delete removes the links cached for the route
+func (c *linksCache) delete(route string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[route]; ok {
+		c.lru.Remove(e)
+		delete(c.entries, route)
+	}
+}
+
+// WithRequestAutomaticEarlyHints enables automatic 103 Early Hints for the route.
+//
+// The preload and preconnect links sent in the Link headers of the last successful response
+// for the route are sent as early hints before the script is executed, no PHP code change is needed.
+// If route is empty, the path of the request is used.
+func WithRequestAutomaticEarlyHints(route string) RequestOption {
+	return func(o *frankenPHPContext) error {
+		if route == "" {
+			route = o.request.URL.Path
+		}
+
+		o.earlyHintsRoute = route
+
+		return nil
+	}
+}
+
+// sendEarlyHints sends a 103 response containing the links that haven't been sent yet for this request
+func (fc *frankenPHPContext) sendEarlyHints(links []string) {
+	if fc.sentEarlyHints == nil {
+		fc.sentEarlyHints = make(map[string]struct{}, len(links))
+	}
+
+	var toSend []string
+	for _, link := range links {
+		if _, ok := fc.sentEarlyHints[link]; ok {
+			continue
+		}
+
+		fc.sentEarlyHints[link] = struct{}{}
+		toSend = append(toSend, link)
+	}
+
+	if len(toSend) == 0 {
+		return
+	}
+
+	// headers already set by other handlers must only be sent with the final response
+	h := fc.responseWriter.Header()
+	previous := h.Clone()
+	clear(h)
+
+	h["Link"] = toSend
+	fc.responseWriter.WriteHeader(http.StatusEarlyHints)
+
+	clear(h)
+	for k, v := range previous {
+		h[k] = v
+	}
+}
+
+// markEarlyHintsAsSent records the links sent by the script with headers_send(103)
+func (fc *frankenPHPContext) markEarlyHintsAsSent() {
+	if fc.sentEarlyHints == nil {
+		fc.sentEarlyHints = make(map[string]struct{})
+	}
+
+	for _, value := range fc.responseWriter.Header().Values("Link") {
+		for _, link := range splitLinks(value) {
+			fc.sentEarlyHints[link] = struct{}{}
+		}
+	}
+}
+
+// sendAutomaticEarlyHints sends the links cached for the route of the request
+func (fc *frankenPHPContext) sendAutomaticEarlyHints() {
+	if fc.earlyHintsRoute == "" {
+		return
+	}
+
+	if links, ok := earlyHintsCache.get(fc.earlyHintsRoute); ok {
+		fc.sendEarlyHints(links)
+	}
+}
+
+// cacheEarlyHints stores the links of a successful response to send them as early hints for the next requests
+func (fc *frankenPHPContext) cacheEarlyHints(status int) {
+	if fc.earlyHintsRoute == "" || status < 200 || status >= 300 {
+		return
+	}
+
+	var links []string
+	for _, value := range fc.responseWriter.Header().Values("Link") {
+		for _, link := range splitLinks(value) {
+			if isEarlyHint(link) {
+				links = append(links, link)
+			}
+		}
+	}
+
+	if len(links) == 0 {
+		earlyHintsCache.delete(fc.earlyHintsRoute)
+
+		return
+	}
+
+	earlyHintsCache.set(fc.earlyHintsRoute, links)
+}
+
+// splitLinks splits a Link header value into link values, commas in URLs and quoted strings are preserved
+func splitLinks(value string) []string {
+	var (
+		links    []string
+		inURL    bool
+		inQuotes bool
+		start    int
+	)
+
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case inQuotes && c == '\\':
+			i++
+		case c == '"' && !inURL:
+			inQuotes = !inQuotes
+		case c == '<' && !inQuotes:
+			inURL = true
+		case c == '>' && !inQuotes:
+			inURL = false
+		case c == ',' && !inURL && !inQuotes:
+			if link := strings.TrimSpace(value[start:i]); link != "" {
+				links = append(links, link)
+			}
+			start = i + 1
+		}
+	}
+
+	if link := strings.TrimSpace(value[start:]); link != "" {
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// isEarlyHint checks if the relation type of a link is useful before the final response
+func isEarlyHint(link string) bool {
+	end := strings.IndexByte(link, '>')
+	if end == -1 {
+		return false
+	}
+
+	for _, param := range strings.Split(link[end+1:], ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+			continue
+		}
+
+		for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+			for _, r := range earlyHintsRelations {
+				if strings.EqualFold(rel, r) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// formatLinks builds the Link header values passed to frankenphp_early_hints().
+//
+// Entries are either URLs (sent with rel=preload), already formatted link values,
+// or URLs as keys and arrays of attributes as values.
+func formatLinks(links AssociativeArray) ([]string, error) {
+	values := make([]string, 0, len(links.Order))
+	for _, key := range links.Order {
+		switch v := links.Map[key].(type) {
+		case string:
+			if strings.HasPrefix(v, "<") {
+				if !httpguts.ValidHeaderFieldValue(v) {
+					return nil, errInvalidLink
+				}
+
+				values = append(values, v)
+
+				continue
+			}
+
+			link, err := formatLink(v, AssociativeArray{})
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, link)
+		case AssociativeArray:
+			link, err := formatLink(key, v)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, link)
+		case []any:
+			// an empty array is a list
+			if len(v) != 0 {
+				return nil, errInvalidLink
+			}
+
+			link, err := formatLink(key, AssociativeArray{})
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, link)
+		default:
+			return nil, errInvalidLink
+		}
+	}
+
+	return values, nil
+}
+
+// formatLink builds a link value as defined by RFC 8288
+func formatLink(url string, attributes AssociativeArray) (string, error) {
+	if url == "" || strings.ContainsAny(url, "<>") {
+		return "", errInvalidLink
+	}
+
+	var b strings.Builder
+	b.WriteString("<" + url + ">")
+
+	if _, ok := attributes.Map["rel"]; !ok {
+		b.WriteString("; rel=preload")
+	}
+
+	order := attributes.Order
+	if order == nil {
+		// unordered map, sort the keys to always produce the same value
+		for k := range attributes.Map {
+			order = append(order, k)
+		}
+		sort.Strings(order)
+	}
+
+	for _, name := range order {
+		if !isToken(name) {
+			return "", errInvalidLink
+		}
+
+		switch v := attributes.Map[name].(type) {
+		case bool:
+			if v {
+				b.WriteString("; " + name)
+			}
+		case int64:
+			b.WriteString("; " + name + "=" + strconv.FormatInt(v, 10))
+		case string:
+			b.WriteString("; " + name + "=")
+			if isToken(v) {
+				b.WriteString(v)
+			} else {
+				b.WriteString(`"` + quotedStringReplacer.Replace(v) + `"`)
+			}
+		case nil:
+			// attributes set to null are omitted
+		default:
+			return "", errInvalidLink
+		}
+	}
+
+	link := b.String()
+	if !httpguts.ValidHeaderFieldValue(link) {
+		return "", errInvalidLink
+	}
+
+	return link, nil
+}
+
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !httpguts.IsTokenRune(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+package frankenphp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLinks(t *testing.T) {
+	links := splitLinks(`</a,b.css>; rel=preload; as=style, </c.js>; rel="modulepreload"; title="x, y",</d>`)
+
+	assert.Equal(t, []string{`</a,b.css>; rel=preload; as=style`, `</c.js>; rel="modulepreload"; title="x, y"`, `</d>`}, links)
+}
+
+func TestIsEarlyHint(t *testing.T) {
+	assert.True(t, isEarlyHint(`</style.css>; rel=preload; as=style`))
+	assert.True(t, isEarlyHint(`<https://cdn.example.com>; REL="dns-prefetch preconnect"`))
+	assert.False(t, isEarlyHint(`</next>; rel=next`))
+	assert.False(t, isEarlyHint(`</style.css>`))
+}
+
+func TestFormatLinks(t *testing.T) {
+	links, err := formatLinks(AssociativeArray{
+		Map: map[string]any{
+			"0":           "/script.js",
+			"/style.css":  AssociativeArray{Map: map[string]any{"as": "style", "title": `a "b"`, "nopush": true, "media": nil}, Order: []string{"as", "title", "nopush", "media"}},
+			"1":           "</font.woff2>; rel=preload; as=font",
+			"/prefetched": []any{},
+		},
+		Order: []string{"0", "/style.css", "1", "/prefetched"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`</script.js>; rel=preload`,
+		`</style.css>; rel=preload; as=style; title="a \"b\""; nopush`,
+		`</font.woff2>; rel=preload; as=font`,
+		`</prefetched>; rel=preload`,
+	}, links)
+
+	_, err = formatLinks(AssociativeArray{Map: map[string]any{"0": "/foo>"}, Order: []string{"0"}})
+	assert.ErrorIs(t, err, errInvalidLink)
+
+	_, err = formatLinks(AssociativeArray{Map: map[string]any{"/foo": AssociativeArray{Map: map[string]any{"as": "a\nb"}, Order: []string{"as"}}}, Order: []string{"/foo"}})
+	assert.ErrorIs(t, err, errInvalidLink)
+}
+
+func TestEarlyHintsCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newEarlyHintsCache(2)
+
+	c.set("a", []string{"</a.css>; rel=preload"})
+	c.set("b", []string{"</b.css>; rel=preload"})
+
+	// "a" becomes the most recently used route
+	links, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"</a.css>; rel=preload"}, links)
+
+	c.set("c", []string{"</c.css>; rel=preload"})
+
+	_, ok = c.get("b")
+	assert.False(t, ok, "the least recently used route must be evicted")
+	_, ok = c.get("a")
+	assert.True(t, ok)
+	_, ok = c.get("c")
+	assert.True(t, ok)
+}
+
+func TestEarlyHintsCacheDelete(t *testing.T) {
+	c := newEarlyHintsCache(2)
+
+	for i := range 100 {
+		route := fmt.Sprintf("/%d", i)
+		c.set(route, []string{"</style.css>; rel=preload"})
+		c.delete(route)
+	}
+
+	// deleted routes free their slot
+	c.set("a", []string{"</a.css>; rel=preload"})
+	c.set("b", []string{"</b.css>; rel=preload"})
+
+	_, ok := c.get("a")
+	assert.True(t, ok)
+	_, ok = c.get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.lru.Len())
+	assert.Len(t, c.entries, 2)
+}
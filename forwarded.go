@@ -0,0 +1,173 @@
+package frankenphp
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// forwardedInfo contains the client information reported by a trusted reverse proxy
+type forwardedInfo struct {
+	clientIP   string
+	clientPort string
+	scheme     string
+	host       string
+	port       string
+}
+
+// WithRequestTrustedProxies sets the IP ranges of the reverse proxies allowed to report client information.
+//
+// When the request comes from one of these proxies, REMOTE_ADDR, HTTPS, REQUEST_SCHEME, SERVER_NAME
+// and SERVER_PORT are computed from the Forwarded header (RFC 7239) or, if it is absent,
+// from the X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Forwarded-Port headers.
+// These headers are ignored for requests coming from other peers.
+func WithRequestTrustedProxies(proxies []netip.Prefix) RequestOption {
+	return func(o *frankenPHPContext) error {
+		peer, ok := parseIP(o.request.RemoteAddr)
+		if !ok || !isTrustedProxy(peer, proxies) {
+			return nil
+		}
+
+		o.forwarded = parseForwardedHeaders(o.request.Header, proxies)
+
+		return nil
+	}
+}
+
+// WithRequestFromTrustedProxy marks the request as coming from a trusted reverse proxy.
+//
+// It is meant for servers that already validated the peer and determined the IP address of the client,
+// such as Caddy with the trusted_proxies option. The scheme, host and port are read from the
+// Forwarded or X-Forwarded-* headers like with WithRequestTrustedProxies.
+func WithRequestFromTrustedProxy(clientIP string) RequestOption {
+	return func(o *frankenPHPContext) error {
+		o.forwarded = parseForwardedHeaders(o.request.Header, nil)
+		if clientIP != "" {
+			o.forwarded.clientIP = clientIP
+			o.forwarded.clientPort = ""
+		}
+
+		return nil
+	}
+}
+
+func isTrustedProxy(addr netip.Addr, proxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, p := range proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseIP extracts the IP address from a host, a host:port pair or a bracketed IPv6 address
+func parseIP(s string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return addrPort.Addr(), true
+	}
+
+	addr, err := netip.ParseAddr(strings.Trim(s, "[]"))
+
+	return addr, err == nil
+}
+
+// parseForwardedHeaders reads the client information from the Forwarded header,
+// or from the X-Forwarded-* headers if it is absent.
+//
+// The client IP is the right-most address that isn't a trusted proxy. The scheme and the host are the right-most values:
+// proxies appending to these headers would otherwise let the client choose the left-most ones.
+func parseForwardedHeaders(h http.Header, proxies []netip.Prefix) *forwardedInfo {
+	f := &forwardedInfo{}
+
+	if values := h.Values("Forwarded"); len(values) > 0 {
+		var forwardedFor []string
+		for _, element := range splitHeaderValues(values) {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok {
+					continue
+				}
+				value = strings.Trim(value, `"`)
+
+				switch strings.ToLower(key) {
+				case "for":
+					forwardedFor = append(forwardedFor, value)
+				case "proto":
+					f.scheme = strings.ToLower(value)
+				case "host":
+					f.host = value
+				}
+			}
+		}
+
+		f.clientIP, f.clientPort = clientAddr(forwardedFor, proxies)
+	} else {
+		f.clientIP, _ = clientAddr(splitHeaderValues(h.Values("X-Forwarded-For")), proxies)
+		f.scheme = strings.ToLower(lastHeaderValue(h, "X-Forwarded-Proto"))
+		f.host = lastHeaderValue(h, "X-Forwarded-Host")
+		f.port = lastHeaderValue(h, "X-Forwarded-Port")
+	}
+
+	if host, port, err := net.SplitHostPort(f.host); err == nil {
+		f.host = host
+		if f.port == "" {
+			f.port = port
+		}
+	}
+
+	if f.scheme != "http" && f.scheme != "https" {
+		f.scheme = ""
+	}
+
+	return f
+}
+
+// clientAddr walks the list of forwarded addresses from right to left and skips trusted proxies
+func clientAddr(addrs []string, proxies []netip.Prefix) (ip string, port string) {
+	for i := len(addrs) - 1; i >= 0; i-- {
+		addr, ok := parseIP(addrs[i])
+		if !ok {
+			// obfuscated identifier or "unknown", the client can't be determined
+			return "", ""
+		}
+
+		ip = addr.Unmap().String()
+		port = ""
+		if addrPort, err := netip.ParseAddrPort(addrs[i]); err == nil {
+			port = strconv.Itoa(int(addrPort.Port()))
+		}
+
+		if !isTrustedProxy(addr, proxies) {
+			return ip, port
+		}
+	}
+
+	// all addresses are trusted proxies, use the left-most one
+	return ip, port
+}
+
+func splitHeaderValues(values []string) []string {
+	var parts []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+
+	return parts
+}
+
+func lastHeaderValue(h http.Header, key string) string {
+	values := splitHeaderValues(h.Values(key))
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
@@ -0,0 +1,79 @@
+package frankenphp
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseForwardedHeaders(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected forwardedInfo
+	}{
+		{
+			name: "x-forwarded headers",
+			headers: map[string]string{
+				"X-Forwarded-For":   "203.0.113.1, 10.0.0.2",
+				"X-Forwarded-Proto": "HTTPS",
+				"X-Forwarded-Host":  "example.com:8443",
+			},
+			expected: forwardedInfo{clientIP: "203.0.113.1", scheme: "https", host: "example.com", port: "8443"},
+		},
+		{
+			name: "spoofed x-forwarded-for",
+			headers: map[string]string{
+				"X-Forwarded-For":  "127.0.0.1, 198.51.100.1, 10.0.0.2",
+				"X-Forwarded-Port": "8080",
+			},
+			expected: forwardedInfo{clientIP: "198.51.100.1", port: "8080"},
+		},
+		{
+			name: "forwarded header",
+			headers: map[string]string{
+				"Forwarded":       `for="[2001:db8::1]:4711";proto=https;host=example.com, for=10.0.0.2`,
+				"X-Forwarded-For": "198.51.100.1",
+			},
+			expected: forwardedInfo{clientIP: "2001:db8::1", clientPort: "4711", scheme: "https", host: "example.com"},
+		},
+		{
+			name: "appended x-forwarded headers",
+			headers: map[string]string{
+				"X-Forwarded-For":   "203.0.113.1, 10.0.0.2",
+				"X-Forwarded-Proto": "http, https",
+				"X-Forwarded-Host":  "evil.example, example.com",
+			},
+			expected: forwardedInfo{clientIP: "203.0.113.1", scheme: "https", host: "example.com"},
+		},
+		{
+			name: "appended forwarded header",
+			headers: map[string]string{
+				"Forwarded": `for=198.51.100.1;proto=http;host=evil.example, for=203.0.113.1;proto=https;host=example.com`,
+			},
+			expected: forwardedInfo{clientIP: "203.0.113.1", scheme: "https", host: "example.com"},
+		},
+		{
+			name: "obfuscated client",
+			headers: map[string]string{
+				"Forwarded": "for=_hidden;proto=ftp",
+			},
+			expected: forwardedInfo{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range test.headers {
+				h.Set(k, v)
+			}
+
+			assert.Equal(t, test.expected, *parseForwardedHeaders(h, proxies))
+		})
+	}
+}
@@ -1,5 +1,6 @@
 #include <SAPI.h>
 #include <Zend/zend_alloc.h>
+#include <Zend/zend_builtin_functions.h>
 #include <Zend/zend_exceptions.h>
 #include <Zend/zend_interfaces.h>
 #include <Zend/zend_types.h>
@@ -28,6 +29,7 @@
 
 #include "_cgo_export.h"
 #include "frankenphp_arginfo.h"
+#include "types.h"
 
 #if defined(PHP_WIN32) && defined(ZTS)
 ZEND_TSRMLS_CACHE_DEFINE()
@@ -184,6 +186,8 @@ static void frankenphp_worker_request_shutdown() {
   zend_end_try();
 
   /* SAPI related shutdown (free stuff) */
+  __zend_string_views_release__();
+  __clear_pending_bailout__();
   frankenphp_free_request_context();
   zend_try { sapi_deactivate(); }
   zend_end_try();
@@ -290,6 +294,95 @@ PHP_FUNCTION(frankenphp_finish_request) { /* {{{ */
   RETURN_TRUE;
 } /* }}} */
 
+static bool send_trailer(zend_string *key, zval *value, bool announce) {
+  zend_string *str = zval_try_get_string(value);
+  if (str == NULL) {
+    return false;
+  }
+
+  bool success = go_write_trailer(thread_index, ZSTR_VAL(key), ZSTR_LEN(key),
+                                  ZSTR_VAL(str), ZSTR_LEN(str), announce);
+  zend_string_release(str);
+
+  if (!success) {
+    php_error_docref(NULL, E_WARNING, "Unable to send the \"%s\" trailer",
+                     ZSTR_VAL(key));
+  }
+
+  return success;
+}
+
+/* {{{ Send HTTP trailers, values can be strings or arrays of strings */
+PHP_FUNCTION(frankenphp_send_trailers) {
+  HashTable *trailers;
+  zend_string *key;
+  zval *value, *v;
+
+  ZEND_PARSE_PARAMETERS_START(1, 1)
+  Z_PARAM_ARRAY_HT(trailers)
+  ZEND_PARSE_PARAMETERS_END();
+
+  if (go_is_context_done(thread_index)) {
+    RETURN_FALSE;
+  }
+
+  /* if the headers haven't been sent yet, the trailers are announced in the
+   * Trailer header, as some clients and proxies ignore undeclared trailers */
+  bool announce = !SG(headers_sent);
+
+  ZEND_HASH_FOREACH_STR_KEY_VAL(trailers, key, value) {
+    if (key == NULL) {
+      zend_argument_value_error(1, "must only have string keys");
+      RETURN_THROWS();
+    }
+
+    if (Z_TYPE_P(value) != IS_ARRAY) {
+      if (!send_trailer(key, value, announce)) {
+        RETURN_FALSE;
+      }
+
+      continue;
+    }
+
+    ZEND_HASH_FOREACH_VAL(Z_ARRVAL_P(value), v) {
+      if (!send_trailer(key, v, announce)) {
+        RETURN_FALSE;
+      }
+    }
+    ZEND_HASH_FOREACH_END();
+  }
+  ZEND_HASH_FOREACH_END();
+
+  /* trailers are sent after the headers */
+  php_header();
+
+  RETURN_TRUE;
+} /* }}} */
+
+/* {{{ Remove the cached responses having any of the given Surrogate-Key tags
+ */
+PHP_FUNCTION(frankenphp_cache_purge_tags) {
+  HashTable *tags;
+  zval *tag;
+  zend_long purged = 0;
+
+  ZEND_PARSE_PARAMETERS_START(1, 1)
+  Z_PARAM_ARRAY_HT(tags)
+  ZEND_PARSE_PARAMETERS_END();
+
+  ZEND_HASH_FOREACH_VAL(tags, tag) {
+    if (Z_TYPE_P(tag) != IS_STRING) {
+      zend_argument_type_error(1, "must only contain strings");
+      RETURN_THROWS();
+    }
+
+    purged += go_cache_purge_tag(Z_STRVAL_P(tag), Z_STRLEN_P(tag));
+  }
+  ZEND_HASH_FOREACH_END();
+
+  RETURN_LONG(purged);
+} /* }}} */
+
 /* {{{ Call go's putenv to prevent race conditions */
 PHP_FUNCTION(frankenphp_putenv) {
   char *setting;
@@ -493,9 +586,157 @@ PHP_FUNCTION(headers_send) {
   RETURN_LONG(sapi_send_headers());
 }
 
+/* {{{ Send a 103 Early Hints response containing Link headers */
+PHP_FUNCTION(frankenphp_early_hints) {
+  zval *links;
+
+  ZEND_PARSE_PARAMETERS_START(1, 1)
+  Z_PARAM_ARRAY(links)
+  ZEND_PARSE_PARAMETERS_END();
+
+  /* early hints must be sent before the final response */
+  if (SG(headers_sent)) {
+    php_error_docref(NULL, E_WARNING,
+                     "Cannot send early hints after the headers have been sent");
+    RETURN_FALSE;
+  }
+
+  if (go_is_context_done(thread_index)) {
+    RETURN_FALSE;
+  }
+
+  if (!go_early_hints(thread_index, links)) {
+    php_error_docref(NULL, E_WARNING, "Invalid early hints links");
+    RETURN_FALSE;
+  }
+
+  RETURN_TRUE;
+} /* }}} */
+
+static void (*original_zend_error_cb)(int type, zend_string *error_filename,
+                                      const uint32_t error_lineno,
+                                      zend_string *message) = NULL;
+static __thread bool has_pending_error = false;
+
+/* whether all errors are passed to the Go error reporter, see
+ * frankenphp_set_trace_all_errors() */
+static bool trace_all_errors = false;
+
+void frankenphp_set_trace_all_errors(bool enabled) {
+  trace_all_errors = enabled;
+}
+
+/* building the stack trace is costly, it is only done for errors that will be
+ * passed to the error reporter or recorded by the logger of the request */
+static bool frankenphp_error_needs_trace(int error_type) {
+  return trace_all_errors ||
+         (PG(log_errors) && go_is_php_error_logged(thread_index, error_type));
+}
+
+/* pass errors to Go as structured records before PHP handles them */
+static void frankenphp_error_cb(int type, zend_string *error_filename,
+                                const uint32_t error_lineno,
+                                zend_string *message) {
+  int error_type = type & E_ALL;
+
+  /* ignore errors raised outside of requests and errors that won't be reported
+   */
+  if (SG(server_context) == NULL ||
+      !((EG(error_reporting) & error_type) || (error_type & E_CORE))) {
+    original_zend_error_cb(type, error_filename, error_lineno, message);
+    return;
+  }
+
+  zend_string *trace = NULL;
+  if (EG(current_execute_data) && frankenphp_error_needs_trace(error_type)) {
+    zval backtrace;
+    zend_fetch_debug_backtrace(&backtrace, 0, DEBUG_BACKTRACE_IGNORE_ARGS, 0);
+    trace = zend_trace_to_string(Z_ARRVAL(backtrace), false);
+    zval_ptr_dtor(&backtrace);
+  }
+
+  go_report_php_error(
+      thread_index, error_type, error_filename ? ZSTR_VAL(error_filename) : NULL,
+      error_filename ? ZSTR_LEN(error_filename) : 0, error_lineno,
+      ZSTR_VAL(message), ZSTR_LEN(message), trace ? ZSTR_VAL(trace) : NULL,
+      trace ? ZSTR_LEN(trace) : 0);
+
+  if (trace) {
+    zend_string_release(trace);
+  }
+
+  /* if PHP logs the error, frankenphp_log_message will log the structured
+   * record instead */
+  has_pending_error = true;
+  zend_try {
+    original_zend_error_cb(type, error_filename, error_lineno, message);
+  }
+  zend_catch {
+    has_pending_error = false;
+    zend_bailout();
+  }
+  zend_end_try();
+  has_pending_error = false;
+}
+
+static void (*original_interrupt_function)(zend_execute_data *execute_data) =
+    NULL;
+
+/* collect information about the running script on behalf of the Go side */
+static void frankenphp_report_snapshot(bool with_stack) {
+  zend_string *stack = NULL;
+
+  if (with_stack && EG(current_execute_data)) {
+    zval backtrace;
+    zend_fetch_debug_backtrace(&backtrace, 0, DEBUG_BACKTRACE_IGNORE_ARGS, 0);
+    stack = zend_trace_to_string(Z_ARRVAL(backtrace), false);
+    zval_ptr_dtor(&backtrace);
+  }
+
+  go_frankenphp_report_snapshot(
+      thread_index, zend_memory_usage(false), zend_memory_peak_usage(false),
+      stack ? ZSTR_VAL(stack) : NULL, stack ? ZSTR_LEN(stack) : 0);
+
+  if (stack) {
+    zend_string_release(stack);
+  }
+}
+
+static void frankenphp_interrupt_function(zend_execute_data *execute_data) {
+  int actions = go_frankenphp_on_vm_interrupt(thread_index);
+
+  if (actions & FRANKENPHP_INTERRUPT_SNAPSHOT) {
+    frankenphp_report_snapshot(actions & FRANKENPHP_INTERRUPT_STACK);
+  }
+
+  if (original_interrupt_function) {
+    original_interrupt_function(execute_data);
+  }
+
+  /* a PHP callable called from Go triggered a fatal error */
+  __raise_pending_bailout__();
+
+  if (actions & FRANKENPHP_INTERRUPT_TERMINATE) {
+    /* bail out like on timeouts, the request has already been rejected */
+    zend_error_noreturn(E_ERROR, "Request terminated by FrankenPHP");
+  }
+}
+
+void frankenphp_interrupt_thread(zend_atomic_bool *vm_interrupt) {
+  zend_atomic_bool_store(vm_interrupt, true);
+}
+
 PHP_MINIT_FUNCTION(frankenphp) {
   zend_function *func;
 
+  // Hook into error handling to pass structured errors to Go
+  original_zend_error_cb = zend_error_cb;
+  zend_error_cb = frankenphp_error_cb;
+
+  // Hook into VM interrupts to inspect or stop running scripts from Go
+  original_interrupt_function = zend_interrupt_function;
+  zend_interrupt_function = frankenphp_interrupt_function;
+
   // Override putenv
   func = zend_hash_str_find_ptr(CG(function_table), "putenv",
                                 sizeof("putenv") - 1);
@@ -530,6 +771,8 @@ static zend_module_entry frankenphp_module = {
     STANDARD_MODULE_PROPERTIES};
 
 static void frankenphp_request_shutdown() {
+  __zend_string_views_release__();
+  __clear_pending_bailout__();
   frankenphp_free_request_context();
   php_request_shutdown((void *)0);
 }
@@ -786,6 +1029,12 @@ static void frankenphp_register_variables(zval *track_vars_array) {
 }
 
 static void frankenphp_log_message(const char *message, int syslog_type_int) {
+  if (has_pending_error) {
+    has_pending_error = false;
+    go_log_php_error(thread_index, (char *)message, syslog_type_int);
+    return;
+  }
+
   go_log((char *)message, syslog_type_int);
 }
 
@@ -862,6 +1111,8 @@ static void *php_thread(void *arg) {
 #endif
 #endif
 
+  go_frankenphp_register_vm_interrupt(thread_index, &EG(vm_interrupt));
+
   // loop until Go signals to stop
   char *scriptName = NULL;
   while ((scriptName = go_frankenphp_before_script_execution(thread_index))) {
@@ -869,6 +1120,9 @@ static void *php_thread(void *arg) {
                                          frankenphp_execute_script(scriptName));
   }
 
+  go_frankenphp_register_vm_interrupt(thread_index, NULL);
+  __zend_string_views_free__();
+
 #ifdef ZTS
   ts_free_thread();
 #endif
@@ -1135,7 +1389,9 @@ static void *execute_script_cli(void *arg) {
 
   exit_status = (void *)(intptr_t)EG(exit_status);
 
+  __zend_string_views_release__();
   php_embed_shutdown();
+  __zend_string_views_free__();
 
   return exit_status;
 }
@@ -1179,7 +1435,7 @@ int frankenphp_reset_opcache(void) {
 
 int frankenphp_get_current_memory_limit() { return PG(memory_limit); }
 
-static zend_module_entry *modules = NULL;
+static zend_module_entry **modules = NULL;
 static int modules_len = 0;
 static int (*original_php_register_internal_extensions_func)(void) = NULL;
 
@@ -1190,19 +1446,23 @@ PHPAPI int register_internal_extensions(void) {
   }
 
   for (int i = 0; i < modules_len; i++) {
-    if (zend_register_internal_module(&modules[i]) == NULL) {
+    if (zend_register_internal_module(modules[i]) == NULL) {
       return FAILURE;
     }
   }
 
+  free(modules);
   modules = NULL;
   modules_len = 0;
 
   return SUCCESS;
 }
 
-void register_extensions(zend_module_entry *m, int len) {
-  modules = m;
+void register_extensions(zend_module_entry **m, int len) {
+  /* module entries are not contiguous in memory, and the Go slice must not be
+   * retained */
+  modules = malloc(len * sizeof(zend_module_entry *));
+  memcpy(modules, m, len * sizeof(zend_module_entry *));
   modules_len = len;
 
   original_php_register_internal_extensions_func =
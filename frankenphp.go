@@ -35,6 +35,8 @@ import (
 	"syscall"
 	"time"
 	"unsafe"
+
+	"golang.org/x/net/http/httpguts"
 	// debug on Linux
 	//_ "github.com/ianlancetaylor/cgosymbolizer"
 )
@@ -240,6 +242,16 @@ func Init(options ...Option) error {
 	}
 
 	maxWaitTime = opt.maxWaitTime
+	errorReporter = opt.errorReporter
+	setTraceAllErrors()
+	workerEventHandler = opt.workerEvents
+	readinessMaxQueueDepth = opt.maxQueueDepth
+	responseFilters = opt.responseFilters
+	if opt.responseCacheSize > 0 {
+		responseCache = newCache(opt.responseCacheSize)
+	} else {
+		responseCache = nil
+	}
 
 	totalThreadCount, workerThreadCount, maxThreadCount, err := calculateMaxThreads(opt)
 	if err != nil {
@@ -327,15 +339,37 @@ func ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) error
 		return nil
 	}
 
+	if fc.serveFromCache() || fc.serveCoalesced() {
+		return nil
+	}
+
+	completed := false
+	if fc.coalescedCall != nil {
+		// the waiting requests must be released even if handling the request panics
+		defer func() { fc.finishCoalescedCall(completed) }()
+	}
+
+	fc.sendAutomaticEarlyHints()
+
 	// Detect if a worker is available to handle this request
 	if fc.worker != nil {
 		fc.worker.handleRequest(fc)
+	} else {
+		// If no worker was available, send the request to non-worker threads
+		handleRequestWithRegularPHPThreads(fc)
+	}
 
-		return nil
+	// the PHP thread is free, the file requested with X-Sendfile can be sent
+	if fc.sendfile != nil {
+		fc.serveSendfile()
 	}
 
-	// If no worker was available, send the request to non-worker threads
-	handleRequestWithRegularPHPThreads(fc)
+	if fc.cacheRecorder != nil {
+		fc.storeInCache()
+	}
+
+	completed = true
+
 	return nil
 }
 
@@ -347,11 +381,18 @@ func go_ub_write(threadIndex C.uintptr_t, cBuf *C.char, length C.int) (C.size_t,
 		return 0, C.bool(true)
 	}
 
+	if fc.sendfile != nil {
+		// the output is replaced by the file requested with X-Sendfile
+		return C.size_t(length), C.bool(fc.clientHasClosed())
+	}
+
 	var writer io.Writer
 	if fc.responseWriter == nil {
 		var b bytes.Buffer
 		// log the output of the worker
 		writer = &b
+	} else if fc.bodyWriter != nil {
+		writer = fc.bodyWriter
 	} else {
 		writer = fc.responseWriter
 	}
@@ -470,6 +511,18 @@ func go_write_headers(threadIndex C.uintptr_t, status C.int, headers *C.zend_lli
 		current = current.next
 	}
 
+	if status >= 200 {
+		status = C.int(fc.applyResponseFilters(int(status)))
+		fc.cacheEarlyHints(int(status))
+	} else if status == http.StatusEarlyHints {
+		fc.markEarlyHintsAsSent()
+	}
+
+	if fc.sendfileRoot != "" && status == http.StatusOK && fc.interceptSendfile() {
+		// headers will be written when sending the file
+		return C.bool(true)
+	}
+
 	fc.responseWriter.WriteHeader(int(status))
 
 	if status >= 100 && status < 200 {
@@ -483,6 +536,80 @@ func go_write_headers(threadIndex C.uintptr_t, status C.int, headers *C.zend_lli
 	return C.bool(true)
 }
 
+// go_write_trailer adds a trailer, sent after the body of the response.
+// If announce is true, the headers haven't been sent yet and the trailer is declared in the "Trailer" header.
+//
+//export go_write_trailer
+func go_write_trailer(threadIndex C.uintptr_t, key *C.char, keyLen C.size_t, value *C.char, valueLen C.size_t, announce C.bool) C.bool {
+	fc := phpThreads[threadIndex].getRequestContext()
+	if fc == nil || fc.isDone || fc.responseWriter == nil {
+		return C.bool(false)
+	}
+
+	k := C.GoStringN(key, C.int(keyLen))
+	v := C.GoStringN(value, C.int(valueLen))
+	if !httpguts.ValidHeaderFieldName(k) || !httpguts.ValidHeaderFieldValue(v) || !httpguts.ValidTrailerHeader(k) {
+		return C.bool(false)
+	}
+
+	h := fc.responseWriter.Header()
+	declared := isDeclaredTrailer(h, k)
+	if bool(announce) && !declared {
+		h.Add("Trailer", http.CanonicalHeaderKey(k))
+		declared = true
+	}
+
+	// the prefix works for both declared and undeclared trailers, and whether the headers have been written or not
+	h.Add(http.TrailerPrefix+k, v)
+
+	if !declared && fc.sendfile == nil {
+		// net/http snapshots the headers in WriteHeader: if the body is still buffered when the handler returns,
+		// it sets a Content-Length and drops the undeclared trailers, flushing switches to chunked encoding
+		if err := http.NewResponseController(fc.responseWriter).Flush(); err != nil {
+			fc.logger.LogAttrs(fc.request.Context(), slog.LevelDebug, "unable to flush the response before sending trailers", slog.Any("error", err))
+		}
+	}
+
+	return C.bool(true)
+}
+
+// isDeclaredTrailer checks if the trailer is listed in the "Trailer" header
+func isDeclaredTrailer(h http.Header, key string) bool {
+	for _, declared := range h.Values("Trailer") {
+		for _, name := range strings.Split(declared, ",") {
+			if strings.EqualFold(strings.TrimSpace(name), key) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// go_early_hints sends a 103 Early Hints response containing the links passed to frankenphp_early_hints().
+//
+//export go_early_hints
+func go_early_hints(threadIndex C.uintptr_t, links *C.zval) C.bool {
+	fc := phpThreads[threadIndex].getRequestContext()
+	if fc == nil || fc.isDone || fc.responseWriter == nil {
+		return C.bool(false)
+	}
+
+	values, err := formatLinks(GoAssociativeArray(unsafe.Pointer(links)))
+	if err != nil {
+		return C.bool(false)
+	}
+
+	fc.sendEarlyHints(values)
+
+	return C.bool(true)
+}
+
+//export go_cache_purge_tag
+func go_cache_purge_tag(tag *C.char, tagLen C.size_t) C.int {
+	return C.int(PurgeCacheTags(C.GoStringN(tag, C.int(tagLen))))
+}
+
 //export go_sapi_flush
 func go_sapi_flush(threadIndex C.uintptr_t) bool {
 	fc := phpThreads[threadIndex].getRequestContext()
@@ -494,6 +621,11 @@ func go_sapi_flush(threadIndex C.uintptr_t) bool {
 		return true
 	}
 
+	if fc.sendfile != nil {
+		// flushing would send the headers before the file is sent
+		return false
+	}
+
 	if err := http.NewResponseController(fc.responseWriter).Flush(); err != nil {
 		logger.LogAttrs(context.Background(), slog.LevelWarn, "the current responseWriter is not a flusher, if you are not using a custom build, please report this issue", slog.Any("error", err))
 	}
@@ -1,6 +1,7 @@
 #ifndef _FRANKENPHP_H
 #define _FRANKENPHP_H
 
+#include <Zend/zend_atomic.h>
 #include <Zend/zend_modules.h>
 #include <Zend/zend_types.h>
 #include <stdbool.h>
@@ -12,6 +13,11 @@
 #define STRINGIFY(x) #x
 #define TOSTRING(x) STRINGIFY(x)
 
+/* actions to take when a thread is interrupted from Go */
+#define FRANKENPHP_INTERRUPT_SNAPSHOT 1
+#define FRANKENPHP_INTERRUPT_STACK 2
+#define FRANKENPHP_INTERRUPT_TERMINATE 4
+
 typedef struct go_string {
   size_t len;
   char *data;
@@ -84,6 +90,10 @@ void frankenphp_register_bulk(
     ht_key_value_pair auth_type, ht_key_value_pair remote_ident,
     ht_key_value_pair request_uri, ht_key_value_pair ssl_cipher);
 
-void register_extensions(zend_module_entry *m, int len);
+void frankenphp_interrupt_thread(zend_atomic_bool *vm_interrupt);
+
+void frankenphp_set_trace_all_errors(bool enabled);
+
+void register_extensions(zend_module_entry **m, int len);
 
 #endif
@@ -6,6 +6,8 @@ function frankenphp_handle_request(callable $callback): bool {}
 
 function headers_send(int $status = 200): int {}
 
+function frankenphp_early_hints(array $links): bool {}
+
 function frankenphp_finish_request(): bool {}
 
 /**
@@ -13,6 +15,10 @@ function frankenphp_finish_request(): bool {}
  */
 function fastcgi_finish_request(): bool {}
 
+function frankenphp_send_trailers(array $trailers): bool {}
+
+function frankenphp_cache_purge_tags(array $tags): int {}
+
 function frankenphp_request_headers(): array {}
 
 /**
@@ -1,5 +1,5 @@
 /* This is a generated file, edit the .stub.php file instead.
- * Stub hash: 05ebde17137c559e891362fba6524fad1e0a2dfe */
+ * Stub hash: 76d6fd2b77a38ef600f4b1090a473ab42d48bca4 */
 
 ZEND_BEGIN_ARG_WITH_RETURN_TYPE_INFO_EX(arginfo_frankenphp_handle_request, 0, 1,
                                         _IS_BOOL, 0)
@@ -10,12 +10,27 @@ ZEND_BEGIN_ARG_WITH_RETURN_TYPE_INFO_EX(arginfo_headers_send, 0, 0, IS_LONG, 0)
 ZEND_ARG_TYPE_INFO_WITH_DEFAULT_VALUE(0, status, IS_LONG, 0, "200")
 ZEND_END_ARG_INFO()
 
+ZEND_BEGIN_ARG_WITH_RETURN_TYPE_INFO_EX(arginfo_frankenphp_early_hints, 0, 1,
+                                        _IS_BOOL, 0)
+ZEND_ARG_TYPE_INFO(0, links, IS_ARRAY, 0)
+ZEND_END_ARG_INFO()
+
 ZEND_BEGIN_ARG_WITH_RETURN_TYPE_INFO_EX(arginfo_frankenphp_finish_request, 0, 0,
                                         _IS_BOOL, 0)
 ZEND_END_ARG_INFO()
 
 #define arginfo_fastcgi_finish_request arginfo_frankenphp_finish_request
 
+ZEND_BEGIN_ARG_WITH_RETURN_TYPE_INFO_EX(arginfo_frankenphp_send_trailers, 0, 1,
+                                        _IS_BOOL, 0)
+ZEND_ARG_TYPE_INFO(0, trailers, IS_ARRAY, 0)
+ZEND_END_ARG_INFO()
+
+ZEND_BEGIN_ARG_WITH_RETURN_TYPE_INFO_EX(arginfo_frankenphp_cache_purge_tags, 0,
+                                        1, IS_LONG, 0)
+ZEND_ARG_TYPE_INFO(0, tags, IS_ARRAY, 0)
+ZEND_END_ARG_INFO()
+
 ZEND_BEGIN_ARG_WITH_RETURN_TYPE_INFO_EX(arginfo_frankenphp_request_headers, 0,
                                         0, IS_ARRAY, 0)
 ZEND_END_ARG_INFO()
@@ -32,7 +47,10 @@ ZEND_END_ARG_INFO()
 
 ZEND_FUNCTION(frankenphp_handle_request);
 ZEND_FUNCTION(headers_send);
+ZEND_FUNCTION(frankenphp_early_hints);
 ZEND_FUNCTION(frankenphp_finish_request);
+ZEND_FUNCTION(frankenphp_send_trailers);
+ZEND_FUNCTION(frankenphp_cache_purge_tags);
 ZEND_FUNCTION(frankenphp_request_headers);
 ZEND_FUNCTION(frankenphp_response_headers);
 
@@ -40,8 +58,11 @@ ZEND_FUNCTION(frankenphp_response_headers);
 static const zend_function_entry ext_functions[] = {
   ZEND_FE(frankenphp_handle_request, arginfo_frankenphp_handle_request)
   ZEND_FE(headers_send, arginfo_headers_send)
+  ZEND_FE(frankenphp_early_hints, arginfo_frankenphp_early_hints)
   ZEND_FE(frankenphp_finish_request, arginfo_frankenphp_finish_request)
   ZEND_FALIAS(fastcgi_finish_request, frankenphp_finish_request, arginfo_fastcgi_finish_request)
+  ZEND_FE(frankenphp_send_trailers, arginfo_frankenphp_send_trailers)
+  ZEND_FE(frankenphp_cache_purge_tags, arginfo_frankenphp_cache_purge_tags)
   ZEND_FE(frankenphp_request_headers, arginfo_frankenphp_request_headers)
   ZEND_FALIAS(apache_request_headers, frankenphp_request_headers, arginfo_apache_request_headers)
   ZEND_FALIAS(getallheaders, frankenphp_request_headers, arginfo_getallheaders)
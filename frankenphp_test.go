@@ -7,25 +7,36 @@ package frankenphp_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
+	"math/big"
 	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
 	"net/http/httptrace"
+	"net/netip"
 	"net/textproto"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dunglas/frankenphp"
 	"github.com/dunglas/frankenphp/internal/fastabs"
@@ -196,6 +207,67 @@ func testServerVariable(t *testing.T, opts *testOptions) {
 	}, opts)
 }
 
+func TestTLSServerVariables_module(t *testing.T) { testTLSServerVariables(t, nil) }
+func TestTLSServerVariables_worker(t *testing.T) {
+	testTLSServerVariables(t, &testOptions{workerScript: "server-variable.php"})
+}
+func testTLSServerVariables(t *testing.T, opts *testOptions) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0xABCDEF),
+		Subject:      pkix.Name{CommonName: "client", Organization: []string{"FrankenPHP"}},
+		NotBefore:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		NotAfter:     time.Date(2034, 11, 12, 13, 14, 15, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com", Organization: []string{"FrankenPHP"}},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		NotAfter:     time.Date(2034, 11, 12, 13, 14, 15, 0, time.UTC),
+	}
+	serverDer, err := x509.CreateCertificate(rand.Reader, serverTemplate, serverTemplate, &key.PublicKey, key)
+	require.NoError(t, err)
+	server := &http.Server{TLSConfig: &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{serverDer}, PrivateKey: key}}}}
+
+	runTest(t, func(handler func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("https://example.com/server-variable.php?i=%d", i), nil)
+		req = req.WithContext(context.WithValue(req.Context(), http.ServerContextKey, server))
+		req.TLS.ServerName = "example.com"
+		req.TLS.NegotiatedProtocol = "h2"
+		req.TLS.PeerCertificates = []*x509.Certificate{cert}
+		req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+		body, _ := testRequest(req, handler, t)
+
+		assert.Contains(t, body, "[HTTPS] => on")
+		assert.Contains(t, body, "[SSL_TLS_SNI] => example.com")
+		assert.Contains(t, body, "[SSL_TLS_ALPN] => h2")
+		assert.Contains(t, body, "[SSL_SERVER_S_DN] => CN=example.com,O=FrankenPHP")
+		assert.Contains(t, body, "[SSL_CLIENT_VERIFY] => SUCCESS")
+		assert.Contains(t, body, "[SSL_CLIENT_S_DN] => CN=client,O=FrankenPHP")
+		assert.Contains(t, body, "[SSL_CLIENT_I_DN] => CN=client,O=FrankenPHP")
+		assert.Contains(t, body, "[SSL_CLIENT_M_SERIAL] => ABCDEF")
+		assert.Contains(t, body, "[SSL_CLIENT_V_START] => Jan  2 03:04:05 2024 GMT")
+		assert.Contains(t, body, "[SSL_CLIENT_V_END] => Nov 12 13:14:15 2034 GMT")
+		assert.Contains(t, body, "[SSL_CLIENT_CERT] => -----BEGIN CERTIFICATE-----")
+
+		// no client certificate
+		req = httptest.NewRequest("GET", fmt.Sprintf("https://example.com/server-variable.php?i=%d", i), nil)
+		body, _ = testRequest(req, handler, t)
+
+		assert.Contains(t, body, "[SSL_CLIENT_VERIFY] => NONE")
+		assert.NotContains(t, body, "[SSL_CLIENT_CERT]")
+		assert.NotContains(t, body, "[SSL_SERVER_S_DN]")
+	}, opts)
+}
+
 func TestPathInfo_module(t *testing.T) { testPathInfo(t, nil) }
 func TestPathInfo_worker(t *testing.T) {
 	testPathInfo(t, &testOptions{workerScript: "server-variable.php"})
@@ -230,6 +302,337 @@ func testPathInfo(t *testing.T, opts *testOptions) {
 	}, opts)
 }
 
+func TestTrustedProxies_module(t *testing.T) { testTrustedProxies(t, nil) }
+func TestTrustedProxies_worker(t *testing.T) {
+	testTrustedProxies(t, &testOptions{workerScript: "server-variable.php"})
+}
+func testTrustedProxies(t *testing.T, opts *testOptions) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			req, err := frankenphp.NewRequestWithContext(r,
+				frankenphp.WithRequestDocumentRoot(testDataDir, false),
+				frankenphp.WithRequestTrustedProxies(trustedProxies),
+			)
+			assert.NoError(t, err)
+
+			err = frankenphp.ServeHTTP(w, req)
+			assert.NoError(t, err)
+		}
+
+		// httptest requests come from 192.0.2.1
+		req := httptest.NewRequest("GET", fmt.Sprintf("http://example.com/server-variable.php?i=%d", i), nil)
+		req.Header.Set("X-Forwarded-For", "203.0.113.7, 192.0.2.10")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "example.org")
+		body, _ := testRequest(req, handler, t)
+
+		assert.Contains(t, body, "[REMOTE_ADDR] => 203.0.113.7")
+		assert.Contains(t, body, "[HTTPS] => on")
+		assert.Contains(t, body, "[REQUEST_SCHEME] => https")
+		assert.Contains(t, body, "[SERVER_NAME] => example.org")
+		assert.Contains(t, body, "[SERVER_PORT] => 443")
+
+		// headers sent by untrusted peers are ignored
+		req = httptest.NewRequest("GET", fmt.Sprintf("http://example.com/server-variable.php?i=%d", i), nil)
+		req.RemoteAddr = "198.51.100.1:1234"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		body, _ = testRequest(req, handler, t)
+
+		assert.Contains(t, body, "[REMOTE_ADDR] => 198.51.100.1")
+		assert.Contains(t, body, "[REQUEST_SCHEME] => http")
+		assert.Contains(t, body, "[SERVER_NAME] => example.com")
+		assert.Contains(t, body, "[SERVER_PORT] => 80")
+	}, opts)
+}
+
+func TestTryFiles_module(t *testing.T) { testTryFiles(t, nil) }
+func TestTryFiles_worker(t *testing.T) {
+	testTryFiles(t, &testOptions{workerScript: "server-variable.php"})
+}
+func testTryFiles(t *testing.T, opts *testOptions) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	newHandler := func(tryFiles []string) func(http.ResponseWriter, *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			req, err := frankenphp.NewRequestWithContext(r,
+				frankenphp.WithRequestDocumentRoot(testDataDir, false),
+				frankenphp.WithRequestTryFiles(tryFiles),
+			)
+			assert.NoError(t, err)
+
+			err = frankenphp.ServeHTTP(w, req)
+			assert.NoError(t, err)
+		}
+	}
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		handler := newHandler([]string{"{path}", "{path}/index.php", "server-variable.php"})
+
+		// existing script with a path info
+		body, _ := testGet(fmt.Sprintf("http://example.com/server-variable.php/foo?i=%d", i), handler, t)
+		assert.Contains(t, body, "[SCRIPT_NAME] => /server-variable.php")
+		assert.Contains(t, body, "[PATH_INFO] => /foo")
+
+		// index file of a directory
+		body, _ = testGet(fmt.Sprintf("http://example.com/?i=%d", i), handler, t)
+		assert.Equal(t, fmt.Sprintf("I am by birth a Genevese (%d)", i), body)
+
+		// fallback to the front controller
+		body, _ = testGet(fmt.Sprintf("http://example.com/not-found/path?i=%d", i), handler, t)
+		assert.Contains(t, body, "[SCRIPT_NAME] => /server-variable.php")
+		assert.Contains(t, body, fmt.Sprintf("[REQUEST_URI] => /not-found/path?i=%d", i))
+
+		// static files are never executed
+		_, resp := testGet(fmt.Sprintf("http://example.com/hello.txt?i=%d", i), newHandler([]string{"{path}"}), t)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}, opts)
+}
+
+func TestFrontController_module(t *testing.T) { testFrontController(t, nil) }
+func TestFrontController_worker(t *testing.T) {
+	testFrontController(t, &testOptions{workerScript: "server-variable.php"})
+}
+func testFrontController(t *testing.T, opts *testOptions) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+	frontController := frankenphp.WithRequestFrontController("server-variable.php")
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			req, err := frankenphp.NewRequestWithContext(r,
+				frankenphp.WithRequestDocumentRoot(testDataDir, false),
+				frontController,
+			)
+			assert.NoError(t, err)
+
+			err = frankenphp.ServeHTTP(w, req)
+			assert.NoError(t, err)
+		}
+
+		body, _ := testGet(fmt.Sprintf("http://example.com/index.php/foo/%d", i), handler, t)
+
+		assert.Contains(t, body, "[SCRIPT_NAME] => /server-variable.php")
+		assert.Contains(t, body, fmt.Sprintf("[SCRIPT_FILENAME] => %sserver-variable.php", testDataDir))
+		assert.Contains(t, body, fmt.Sprintf("[PATH_INFO] => /index.php/foo/%d", i))
+		assert.Contains(t, body, fmt.Sprintf("[REQUEST_URI] => /index.php/foo/%d", i))
+	}, opts)
+}
+
+func TestSendfile_module(t *testing.T) { testSendfile(t, nil) }
+func TestSendfile_worker(t *testing.T) {
+	testSendfile(t, &testOptions{workerScript: "sendfile.php"})
+}
+func testSendfile(t *testing.T, opts *testOptions) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestSendfileRoot(testDataDir+"files"),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		body, resp := testGet(fmt.Sprintf("http://example.com/sendfile.php?file=%sfiles/static.txt&i=%d", testDataDir, i), handler, t)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+		assert.NotEmpty(t, resp.Header.Get("ETag"))
+		assert.Empty(t, resp.Header.Get("X-Sendfile"))
+		assert.NotContains(t, body, "This output must not be sent")
+
+		expected, err := os.ReadFile(testDataDir + "files/static.txt")
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), body)
+
+		req := httptest.NewRequest("GET", fmt.Sprintf("http://example.com/sendfile.php?header=X-Accel-Redirect&file=/static.txt&i=%d", i), nil)
+		req.Header.Set("Range", "bytes=0-1")
+		body, resp = testRequest(req, handler, t)
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Equal(t, string(expected[:2]), body)
+
+		_, resp = testGet(fmt.Sprintf("http://example.com/sendfile.php?file=%shello.txt&i=%d", testDataDir, i), handler, t)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		_, resp = testGet(fmt.Sprintf("http://example.com/sendfile.php?header=X-Accel-Redirect&file=../hello.txt&i=%d", i), handler, t)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}, opts)
+}
+
+type upperCaseWriter struct {
+	w io.Writer
+}
+
+func (u *upperCaseWriter) Write(p []byte) (int, error) {
+	return u.w.Write(bytes.ToUpper(p))
+}
+
+func (u *upperCaseWriter) Close() error {
+	_, err := u.w.Write([]byte("!"))
+
+	return err
+}
+
+func TestResponseFilter_module(t *testing.T) { testResponseFilter(t, nil) }
+func TestResponseFilter_worker(t *testing.T) {
+	testResponseFilter(t, &testOptions{workerScript: "headers.php"})
+}
+func testResponseFilter(t *testing.T, opts *testOptions) {
+	if opts == nil {
+		opts = &testOptions{}
+	}
+	opts.initOpts = append(opts.initOpts, frankenphp.WithResponseFilter(func(r *frankenphp.FilteredResponse) {
+		r.Header.Set("X-Content-Type-Options", "nosniff")
+	}))
+
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestResponseFilter(func(r *frankenphp.FilteredResponse) {
+				assert.Equal(t, http.StatusCreated, r.StatusCode)
+				assert.Equal(t, "nosniff", r.Header.Get("X-Content-Type-Options"))
+
+				r.StatusCode = http.StatusAccepted
+				r.Header.Set("Foo", "filtered")
+				r.Body = &upperCaseWriter{r.Body}
+			}),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		body, resp := testGet(fmt.Sprintf("http://example.com/headers.php?i=%d", i), handler, t)
+
+		assert.Equal(t, "HELLO!", body)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, "filtered", resp.Header.Get("Foo"))
+		assert.Equal(t, "bar2", resp.Header.Get("Foo2"))
+		assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+	}, opts)
+}
+
+func TestResponseFilterInvalidStatusCode(t *testing.T) {
+	logger, logs := observer.New(zapcore.ErrorLevel)
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		for _, status := range []int{0, http.StatusEarlyHints, 1000} {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				req, err := frankenphp.NewRequestWithContext(r,
+					frankenphp.WithRequestDocumentRoot(testDataDir, false),
+					frankenphp.WithRequestResponseFilter(func(r *frankenphp.FilteredResponse) {
+						r.StatusCode = status
+					}),
+				)
+				assert.NoError(t, err)
+
+				err = frankenphp.ServeHTTP(w, req)
+				assert.NoError(t, err)
+			}
+
+			_, resp := testGet(fmt.Sprintf("http://example.com/headers.php?i=%d", i), handler, t)
+
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		}
+	}, &testOptions{logger: slog.New(zapslog.NewHandler(logger))})
+
+	assert.NotZero(t, logs.FilterMessage("invalid status code set by a response filter").Len())
+}
+
+// nonComparableWriter can't be compared with ==, because it contains a slice
+type nonComparableWriter struct {
+	w       io.Writer
+	written []int
+}
+
+func (n nonComparableWriter) Write(p []byte) (int, error) {
+	return n.w.Write(p)
+}
+
+func TestResponseFilterNonComparableWriter(t *testing.T) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestResponseFilter(func(r *frankenphp.FilteredResponse) {
+				r.Body = nonComparableWriter{w: &upperCaseWriter{r.Body}}
+			}),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		body, resp := testGet(fmt.Sprintf("http://example.com/headers.php?i=%d", i), handler, t)
+
+		assert.Equal(t, "HELLO", body)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	}, nil)
+}
+
+func TestTrailers_module(t *testing.T) { testTrailers(t, nil) }
+func TestTrailers_worker(t *testing.T) { testTrailers(t, &testOptions{workerScript: "trailers.php"}) }
+func testTrailers(t *testing.T, opts *testOptions) {
+	if opts == nil {
+		opts = &testOptions{}
+	}
+	opts.realServer = true
+
+	runTest(t, func(handler func(http.ResponseWriter, *http.Request), ts *httptest.Server, i int) {
+		body, resp := testGet(fmt.Sprintf("http://example.com/trailers.php?i=%d", i), handler, t)
+
+		assert.Equal(t, "Hello", body)
+		assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+		assert.Equal(t, []string{"db;dur=53", "app;dur=47.2"}, resp.Trailer.Values("Server-Timing"))
+		assert.Empty(t, resp.Header.Get("Grpc-Status"))
+
+		// undeclared trailers sent after the body over HTTP/1.1
+		resp, err := http.Get(fmt.Sprintf("%s/trailers.php?unannounced=1&i=%d", ts.URL, i))
+		require.NoError(t, err)
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, "Hello", string(b))
+		assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+		assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+		assert.Equal(t, []string{"db;dur=53", "app;dur=47.2"}, resp.Trailer.Values("Server-Timing"))
+
+		// trailers sent before the body are announced automatically
+		resp, err = http.Get(fmt.Sprintf("%s/trailers.php?early=1&i=%d", ts.URL, i))
+		require.NoError(t, err)
+		assert.Contains(t, resp.Trailer, "Grpc-Status", "the trailer must be declared before the body")
+		b, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, "Hello", string(b))
+		assert.Empty(t, resp.Header.Get("Grpc-Status"))
+		assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+	}, opts)
+}
+
 func TestHeaders_module(t *testing.T) { testHeaders(t, nil) }
 func TestHeaders_worker(t *testing.T) { testHeaders(t, &testOptions{workerScript: "headers.php"}) }
 func testHeaders(t *testing.T, opts *testOptions) {
@@ -466,6 +869,88 @@ func testException(t *testing.T, opts *testOptions) {
 	}, opts)
 }
 
+type errorRecorder struct {
+	mu     sync.Mutex
+	errors []frankenphp.PHPError
+}
+
+func (r *errorRecorder) ReportPHPError(_ context.Context, e frankenphp.PHPError) {
+	r.mu.Lock()
+	r.errors = append(r.errors, e)
+	r.mu.Unlock()
+}
+
+func (r *errorRecorder) find(message string) *frankenphp.PHPError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.errors {
+		if e.Message == message {
+			return &e
+		}
+	}
+
+	return nil
+}
+
+func TestErrorReporter_module(t *testing.T) { testErrorReporter(t, &testOptions{}) }
+func TestErrorReporter_worker(t *testing.T) {
+	testErrorReporter(t, &testOptions{workerScript: "exception.php"})
+}
+func testErrorReporter(t *testing.T, opts *testOptions) {
+	recorder := &errorRecorder{}
+	logger, logs := observer.New(zapcore.InfoLevel)
+	opts.logger = slog.New(zapslog.NewHandler(logger))
+	opts.initOpts = append(opts.initOpts, frankenphp.WithErrorReporter(recorder))
+	opts.nbParallelRequests = 10
+
+	runTest(t, func(handler func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		testGet(fmt.Sprintf("http://example.com/exception.php?i=%d", i), handler, t)
+
+		e := recorder.find(fmt.Sprintf("request %d", i))
+		require.NotNil(t, e)
+		assert.Equal(t, "E_ERROR", e.Type)
+		assert.Equal(t, "Exception", e.Exception)
+		assert.Equal(t, fmt.Sprintf("/exception.php?i=%d", i), e.RequestURI)
+		assert.Contains(t, e.Trace, "{main}")
+		assert.True(t, strings.HasSuffix(e.File, "exception.php"))
+
+		entries := logs.FilterMessage(fmt.Sprintf("request %d", i)).All()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Exception", entries[0].ContextMap()["exception"])
+	}, opts)
+}
+
+func TestErrorsLoggedByRequestLogger(t *testing.T) {
+	globalLogger, globalLogs := observer.New(zapcore.ErrorLevel)
+	requestLogger, requestLogs := observer.New(zapcore.InfoLevel)
+
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + "/testdata/"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestLogger(slog.New(zapslog.NewHandler(requestLogger))),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		body, _ := testGet(fmt.Sprintf("http://example.com/warning.php?i=%d", i), handler, t)
+		assert.Equal(t, "done", body)
+
+		entries := requestLogs.FilterMessage(fmt.Sprintf("warning %d", i)).All()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "E_USER_WARNING", entries[0].ContextMap()["type"])
+		assert.Contains(t, entries[0].ContextMap()["trace"], "warn(")
+	}, &testOptions{logger: slog.New(zapslog.NewHandler(globalLogger))})
+
+	assert.Zero(t, globalLogs.FilterMessageSnippet("warning").Len())
+}
+
 func TestEarlyHints_module(t *testing.T) { testEarlyHints(t, &testOptions{}) }
 func TestEarlyHints_worker(t *testing.T) {
 	testEarlyHints(t, &testOptions{workerScript: "early-hints.php"})
@@ -498,6 +983,234 @@ func testEarlyHints(t *testing.T, opts *testOptions) {
 	}, opts)
 }
 
+func TestEarlyHintsFunction_module(t *testing.T) { testEarlyHintsFunction(t, &testOptions{}) }
+func TestEarlyHintsFunction_worker(t *testing.T) {
+	testEarlyHintsFunction(t, &testOptions{workerScript: "early-hints-function.php"})
+}
+func testEarlyHintsFunction(t *testing.T, opts *testOptions) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestAutomaticEarlyHints("route-"+r.URL.Query().Get("i")),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		request := func() [][]string {
+			var earlyHints [][]string
+			w := NewRecorder()
+			w.ClientTrace = &httptrace.ClientTrace{
+				Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+					assert.Equal(t, http.StatusEarlyHints, code)
+					earlyHints = append(earlyHints, slices.Clone(header.Values("Link")))
+
+					return nil
+				},
+			}
+			handler(w, httptest.NewRequest("GET", fmt.Sprintf("http://example.com/early-hints-function.php?i=%d", i), nil))
+
+			assert.Equal(t, "Hello", w.Body.String())
+			assert.Equal(t, "</style.css>; rel=preload; as=style", w.Header().Get("Link"))
+
+			return earlyHints
+		}
+
+		assert.Equal(t, [][]string{
+			{"</style.css>; rel=preload; as=style", "</app.js>; rel=modulepreload", "</font.woff2>; rel=preload; as=font; crossorigin"},
+			{"<https://cdn.example.com>; rel=preconnect; crossorigin"},
+		}, request())
+
+		// the links of the previous response are sent automatically
+		assert.Equal(t, [][]string{
+			{"</style.css>; rel=preload; as=style"},
+			{"</app.js>; rel=modulepreload", "</font.woff2>; rel=preload; as=font; crossorigin"},
+			{"<https://cdn.example.com>; rel=preconnect; crossorigin"},
+		}, request())
+	}, opts)
+}
+
+func TestResponseCache_module(t *testing.T) { testResponseCache(t, &testOptions{}) }
+func TestResponseCache_worker(t *testing.T) {
+	testResponseCache(t, &testOptions{workerScript: "response-cache.php"})
+}
+func testResponseCache(t *testing.T, opts *testOptions) {
+	opts.initOpts = append(opts.initOpts, frankenphp.WithResponseCache(1<<20))
+
+	runTest(t, func(handler func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		url := fmt.Sprintf("http://example.com/response-cache.php?i=%d", i)
+
+		body, resp := testGet(url, handler, t)
+		assert.Equal(t, "FrankenPHP; fwd=uri-miss; stored", resp.Header.Get("Cache-Status"))
+
+		// served without executing the script
+		cached, resp := testGet(url, handler, t)
+		assert.Equal(t, body, cached)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Cache-Status"), "FrankenPHP; hit"))
+		assert.NotEmpty(t, resp.Header.Get("Age"))
+
+		req := httptest.NewRequest("GET", url, nil)
+		req.Header.Set("If-None-Match", fmt.Sprintf(`"%d"`, i))
+		_, resp = testRequest(req, handler, t)
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+		purged, _ := testGet(fmt.Sprintf("http://example.com/response-cache.php?i=%d&purge=tag-%d", i, i), handler, t)
+		assert.Equal(t, "1", purged)
+
+		fresh, resp := testGet(url, handler, t)
+		assert.NotEqual(t, body, fresh)
+		assert.Equal(t, "FrankenPHP; fwd=uri-miss; stored", resp.Header.Get("Cache-Status"))
+	}, opts)
+}
+
+func TestResponseCacheStaleWhileRevalidate(t *testing.T) {
+	runTest(t, func(handler func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		url := fmt.Sprintf("http://example.com/response-cache.php?i=%d&swr", i)
+
+		body, _ := testGet(url, handler, t)
+		time.Sleep(1100 * time.Millisecond)
+
+		// the stale response is served while the script is executed in the background
+		stale, resp := testGet(url, handler, t)
+		assert.Equal(t, body, stale)
+		assert.Contains(t, resp.Header.Get("Cache-Status"), "fwd=stale")
+
+		assert.Eventually(t, func() bool {
+			revalidated, resp := testGet(url, handler, t)
+
+			return revalidated != body && strings.HasPrefix(resp.Header.Get("Cache-Status"), "FrankenPHP; hit")
+		}, 5*time.Second, 50*time.Millisecond)
+	}, &testOptions{nbParallelRequests: 10, initOpts: []frankenphp.Option{frankenphp.WithResponseCache(1 << 20)}})
+}
+
+func TestRequestCoalescing_module(t *testing.T) { testRequestCoalescing(t, &testOptions{}) }
+func TestRequestCoalescing_worker(t *testing.T) {
+	testRequestCoalescing(t, &testOptions{workerScript: "coalescing.php"})
+}
+func testRequestCoalescing(t *testing.T, opts *testOptions) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestCoalescing("Accept-Language"),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	var (
+		bodies          sync.Map
+		collapsed       atomic.Int32
+		noStoreBodies   sync.Map
+		noStoreRequests atomic.Int32
+	)
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		body, resp := testGet("http://example.com/coalescing.php", handler, t)
+		bodies.Store(body, struct{}{})
+		if resp.Header.Get("Cache-Status") == "FrankenPHP; fwd=uri-miss; collapsed" {
+			collapsed.Add(1)
+		}
+
+		// responses that can't be cached are never shared
+		body, resp = testGet("http://example.com/coalescing.php?no-store", handler, t)
+		assert.Empty(t, resp.Header.Get("Cache-Status"))
+		noStoreBodies.Store(body, struct{}{})
+		noStoreRequests.Add(1)
+	}, opts)
+
+	var distinct, distinctNoStore int32
+	bodies.Range(func(_, _ any) bool { distinct++; return true })
+	noStoreBodies.Range(func(_, _ any) bool { distinctNoStore++; return true })
+
+	assert.Positive(t, collapsed.Load())
+	assert.Less(t, distinct, int32(100))
+	assert.Equal(t, noStoreRequests.Load(), distinctNoStore)
+}
+
+func TestRequestCoalescingNotShareable_module(t *testing.T) {
+	testRequestCoalescingNotShareable(t, &testOptions{})
+}
+func TestRequestCoalescingNotShareable_worker(t *testing.T) {
+	testRequestCoalescingNotShareable(t, &testOptions{workerScript: "coalescing.php"})
+}
+func testRequestCoalescingNotShareable(t *testing.T, opts *testOptions) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+	marker := filepath.Join(t.TempDir(), "marker")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestCoalescing(),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	var (
+		bodies    sync.Map
+		collapsed atomic.Int32
+	)
+	opts.nbParallelRequests = 10
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, _ int) {
+		body, resp := testGet("http://example.com/coalescing.php?first-no-store="+url.QueryEscape(marker), handler, t)
+		bodies.Store(body, struct{}{})
+		if resp.Header.Get("Cache-Status") == "FrankenPHP; fwd=uri-miss; collapsed" {
+			collapsed.Add(1)
+		}
+	}, opts)
+
+	var distinct int32
+	bodies.Range(func(_, _ any) bool { distinct++; return true })
+
+	// the first response can't be shared, the waiting requests are handled by PHP on their own
+	assert.Equal(t, int32(10), distinct)
+	assert.Zero(t, collapsed.Load())
+}
+
+func TestRequestCoalescingWithResponseCache(t *testing.T) {
+	cwd, _ := os.Getwd()
+	testDataDir := cwd + strings.Clone("/testdata/")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req, err := frankenphp.NewRequestWithContext(r,
+			frankenphp.WithRequestDocumentRoot(testDataDir, false),
+			frankenphp.WithRequestCoalescing(),
+		)
+		assert.NoError(t, err)
+
+		err = frankenphp.ServeHTTP(w, req)
+		assert.NoError(t, err)
+	}
+
+	var stored, collapsed atomic.Int32
+	runTest(t, func(_ func(http.ResponseWriter, *http.Request), _ *httptest.Server, _ int) {
+		_, resp := testGet("http://example.com/coalescing.php?cached", handler, t)
+		switch resp.Header.Get("Cache-Status") {
+		case "FrankenPHP; fwd=uri-miss; stored":
+			stored.Add(1)
+		case "FrankenPHP; fwd=uri-miss; collapsed":
+			collapsed.Add(1)
+		}
+	}, &testOptions{nbParallelRequests: 10, initOpts: []frankenphp.Option{frankenphp.WithResponseCache(1 << 20)}})
+
+	// only the first request stores the response, the waiting requests keep their own Cache-Status
+	assert.Equal(t, int32(1), stored.Load())
+	assert.Positive(t, collapsed.Load())
+}
+
 type streamResponseRecorder struct {
 	*httptest.ResponseRecorder
 	writeCallback func(buf []byte)
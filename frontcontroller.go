@@ -0,0 +1,57 @@
+package frankenphp
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// frontController is a script handling all requests
+type frontController struct {
+	scriptName string
+	// the script filename and the worker are computed once for a given document root
+	resolved atomic.Pointer[resolvedFrontController]
+}
+
+type resolvedFrontController struct {
+	documentRoot      string
+	scriptFilename    string
+	worker            *worker
+	workersGeneration uint64
+}
+
+// WithRequestFrontController routes the request to a single script, relative to the document root.
+//
+// SCRIPT_NAME and SCRIPT_FILENAME are set to the front controller and PATH_INFO to the full path of the request:
+// the path is not split and the filesystem is not checked. The option should be created once and reused
+// for all requests, SCRIPT_FILENAME and the matching worker are then only computed when the document root changes.
+func WithRequestFrontController(scriptName string) RequestOption {
+	f := &frontController{scriptName: "/" + strings.TrimPrefix(scriptName, "/")}
+
+	return func(o *frankenPHPContext) error {
+		o.frontController = f
+
+		return nil
+	}
+}
+
+// apply sets the CGI path variables of the request
+func (f *frontController) apply(fc *frankenPHPContext) {
+	r := f.resolved.Load()
+	generation := workersGeneration.Load()
+	if r == nil || r.documentRoot != fc.documentRoot || r.workersGeneration != generation {
+		scriptFilename := sanitizedPathJoin(fc.documentRoot, f.scriptName)
+		r = &resolvedFrontController{
+			documentRoot:      fc.documentRoot,
+			scriptFilename:    scriptFilename,
+			worker:            getWorkerByPath(scriptFilename),
+			workersGeneration: generation,
+		}
+		f.resolved.Store(r)
+	}
+
+	fc.scriptName = f.scriptName
+	fc.scriptFilename = r.scriptFilename
+	fc.docURI = f.scriptName
+	fc.pathInfo = fc.request.URL.Path
+	fc.worker = r.worker
+}
@@ -0,0 +1,114 @@
+package frankenphp
+
+import (
+	"fmt"
+)
+
+// readinessMaxQueueDepth is the number of queued requests from which FrankenPHP is not ready, 0 means the number of threads
+var readinessMaxQueueDepth int
+
+// HealthReport describes whether FrankenPHP is able to accept new requests.
+type HealthReport struct {
+	// Healthy is true if the PHP runtime is running
+	Healthy bool
+	// Ready is true if all workers are ready and requests are not piling up
+	Ready bool
+	// Reasons explains why FrankenPHP is not ready
+	Reasons        []string `json:",omitempty"`
+	QueuedRequests int
+	MaxQueueDepth  int
+	Workers        []WorkerHealthReport `json:",omitempty"`
+}
+
+// WorkerHealthReport describes the state of the threads of a single worker.
+type WorkerHealthReport struct {
+	Name         string
+	Threads      int
+	ReadyThreads int
+	// FailingThreads is the number of threads in crash backoff
+	FailingThreads int
+	QueuedRequests int
+}
+
+// IsHealthy returns true if the PHP runtime is running. It doesn't use any PHP thread.
+func IsHealthy() bool {
+	return mainThread != nil && mainThread.state.is(stateReady)
+}
+
+// Health reports whether FrankenPHP is ready to handle requests without using any PHP thread.
+//
+// FrankenPHP is ready when all worker threads have reached frankenphp_handle_request(),
+// no worker thread is restarting after a crash and the number of queued requests
+// is below the configured maximum queue depth.
+func Health() HealthReport {
+	report := HealthReport{
+		Healthy: IsHealthy(),
+		Workers: make([]WorkerHealthReport, 0, len(workers)),
+	}
+	if !report.Healthy {
+		report.Reasons = append(report.Reasons, "PHP is not running")
+
+		return report
+	}
+
+	threadCount := 0
+	for _, thread := range phpThreads {
+		if !thread.state.is(stateReserved) {
+			threadCount++
+		}
+	}
+
+	report.MaxQueueDepth = readinessMaxQueueDepth
+	if report.MaxQueueDepth == 0 {
+		report.MaxQueueDepth = threadCount
+	}
+
+	report.QueuedRequests = int(queuedRegularRequests.Load())
+	for _, w := range workers {
+		workerReport := w.health()
+		report.Workers = append(report.Workers, workerReport)
+		report.QueuedRequests += workerReport.QueuedRequests
+
+		if workerReport.ReadyThreads < workerReport.Threads {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("worker %q: %d of %d threads are ready", w.name, workerReport.ReadyThreads, workerReport.Threads))
+		}
+		if workerReport.FailingThreads > 0 {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("worker %q: %d threads are in crash backoff", w.name, workerReport.FailingThreads))
+		}
+	}
+
+	if report.QueuedRequests >= report.MaxQueueDepth {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d requests are queued (max %d)", report.QueuedRequests, report.MaxQueueDepth))
+	}
+
+	report.Ready = len(report.Reasons) == 0
+
+	return report
+}
+
+func (worker *worker) health() WorkerHealthReport {
+	report := WorkerHealthReport{
+		Name:           worker.name,
+		QueuedRequests: int(worker.queuedRequests.Load()),
+	}
+
+	worker.threadMutex.RLock()
+	defer worker.threadMutex.RUnlock()
+
+	report.Threads = len(worker.threads)
+	for _, thread := range worker.threads {
+		if thread.state.is(stateReady) {
+			report.ReadyThreads++
+		}
+
+		thread.handlerMu.Lock()
+		handler, ok := thread.handler.(*workerThread)
+		thread.handlerMu.Unlock()
+
+		if ok && handler.backoff.failures() > 0 {
+			report.FailingThreads++
+		}
+	}
+
+	return report
+}
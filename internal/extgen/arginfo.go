@@ -13,6 +13,28 @@ type arginfoGenerator struct {
 }
 
 func (ag *arginfoGenerator) generate() error {
+	stubFile := ag.generator.BaseName + ".stub.php"
+	arginfoPath := filepath.Join(ag.generator.BuildDir, ag.generator.BaseName+"_arginfo.h")
+
+	// running gen_stub.php is slow, skip it if the stub didn't change
+	if _, err := os.Stat(arginfoPath); err == nil && !ag.generator.stubUpdated {
+		return nil
+	}
+
+	if err := ag.runGenStub(stubFile); err != nil {
+		// the arginfo file doesn't match the stub anymore, remove it to run gen_stub.php again on the next generation,
+		// even if the stub didn't change in between
+		if rmErr := os.Remove(arginfoPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			return fmt.Errorf("%w (removing stale arginfo file: %w)", err, rmErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (ag *arginfoGenerator) runGenStub(stubFile string) error {
 	genStubPath := os.Getenv("GEN_STUB_SCRIPT")
 	if genStubPath == "" {
 		genStubPath = "/usr/local/src/php/build/gen_stub.php"
@@ -22,7 +44,6 @@ func (ag *arginfoGenerator) generate() error {
 		return fmt.Errorf(`the PHP "gen_stub.php" file couldn't be found under %q, you can set the "GEN_STUB_SCRIPT" environement variable to set a custom location`, genStubPath)
 	}
 
-	stubFile := ag.generator.BaseName + ".stub.php"
 	cmd := exec.Command("php", genStubPath, filepath.Join(ag.generator.BuildDir, stubFile))
 
 	if err := cmd.Run(); err != nil {
@@ -0,0 +1,40 @@
+package extgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArginfoGeneratorSkipsUpToDateFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GEN_STUB_SCRIPT", filepath.Join(tmpDir, "missing_gen_stub.php"))
+
+	arginfoPath := filepath.Join(tmpDir, "test_extension_arginfo.h")
+	require.NoError(t, os.WriteFile(arginfoPath, []byte("/* arginfo */"), 0644))
+
+	generator := arginfoGenerator{&Generator{BaseName: "test_extension", BuildDir: tmpDir}}
+	require.NoError(t, generator.generate())
+
+	assert.FileExists(t, arginfoPath)
+}
+
+func TestArginfoGeneratorRemovesStaleFileOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GEN_STUB_SCRIPT", filepath.Join(tmpDir, "missing_gen_stub.php"))
+
+	arginfoPath := filepath.Join(tmpDir, "test_extension_arginfo.h")
+	require.NoError(t, os.WriteFile(arginfoPath, []byte("/* stale arginfo */"), 0644))
+
+	g := &Generator{BaseName: "test_extension", BuildDir: tmpDir, stubUpdated: true}
+	generator := arginfoGenerator{g}
+	assert.ErrorContains(t, generator.generate(), "gen_stub.php")
+	assert.NoFileExists(t, arginfoPath, "the stale arginfo file must be removed")
+
+	// the next generation must run gen_stub.php again, even if the stub didn't change
+	g.stubUpdated = false
+	assert.ErrorContains(t, generator.generate(), "gen_stub.php")
+}
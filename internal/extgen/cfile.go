@@ -6,6 +6,7 @@ import (
 	"bytes"
 	_ "embed"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 )
@@ -18,11 +19,16 @@ type cFileGenerator struct {
 }
 
 type cTemplateData struct {
-	BaseName  string
-	Functions []phpFunction
-	Classes   []phpClass
-	Constants []phpConstant
-	Namespace string
+	BaseName          string
+	Functions         []phpFunction
+	Classes           []phpClass
+	Constants         []phpConstant
+	Enums             []phpEnum
+	Namespace         string
+	IniEntries        []phpIniEntry
+	ThrowsExceptions  bool
+	ExposesProperties bool
+	ReturnsHookErrors bool
 }
 
 func (cg *cFileGenerator) generate() error {
@@ -32,7 +38,7 @@ func (cg *cFileGenerator) generate() error {
 		return err
 	}
 
-	return writeFile(filename, content)
+	return writeFileWithUserCode(filename, content)
 }
 
 func (cg *cFileGenerator) buildContent() (string, error) {
@@ -52,25 +58,92 @@ func (cg *cFileGenerator) buildContent() (string, error) {
 		builder.WriteString(fnGen.generate(fn))
 	}
 
+	builder.WriteString("/* BEGIN USER CODE: code */\n/* END USER CODE: code */\n")
+
 	return builder.String(), nil
 }
 
 func (cg *cFileGenerator) getTemplateContent() (string, error) {
 	funcMap := sprig.FuncMap()
 	funcMap["namespacedClassName"] = NamespacedName
+	funcMap["paramDeclarations"] = cg.paramParser().generateParamDeclarations
+	funcMap["paramParsing"] = cg.paramParsing
+	funcMap["callParams"] = cg.paramParser().generateGoCallParams
+	funcMap["methodCallArgs"] = cg.methodCallArgs
+	funcMap["interfaceClassEntries"] = cg.interfaceClassEntries
+	funcMap["hooks"] = cg.hooks
 
 	tmpl := template.Must(template.New("cfile").Funcs(funcMap).Parse(cFileContent))
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, cTemplateData{
-		BaseName:  cg.generator.BaseName,
-		Functions: cg.generator.Functions,
-		Classes:   cg.generator.Classes,
-		Constants: cg.generator.Constants,
-		Namespace: cg.generator.Namespace,
+		BaseName:         cg.generator.BaseName,
+		Functions:        cg.generator.Functions,
+		Classes:          cg.generator.Classes,
+		Constants:        cg.generator.Constants,
+		Enums:            cg.generator.Enums,
+		Namespace:        cg.generator.Namespace,
+		IniEntries:       cg.generator.IniEntries,
+		ThrowsExceptions: slices.ContainsFunc(cg.generator.Functions, func(fn phpFunction) bool { return fn.ReturnsError }),
+		ExposesProperties: slices.ContainsFunc(cg.generator.Classes, func(class phpClass) bool {
+			return len(class.ExposedProperties()) > 0
+		}),
+		ReturnsHookErrors: slices.ContainsFunc(cg.generator.Hooks, func(hook phpHook) bool { return hook.ReturnsError }),
 	}); err != nil {
 		return "", err
 	}
 
 	return buf.String(), nil
 }
+
+// hooks returns the lifecycle hooks of the given kind, in declaration order
+func (cg *cFileGenerator) hooks(kind phpHookKind) []phpHook {
+	var hooks []phpHook
+	for _, hook := range cg.generator.Hooks {
+		if hook.Kind == kind {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	return hooks
+}
+
+func (cg *cFileGenerator) paramParser() *ParameterParser {
+	return &ParameterParser{}
+}
+
+func (cg *cFileGenerator) paramParsing(params []phpParameter) string {
+	pp := cg.paramParser()
+
+	return pp.generateParamParsing(params, pp.analyzeParameters(params).RequiredCount)
+}
+
+// methodCallArgs returns the arguments passed to the Go wrapper of a method: the handle of the object
+// for instance methods, the parameters, and the return value for mixed values, set by the wrapper
+func (cg *cFileGenerator) methodCallArgs(method phpClassMethod) string {
+	var args []string
+	if !method.IsStatic {
+		args = append(args, "intern->go_handle")
+	}
+
+	if params := cg.paramParser().generateGoCallParams(method.Params); params != "" {
+		args = append(args, params)
+	}
+
+	if method.ReturnType == phpMixed {
+		args = append(args, "return_value")
+	}
+
+	return strings.Join(args, ", ")
+}
+
+// interfaceClassEntries returns the class entries of the interfaces implemented by the class,
+// in the order of the arguments of the register function generated by gen_stub.php
+func (cg *cFileGenerator) interfaceClassEntries(class phpClass) string {
+	entries := make([]string, 0, len(class.Implements))
+	for _, name := range class.Implements {
+		entries = append(entries, phpInterfaces[name].ClassEntry)
+	}
+
+	return strings.Join(entries, ", ")
+}
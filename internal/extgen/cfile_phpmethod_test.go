@@ -1,8 +1,11 @@
 package extgen
 
 import (
-	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCFile_NamespacedPHPMethods(t *testing.T) {
@@ -184,3 +187,58 @@ func TestCFile_PHP_METHOD_Integration(t *testing.T) {
 		require.NotContains(t, fullContent, old, "Did not expect to find old declaration %q in full C file content", old)
 	}
 }
+
+func TestCFile_ClassInterfacesStaticMethodsAndConstructor(t *testing.T) {
+	generator := &Generator{
+		BaseName: "test_extension",
+		Classes: []phpClass{
+			{
+				Name:       "Bag",
+				GoStruct:   "BagStruct",
+				Implements: []string{"Countable", "Stringable"},
+				Constructor: &phpClassMethod{
+					Name:    "__construct",
+					PhpName: "__construct",
+					Params: []phpParameter{
+						{Name: "name", PhpType: phpString},
+						{Name: "size", PhpType: phpInt, HasDefault: true, DefaultValue: "0"},
+					},
+				},
+				Properties: []phpClassProperty{
+					{Name: "Name", PhpType: phpString, PhpName: "name", Visibility: "public", IsReadonly: true},
+					{Name: "Size", PhpType: phpInt, PhpName: "size", Visibility: "public"},
+				},
+				Methods: []phpClassMethod{
+					{Name: "count", PhpName: "count", ReturnType: phpInt, ClassName: "Bag"},
+					{Name: "__toString", PhpName: "__toString", ReturnType: phpString, ClassName: "Bag"},
+					{
+						Name:       "fromString",
+						PhpName:    "fromString",
+						ReturnType: phpString,
+						ClassName:  "Bag",
+						IsStatic:   true,
+						Params:     []phpParameter{{Name: "value", PhpType: phpString}},
+					},
+				},
+			},
+		},
+		BuildDir: t.TempDir(),
+	}
+
+	cFileGen := cFileGenerator{generator: generator}
+	content, err := cFileGen.buildContent()
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "Bag_ce = register_class_Bag(zend_ce_countable, zend_ce_stringable);")
+	assert.Contains(t, content, "intern->go_handle = create_BagStruct_object(name, (long) size);")
+	assert.Contains(t, content, `{"name", true},`)
+	assert.Contains(t, content, `{"size", false},`)
+	assert.Contains(t, content, "go_properties_to_php(&intern->std, Bag_ce, intern->go_handle, Bag_properties, 2, BagStruct_read_property, true);")
+	assert.Contains(t, content, `zend_throw_error(NULL, "Unable to read the %s property: %s", properties[i].name, error);`)
+	assert.Contains(t, content, "zend_long result = count_wrapper(intern->go_handle);")
+	assert.Contains(t, content, "zend_string *result = fromString_wrapper(value);")
+
+	staticMethod := content[strings.Index(content, "PHP_METHOD(Bag, fromString)"):]
+	staticMethod = staticMethod[:strings.Index(staticMethod, "\n}\n")]
+	assert.NotContains(t, staticMethod, "ZEND_THIS", "Static methods must not access the object")
+}
@@ -123,6 +123,44 @@ func TestCFileGenerator_BuildContent(t *testing.T) {
 				`#include "full.h"`,
 			},
 		},
+		{
+			name:     "extension with functions returning errors",
+			baseName: "errors",
+			functions: []phpFunction{
+				{Name: "save", ReturnType: phpVoid, ReturnsError: true},
+			},
+			contains: []string{
+				"#include <ext/spl/spl_exceptions.h>",
+				"static void throw_go_exception(char *message, const char *class_name) {",
+				"zend_throw_exception(ce, message, 0);",
+				"save_wrapper(&exception_message);",
+			},
+		},
+		{
+			name:     "extension with callable parameters",
+			baseName: "callable",
+			functions: []phpFunction{
+				{Name: "each", ReturnType: phpVoid, Params: []phpParameter{
+					{Name: "items", PhpType: phpArray},
+					{Name: "callback", PhpType: phpCallable},
+				}},
+			},
+			classes: []phpClass{
+				{Name: "Emitter", GoStruct: "Emitter", Methods: []phpClassMethod{
+					{Name: "on", PhpName: "on", ClassName: "Emitter", ReturnType: phpVoid, Params: []phpParameter{
+						{Name: "listener", PhpType: phpCallable, IsNullable: true},
+					}},
+				}},
+			},
+			contains: []string{
+				"zend_fcall_info callback_fci = empty_fcall_info;",
+				"Z_PARAM_FUNC(callback_fci, callback_fcc)",
+				"each(items, &callback_fci.function_name);",
+				"zend_fcall_info_cache listener_fcc = empty_fcall_info_cache;",
+				"Z_PARAM_FUNC_OR_NULL(listener_fci, listener_fcc)",
+				"on_wrapper(intern->go_handle, ZEND_FCI_INITIALIZED(listener_fci) ? &listener_fci.function_name : NULL);",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -449,6 +487,47 @@ func TestCFileConstants(t *testing.T) {
 	}
 }
 
+func TestCFileEnums(t *testing.T) {
+	generator := &Generator{
+		BaseName:  "enum_test",
+		Namespace: `Go\Cards`,
+		Enums: []phpEnum{
+			{Name: "Suit", BackingType: phpString, Cases: []phpEnumCase{{"Hearts", "H"}}},
+		},
+	}
+
+	cGen := cFileGenerator{generator}
+	content, err := cGen.buildContent()
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "#include <Zend/zend_enum.h>")
+	assert.Contains(t, content, "static zend_class_entry *Suit_ce = NULL;")
+	assert.Contains(t, content, "Suit_ce = register_class_Go_Cards_Suit();")
+}
+
+func TestCFileEmptyStringEnumReturn(t *testing.T) {
+	// PHPString returns NULL for "", which is a valid case value
+	suit := phpEnum{Name: "Suit", BackingType: phpString, Cases: []phpEnumCase{{"None", ""}, {"Hearts", "H"}}}
+	generator := &Generator{
+		BaseName: "enum_test",
+		Enums:    []phpEnum{suit},
+		Functions: []phpFunction{
+			{Name: "noSuit", ReturnType: "Suit", ReturnEnum: &suit},
+		},
+	}
+
+	cGen := cFileGenerator{generator}
+	content, err := cGen.buildContent()
+	require.NoError(t, err)
+
+	function := content[strings.Index(content, "PHP_FUNCTION(noSuit)"):]
+	function = function[:strings.Index(function, "\n}\n")]
+
+	assert.Contains(t, function, "zend_string *result = noSuit_wrapper();")
+	assert.Contains(t, function, "if (result) {\n        ZVAL_STR(&value, result);\n    } else {\n        ZVAL_EMPTY_STRING(&value);\n    }")
+	assert.Contains(t, function, `zend_call_method_with_1_params(NULL, Suit_ce, NULL, "from", return_value, &value);`)
+}
+
 func TestCFileTemplateErrorHandling(t *testing.T) {
 	generator := &Generator{
 		BaseName: "error_test",
@@ -459,3 +538,48 @@ func TestCFileTemplateErrorHandling(t *testing.T) {
 	_, err := cGen.getTemplateContent()
 	assert.NoError(t, err, "getTemplateContent() should not fail with valid template")
 }
+
+func TestCFileHooksAndIniEntries(t *testing.T) {
+	generator := &Generator{
+		BaseName: "hooks_test",
+		Hooks: []phpHook{
+			{Kind: hookMinit, Name: "open_pool", ReturnsError: true},
+			{Kind: hookMshutdown, Name: "close_pool"},
+			{Kind: hookRinit, Name: "acquire"},
+			{Kind: hookRshutdown, Name: "release"},
+		},
+		IniEntries: []phpIniEntry{
+			{Name: "hooks_test.dsn", DefaultValue: `mysql://"app"`},
+		},
+	}
+
+	cGen := cFileGenerator{generator}
+	content, err := cGen.buildContent()
+	require.NoError(t, err)
+
+	for _, expected := range []string{
+		`PHP_INI_ENTRY("hooks_test.dsn", "mysql://\"app\"", PHP_INI_ALL, NULL)`,
+		"REGISTER_INI_ENTRIES();",
+		`if (go_hook_result(open_pool_hook(), "open_pool") == FAILURE) {`,
+		"PHP_MSHUTDOWN_FUNCTION(hooks_test) {\n    close_pool();\n    UNREGISTER_INI_ENTRIES();",
+		"PHP_RINIT_FUNCTION(hooks_test) {\n    acquire();",
+		"PHP_RSHUTDOWN_FUNCTION(hooks_test) {\n    release();",
+		"DISPLAY_INI_ENTRIES();",
+		"PHP_MSHUTDOWN(hooks_test),",
+		"PHP_RINIT(hooks_test),",
+		"PHP_RSHUTDOWN(hooks_test),",
+		"PHP_MINFO(hooks_test),",
+	} {
+		assert.Contains(t, content, expected)
+	}
+
+	generator.Hooks = nil
+	generator.IniEntries = nil
+	content, err = cGen.buildContent()
+	require.NoError(t, err)
+
+	assert.NotContains(t, content, "PHP_INI_BEGIN()")
+	assert.NotContains(t, content, "go_hook_result")
+	assert.NotContains(t, content, "PHP_RINIT_FUNCTION")
+	assert.NotContains(t, content, "PHP_MSHUTDOWN_FUNCTION")
+}
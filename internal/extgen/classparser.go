@@ -7,13 +7,17 @@ import (
 	"go/parser"
 	"go/token"
 	"os"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 )
 
-var phpClassRegex = regexp.MustCompile(`//\s*export_php:class\s+(\w+)`)
-var phpMethodRegex = regexp.MustCompile(`//\s*export_php:method\s+(\w+)::([^{}\n]+)(?:\s*{\s*})?`)
-var methodSignatureRegex = regexp.MustCompile(`(\w+)\s*\(([^)]*)\)\s*:\s*(\??[\w|]+)`)
+var phpClassRegex = regexp.MustCompile(`//\s*export_php:class\s+(\w+)(?:\s+implements\s+(\\?\w+(?:\s*,\s*\\?\w+)*))?`)
+var phpClassExtendsRegex = regexp.MustCompile(`//\s*export_php:class\s+\w+\s+extends\b`)
+var phpMethodRegex = regexp.MustCompile(`//\s*export_php:(method|static)\s+(\w+)::([^{}\n]+)(?:\s*{\s*})?`)
+var methodSignatureRegex = regexp.MustCompile(`(\w+)\s*\(([^)]*)\)\s*:\s*(\??\\?[\w|]+)`)
+var constructorSignatureRegex = regexp.MustCompile(`^__construct\s*\(([^)]*)\)$`)
 var methodParamTypeNameRegex = regexp.MustCompile(`(\??[\w|]+)\s+\$?(\w+)`)
 
 type exportDirective struct {
@@ -64,29 +68,58 @@ func (cp *classParser) parse(filename string) (classes []phpClass, err error) {
 			}
 
 			var phpCl string
+			var implements []string
 			var directiveLine int
-			if phpCl, directiveLine = cp.extractPHPClassCommentWithLine(genDecl.Doc, fset); phpCl == "" {
+			if phpCl, implements, directiveLine = cp.extractPHPClassCommentWithLine(genDecl.Doc, fset); phpCl == "" {
 				continue
 			}
 
 			matchedDirectives[directiveLine] = true
 
+			if cp.hasExtends(genDecl.Doc) {
+				printWarning(filename, directiveLine, "invalid class %q: extending a class isn't supported, only interfaces can be implemented", phpCl)
+				continue
+			}
+
 			class := phpClass{
-				Name:     phpCl,
-				GoStruct: typeSpec.Name.Name,
+				Name:       phpCl,
+				GoStruct:   typeSpec.Name.Name,
+				Implements: implements,
 			}
 
 			class.Properties = cp.parseStructFields(structType.Fields.List)
 
 			// associate methods with this class
 			for _, method := range methods {
-				if method.ClassName == phpCl {
-					class.Methods = append(class.Methods, method)
+				if method.ClassName != phpCl {
+					continue
 				}
+
+				if method.PhpName == "__construct" {
+					if err := validator.validateConstructor(class, method); err != nil {
+						printWarning(filename, method.lineNumber, "invalid constructor for class %q: %v", class.Name, err)
+
+						continue
+					}
+
+					class.Constructor = &method
+
+					continue
+				}
+
+				class.Methods = append(class.Methods, method)
+			}
+
+			// classes declaring __toString() implicitly implement Stringable
+			hasToString := slices.ContainsFunc(class.Methods, func(method phpClassMethod) bool {
+				return method.PhpName == "__toString" && !method.IsStatic
+			})
+			if hasToString && !slices.Contains(class.Implements, "Stringable") {
+				class.Implements = append(class.Implements, "Stringable")
 			}
 
 			if err := validator.validateClass(class); err != nil {
-				fmt.Printf("Warning: Invalid class '%s': %v\n", class.Name, err)
+				printWarning(filename, directiveLine, "invalid class %q: %v", class.Name, err)
 				continue
 			}
 
@@ -121,19 +154,36 @@ func (cp *classParser) collectExportDirectives(node *ast.File, fset *token.FileS
 	return directives
 }
 
-func (cp *classParser) extractPHPClassCommentWithLine(commentGroup *ast.CommentGroup, fset *token.FileSet) (string, int) {
+func (cp *classParser) extractPHPClassCommentWithLine(commentGroup *ast.CommentGroup, fset *token.FileSet) (string, []string, int) {
 	if commentGroup == nil {
-		return "", 0
+		return "", nil, 0
 	}
 
 	for _, comment := range commentGroup.List {
 		if matches := phpClassRegex.FindStringSubmatch(comment.Text); matches != nil {
+			var implements []string
+			if matches[2] != "" {
+				for iface := range strings.SplitSeq(matches[2], ",") {
+					implements = append(implements, strings.TrimPrefix(strings.TrimSpace(iface), `\`))
+				}
+			}
+
 			pos := fset.Position(comment.Pos())
-			return matches[1], pos.Line
+			return matches[1], implements, pos.Line
+		}
+	}
+
+	return "", nil, 0
+}
+
+func (cp *classParser) hasExtends(commentGroup *ast.CommentGroup) bool {
+	for _, comment := range commentGroup.List {
+		if phpClassExtendsRegex.MatchString(comment.Text) {
+			return true
 		}
 	}
 
-	return "", 0
+	return false
 }
 
 func (cp *classParser) parseStructFields(fields []*ast.Field) []phpClassProperty {
@@ -163,9 +213,38 @@ func (cp *classParser) parseStructField(fieldName string, field *ast.Field) phpC
 
 	prop.PhpType = cp.goTypeToPHPType(prop.GoType)
 
+	if field.Tag != nil {
+		cp.parseStructTag(&prop, reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("php"))
+	}
+
 	return prop
 }
 
+// parseStructTag exposes the field as a PHP property if it has a "php" struct tag,
+// options set the visibility and declare the property as readonly, e.g. `php:"name,readonly,protected"`
+func (cp *classParser) parseStructTag(prop *phpClassProperty, tag string) {
+	if tag == "" || tag == "-" {
+		return
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = prop.Name
+	}
+
+	prop.PhpName = name
+	prop.Visibility = "public"
+
+	for option := range strings.SplitSeq(options, ",") {
+		switch option {
+		case "readonly":
+			prop.IsReadonly = true
+		case "public", "protected", "private":
+			prop.Visibility = option
+		}
+	}
+}
+
 func (cp *classParser) typeToString(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.Ident:
@@ -225,12 +304,19 @@ func (cp *classParser) parseMethods(filename string) (methods []phpClassMethod,
 		line := strings.TrimSpace(scanner.Text())
 
 		if matches := phpMethodRegex.FindStringSubmatch(line); matches != nil {
-			className := strings.TrimSpace(matches[1])
-			signature := strings.TrimSpace(matches[2])
+			className := strings.TrimSpace(matches[2])
+			signature := strings.TrimSpace(matches[3])
 
 			method, err := cp.parseMethodSignature(className, signature)
 			if err != nil {
-				fmt.Printf("Warning: Error parsing method signature %q: %v\n", signature, err)
+				printWarning(filename, lineNumber, "error parsing method signature %q: %v", signature, err)
+
+				continue
+			}
+
+			method.IsStatic = matches[1] == "static"
+			if method.IsStatic && method.PhpName == "__construct" {
+				printWarning(filename, lineNumber, "the constructor of class %q can't be static", className)
 
 				continue
 			}
@@ -244,8 +330,8 @@ func (cp *classParser) parseMethods(filename string) (methods []phpClassMethod,
 				IsReturnNullable: method.isReturnNullable,
 			}
 
-			if err := validator.validateScalarTypes(phpFunc); err != nil {
-				fmt.Printf("Warning: Method \"%s::%s\" uses unsupported types: %v\n", className, method.Name, err)
+			if err := validator.validateMethodTypes(phpFunc); err != nil {
+				printWarning(filename, lineNumber, "method \"%s::%s\" uses unsupported types: %v", className, method.Name, err)
 
 				continue
 			}
@@ -263,6 +349,18 @@ func (cp *classParser) parseMethods(filename string) (methods []phpClassMethod,
 			currentMethod.GoFunction = goFunc
 
 			validator := Validator{}
+			if currentMethod.PhpName == "__construct" {
+				// the constructor is validated with the class it returns
+				if goFunc, err := validator.parseGoFunction(currentMethod.GoFunction); err == nil {
+					currentMethod.GoName = goFunc.Name.Name
+				}
+
+				methods = append(methods, *currentMethod)
+				currentMethod = nil
+
+				continue
+			}
+
 			phpFunc := phpFunction{
 				Name:             currentMethod.Name,
 				Signature:        currentMethod.Signature,
@@ -272,12 +370,24 @@ func (cp *classParser) parseMethods(filename string) (methods []phpClassMethod,
 				IsReturnNullable: currentMethod.isReturnNullable,
 			}
 
-			if err := validator.validateGoFunctionSignatureWithOptions(phpFunc, true); err != nil {
-				fmt.Printf("Warning: Go method signature mismatch for '%s::%s': %v\n", currentMethod.ClassName, currentMethod.Name, err)
+			if err := validator.validateGoFunctionSignatureWithOptions(phpFunc, !currentMethod.IsStatic); err != nil {
+				printWarning(filename, currentMethod.lineNumber, "Go method signature mismatch for '%s::%s': %v", currentMethod.ClassName, currentMethod.Name, err)
 				currentMethod = nil
 				continue
 			}
 
+			if goFunc, err := validator.parseGoFunction(currentMethod.GoFunction); err == nil {
+				if currentMethod.IsStatic && goFunc.Recv != nil {
+					printWarning(filename, currentMethod.lineNumber, "static method '%s::%s' must be mapped to a function, not to a method", currentMethod.ClassName, currentMethod.Name)
+					currentMethod = nil
+					continue
+				}
+
+				if currentMethod.IsStatic || goFunc.Recv != nil {
+					currentMethod.GoName = goFunc.Name.Name
+				}
+			}
+
 			methods = append(methods, *currentMethod)
 			currentMethod = nil
 		}
@@ -291,18 +401,30 @@ func (cp *classParser) parseMethods(filename string) (methods []phpClassMethod,
 }
 
 func (cp *classParser) parseMethodSignature(className, signature string) (*phpClassMethod, error) {
-	matches := methodSignatureRegex.FindStringSubmatch(signature)
+	var methodName, paramsStr, returnTypeStr string
+	if matches := constructorSignatureRegex.FindStringSubmatch(signature); matches != nil {
+		// constructors have no return type
+		methodName = "__construct"
+		paramsStr = strings.TrimSpace(matches[1])
+		returnTypeStr = string(phpVoid)
+	} else {
+		matches := methodSignatureRegex.FindStringSubmatch(signature)
 
-	if len(matches) != 4 {
-		return nil, fmt.Errorf("invalid method signature format")
-	}
+		if len(matches) != 4 {
+			return nil, fmt.Errorf("invalid method signature format")
+		}
 
-	methodName := matches[1]
-	paramsStr := strings.TrimSpace(matches[2])
-	returnTypeStr := strings.TrimSpace(matches[3])
+		methodName = matches[1]
+		paramsStr = strings.TrimSpace(matches[2])
+		returnTypeStr = strings.TrimSpace(matches[3])
+	}
 
 	isReturnNullable := strings.HasPrefix(returnTypeStr, "?")
-	returnType := strings.TrimPrefix(returnTypeStr, "?")
+	returnType := strings.TrimPrefix(strings.TrimPrefix(returnTypeStr, "?"), `\`)
+	if returnType == string(phpIterator) {
+		// the stub must not resolve Iterator in the namespace of the extension
+		signature = strings.TrimSuffix(signature, returnTypeStr) + `\Iterator`
+	}
 
 	var params []phpParameter
 	if paramsStr != "" {
@@ -639,3 +639,344 @@ func validFloat(tc *TestClass, value float64) float64 {
 		})
 	}
 }
+
+func TestClassParserStaticMethodsAndConstructor(t *testing.T) {
+	input := []byte(`package main
+
+//export_php:class Temperature
+type TemperatureStruct struct {
+	Celsius float64
+}
+
+//export_php:method Temperature::__construct(float $celsius = 0.0)
+func NewTemperature(celsius float64) *TemperatureStruct {
+	return &TemperatureStruct{Celsius: celsius}
+}
+
+//export_php:static Temperature::fromFahrenheit(float $fahrenheit): float
+func FromFahrenheit(fahrenheit float64) float64 {
+	return (fahrenheit - 32) * 5 / 9
+}
+
+//export_php:method Temperature::getCelsius(): float
+func (t *TemperatureStruct) Celsius() float64 {
+	return t.Celsius
+}`)
+
+	tmpDir := t.TempDir()
+	fileName := filepath.Join(tmpDir, "test.go")
+	require.NoError(t, os.WriteFile(fileName, input, 0644))
+
+	parser := classParser{}
+	classes, err := parser.parse(fileName)
+	require.NoError(t, err)
+	require.Len(t, classes, 1)
+
+	class := classes[0]
+	require.NotNil(t, class.Constructor, "Expected a constructor")
+	assert.Equal(t, "NewTemperature", class.Constructor.GoName)
+	assert.Equal(t, "__construct(float $celsius = 0.0)", class.Constructor.Signature)
+	require.Len(t, class.Constructor.Params, 1)
+	assert.True(t, class.Constructor.Params[0].HasDefault)
+
+	require.Len(t, class.Methods, 2, "The constructor must not be listed in the methods")
+
+	fromFahrenheit := class.Methods[0]
+	assert.True(t, fromFahrenheit.IsStatic)
+	assert.Equal(t, "FromFahrenheit", fromFahrenheit.GoName)
+
+	getCelsius := class.Methods[1]
+	assert.False(t, getCelsius.IsStatic)
+	assert.Equal(t, "Celsius", getCelsius.GoName)
+}
+
+func TestClassParserInvalidStaticMethodsAndConstructor(t *testing.T) {
+	tests := []struct {
+		name              string
+		input             string
+		expectedMethods   int
+		expectConstructor bool
+	}{
+		{
+			name: "static method mapped to a Go method",
+			input: `package main
+
+//export_php:class TestClass
+type TestClass struct{}
+
+//export_php:static TestClass::create(int $value): int
+func (tc *TestClass) Create(value int64) int64 {
+	return value
+}`,
+		},
+		{
+			name: "static constructor",
+			input: `package main
+
+//export_php:class TestClass
+type TestClass struct{}
+
+//export_php:static TestClass::__construct()
+func NewTestClass() *TestClass {
+	return &TestClass{}
+}`,
+		},
+		{
+			name: "constructor returning another type",
+			input: `package main
+
+//export_php:class TestClass
+type TestClass struct{}
+
+//export_php:method TestClass::__construct(int $value)
+func NewTestClass(value int64) TestClass {
+	return TestClass{}
+}`,
+		},
+		{
+			name: "constructor parameters mismatch",
+			input: `package main
+
+//export_php:class TestClass
+type TestClass struct{}
+
+//export_php:method TestClass::__construct(int $value)
+func NewTestClass(value string) *TestClass {
+	return &TestClass{}
+}`,
+		},
+		{
+			name: "constructor mapped to a method",
+			input: `package main
+
+//export_php:class TestClass
+type TestClass struct{}
+
+//export_php:method TestClass::__construct()
+func (tc *TestClass) Init() *TestClass {
+	return tc
+}`,
+		},
+		{
+			name: "valid static method",
+			input: `package main
+
+//export_php:class TestClass
+type TestClass struct{}
+
+//export_php:static TestClass::create(int $value): int
+func Create(value int64) int64 {
+	return value
+}`,
+			expectedMethods: 1,
+		},
+		{
+			name: "valid constructor without parameters",
+			input: `package main
+
+//export_php:class TestClass
+type TestClass struct{}
+
+//export_php:method TestClass::__construct()
+func NewTestClass() *TestClass {
+	return &TestClass{}
+}`,
+			expectConstructor: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			fileName := filepath.Join(tmpDir, "test.go")
+			require.NoError(t, os.WriteFile(fileName, []byte(tt.input), 0644))
+
+			parser := &classParser{}
+			classes, err := parser.parse(fileName)
+			require.NoError(t, err)
+			require.Len(t, classes, 1)
+
+			assert.Len(t, classes[0].Methods, tt.expectedMethods)
+			assert.Equal(t, tt.expectConstructor, classes[0].Constructor != nil)
+		})
+	}
+}
+
+func TestClassParserInterfaces(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              string
+		expectedClasses    int
+		expectedImplements []string
+	}{
+		{
+			name: "countable class",
+			input: `package main
+
+//export_php:class Bag implements Countable
+type BagStruct struct{}
+
+//export_php:method Bag::count(): int
+func (b *BagStruct) Count() int64 {
+	return 0
+}`,
+			expectedClasses:    1,
+			expectedImplements: []string{"Countable"},
+		},
+		{
+			name: "multiple interfaces with leading backslashes",
+			input: `package main
+
+//export_php:class Bag implements \Countable, \IteratorAggregate
+type BagStruct struct{}
+
+//export_php:method Bag::count(): int
+func (b *BagStruct) Count() int64 {
+	return 0
+}
+
+//export_php:method Bag::getIterator(): \Iterator
+func (b *BagStruct) GetIterator() unsafe.Pointer {
+	return nil
+}`,
+			expectedClasses:    1,
+			expectedImplements: []string{"Countable", "IteratorAggregate"},
+		},
+		{
+			name: "array access",
+			input: `package main
+
+//export_php:class Bag implements ArrayAccess
+type BagStruct struct{}
+
+//export_php:method Bag::offsetExists(mixed $offset): bool
+func (b *BagStruct) OffsetExists(offset *C.zval) bool {
+	return false
+}
+
+//export_php:method Bag::offsetGet(mixed $offset): mixed
+func (b *BagStruct) OffsetGet(offset *C.zval) any {
+	return nil
+}
+
+//export_php:method Bag::offsetSet(mixed $offset, mixed $value): void
+func (b *BagStruct) OffsetSet(offset *C.zval, value *C.zval) {
+}
+
+//export_php:method Bag::offsetUnset(mixed $offset): void
+func (b *BagStruct) OffsetUnset(offset *C.zval) {
+}`,
+			expectedClasses:    1,
+			expectedImplements: []string{"ArrayAccess"},
+		},
+		{
+			name: "__toString implicitly implements Stringable",
+			input: `package main
+
+//export_php:class Name
+type NameStruct struct{}
+
+//export_php:method Name::__toString(): string
+func (n *NameStruct) String() unsafe.Pointer {
+	return nil
+}`,
+			expectedClasses:    1,
+			expectedImplements: []string{"Stringable"},
+		},
+		{
+			name: "missing interface method",
+			input: `package main
+
+//export_php:class Bag implements Countable
+type BagStruct struct{}`,
+			expectedClasses: 0,
+		},
+		{
+			name: "wrong return type of interface method",
+			input: `package main
+
+//export_php:class Bag implements Countable
+type BagStruct struct{}
+
+//export_php:method Bag::count(): string
+func (b *BagStruct) Count() unsafe.Pointer {
+	return nil
+}`,
+			expectedClasses: 0,
+		},
+		{
+			name: "unsupported interface",
+			input: `package main
+
+//export_php:class Bag implements JsonSerializable
+type BagStruct struct{}`,
+			expectedClasses: 0,
+		},
+		{
+			name: "extending a class is not supported",
+			input: `package main
+
+//export_php:class Bag extends ArrayObject implements Countable
+type BagStruct struct{}
+
+//export_php:method Bag::count(): int
+func (b *BagStruct) Count() int64 {
+	return 0
+}`,
+			expectedClasses: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			fileName := filepath.Join(tmpDir, "test.go")
+			require.NoError(t, os.WriteFile(fileName, []byte(tt.input), 0644))
+
+			parser := &classParser{}
+			classes, err := parser.parse(fileName)
+			require.NoError(t, err)
+			require.Len(t, classes, tt.expectedClasses)
+
+			if tt.expectedClasses > 0 {
+				assert.Equal(t, tt.expectedImplements, classes[0].Implements)
+			}
+		})
+	}
+}
+
+func TestClassParserPropertyTags(t *testing.T) {
+	input := []byte(`package main
+
+//export_php:class User
+type UserStruct struct {
+	ID       int64   ` + "`php:\"id,readonly\"`" + `
+	Name     string  ` + "`php:\",protected\"`" + `
+	Nickname *string ` + "`php:\"nickname\"`" + `
+	Password string  ` + "`php:\"-\"`" + `
+	secret   string
+}`)
+
+	tmpDir := t.TempDir()
+	fileName := filepath.Join(tmpDir, "test.go")
+	require.NoError(t, os.WriteFile(fileName, input, 0644))
+
+	parser := classParser{}
+	classes, err := parser.parse(fileName)
+	require.NoError(t, err)
+	require.Len(t, classes, 1)
+
+	props := classes[0].ExposedProperties()
+	require.Len(t, props, 3, "Only fields with a php tag must be exposed")
+
+	assert.Equal(t, "id", props[0].PhpName)
+	assert.Equal(t, "public", props[0].Visibility)
+	assert.True(t, props[0].IsReadonly)
+
+	assert.Equal(t, "Name", props[1].PhpName, "The name of the field must be used when the tag has no name")
+	assert.Equal(t, "protected", props[1].Visibility)
+	assert.False(t, props[1].IsReadonly)
+
+	assert.Equal(t, "nickname", props[2].PhpName)
+	assert.True(t, props[2].IsNullable)
+}
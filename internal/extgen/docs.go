@@ -15,9 +15,11 @@ type DocumentationGenerator struct {
 }
 
 type DocTemplateData struct {
-	BaseName  string
-	Functions []phpFunction
-	Classes   []phpClass
+	BaseName   string
+	Functions  []phpFunction
+	Classes    []phpClass
+	Enums      []phpEnum
+	IniEntries []phpIniEntry
 }
 
 func (dg *DocumentationGenerator) generate() error {
@@ -35,9 +37,11 @@ func (dg *DocumentationGenerator) generateMarkdown() (string, error) {
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, DocTemplateData{
-		BaseName:  dg.generator.BaseName,
-		Functions: dg.generator.Functions,
-		Classes:   dg.generator.Classes,
+		BaseName:   dg.generator.BaseName,
+		Functions:  dg.generator.Functions,
+		Classes:    dg.generator.Classes,
+		Enums:      dg.generator.Enums,
+		IniEntries: dg.generator.IniEntries,
 	}); err != nil {
 		return "", err
 	}
@@ -278,6 +278,50 @@ func TestDocumentationGenerator_GenerateMarkdown(t *testing.T) {
 				"**Properties:**",
 			},
 		},
+		{
+			name: "class with interfaces, constructor and static methods",
+			generator: &Generator{
+				BaseName: "classext",
+				Classes: []phpClass{
+					{
+						Name:        "Bag",
+						Implements:  []string{"Countable", "Stringable"},
+						Constructor: &phpClassMethod{Signature: "__construct(string $name)"},
+						Properties: []phpClassProperty{
+							{Name: "Name", PhpType: phpString, PhpName: "name", Visibility: "public", IsReadonly: true},
+						},
+						Methods: []phpClassMethod{
+							{Signature: "count(): int"},
+							{Signature: "fromString(string $value): string", IsStatic: true},
+						},
+					},
+				},
+			},
+			contains: []string{
+				"**Implements:** `Countable`, `Stringable`",
+				"**Constructor:**",
+				"__construct(string $name)",
+				"- `name`: string (public, readonly)",
+				"**Methods:**",
+				"- `count(): int`",
+				"- `static fromString(string $value): string`",
+			},
+		},
+		{
+			name: "INI settings",
+			generator: &Generator{
+				BaseName: "testextension",
+				IniEntries: []phpIniEntry{
+					{Name: "testextension.pool_size", DefaultValue: "10"},
+					{Name: "testextension.prefix"},
+				},
+			},
+			contains: []string{
+				"## INI Settings",
+				"- `testextension.pool_size` (default: `10`)",
+				"- `testextension.prefix` (default: empty)",
+			},
+		},
 	}
 
 	for _, tt := range tests {
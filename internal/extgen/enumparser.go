@@ -0,0 +1,169 @@
+package extgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+)
+
+var phpEnumRegex = regexp.MustCompile(`//\s*export_php:enum\s+(\w+)`)
+
+type EnumParser struct{}
+
+// parse returns the enums declared in the files, which must belong to the same package
+// so that the cases can be declared in another file than the type
+func (ep *EnumParser) parse(filenames ...string) ([]phpEnum, error) {
+	fset := token.NewFileSet()
+	nodes := make([]*ast.File, 0, len(filenames))
+	for _, filename := range filenames {
+		node, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parsing file: %w", err)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	// type-check the files to evaluate the values of the cases, including iota;
+	// errors are ignored because the imports (C, frankenphp...) can't be resolved here
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	conf := types.Config{
+		Importer:    failingImporter{},
+		FakeImportC: true,
+		Error:       func(error) {},
+	}
+	_, _ = conf.Check(nodes[0].Name.Name, fset, nodes, info)
+
+	validator := Validator{}
+
+	var enums []phpEnum
+	for _, node := range nodes {
+		enums = append(enums, ep.parseFile(fset, node, nodes, info, validator)...)
+	}
+
+	return enums, nil
+}
+
+func (ep *EnumParser) parseFile(fset *token.FileSet, node *ast.File, nodes []*ast.File, info *types.Info, validator Validator) []phpEnum {
+	var enums []phpEnum
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = genDecl.Doc
+			}
+
+			name := ep.extractEnumName(doc)
+			if name == "" {
+				continue
+			}
+
+			position := fset.Position(typeSpec.Pos())
+			enum := phpEnum{Name: name, GoType: typeSpec.Name.Name, filename: position.Filename}
+
+			// the type of the enum must be defined from a predeclared string or integer type
+			if ident, ok := typeSpec.Type.(*ast.Ident); ok {
+				if typeName, ok := types.Universe.Lookup(ident.Name).(*types.TypeName); ok {
+					if basic, ok := typeName.Type().(*types.Basic); ok {
+						enum.GoBackingType = ident.Name
+
+						switch {
+						case basic.Info()&types.IsString != 0:
+							enum.BackingType = phpString
+						case basic.Info()&types.IsInteger != 0:
+							enum.BackingType = phpInt
+						}
+					}
+				}
+			}
+
+			for _, n := range nodes {
+				enum.Cases = append(enum.Cases, ep.collectCases(n, info, enum)...)
+			}
+
+			if err := validator.validateEnum(enum); err != nil {
+				printWarning(position.Filename, position.Line, "invalid enum %q: %v", enum.Name, err)
+
+				continue
+			}
+
+			enums = append(enums, enum)
+		}
+	}
+
+	return enums
+}
+
+func (ep *EnumParser) extractEnumName(commentGroup *ast.CommentGroup) string {
+	if commentGroup == nil {
+		return ""
+	}
+
+	for _, comment := range commentGroup.List {
+		if matches := phpEnumRegex.FindStringSubmatch(comment.Text); matches != nil {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
+
+// collectCases returns the constants of the type of the enum, in the order of declaration
+func (ep *EnumParser) collectCases(node *ast.File, info *types.Info, enum phpEnum) []phpEnumCase {
+	var cases []phpEnumCase
+	for _, decl := range node.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			for _, ident := range spec.(*ast.ValueSpec).Names {
+				c, ok := info.Defs[ident].(*types.Const)
+				if !ok {
+					continue
+				}
+
+				named, ok := c.Type().(*types.Named)
+				if !ok || named.Obj().Name() != enum.GoType {
+					continue
+				}
+
+				enumCase := phpEnumCase{Name: ident.Name}
+				if c.Val().Kind() == constant.String {
+					enumCase.Value = constant.StringVal(c.Val())
+				} else {
+					enumCase.Value = c.Val().ExactString()
+				}
+
+				cases = append(cases, enumCase)
+			}
+		}
+	}
+
+	return cases
+}
+
+type failingImporter struct{}
+
+func (failingImporter) Import(path string) (*types.Package, error) {
+	return nil, fmt.Errorf("package %q can't be imported", path)
+}
@@ -0,0 +1,186 @@
+package extgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []phpEnum
+	}{
+		{
+			name: "string-backed enum",
+			input: `package main
+
+//export_php:enum Suit
+type Suit string
+
+const (
+	Hearts Suit = "H"
+	Spades Suit = "S"
+)`,
+			expected: []phpEnum{{
+				Name:          "Suit",
+				GoType:        "Suit",
+				GoBackingType: "string",
+				BackingType:   phpString,
+				Cases:         []phpEnumCase{{"Hearts", "H"}, {"Spades", "S"}},
+			}},
+		},
+		{
+			name: "string-backed enum with an empty case",
+			input: `package main
+
+//export_php:enum Suit
+type Suit string
+
+const (
+	NoSuit Suit = ""
+	Hearts Suit = "H"
+)`,
+			expected: []phpEnum{{
+				Name:          "Suit",
+				GoType:        "Suit",
+				GoBackingType: "string",
+				BackingType:   phpString,
+				Cases:         []phpEnumCase{{"NoSuit", ""}, {"Hearts", "H"}},
+			}},
+		},
+		{
+			name: "int-backed enum with iota",
+			input: `package main
+
+import "C"
+
+//export_php:enum Level
+type Level uint8
+
+const (
+	Low Level = iota + 1
+	Medium
+	High
+)
+
+const Unrelated = 42`,
+			expected: []phpEnum{{
+				Name:          "Level",
+				GoType:        "Level",
+				GoBackingType: "uint8",
+				BackingType:   phpInt,
+				Cases:         []phpEnumCase{{"Low", "1"}, {"Medium", "2"}, {"High", "3"}},
+			}},
+		},
+		{
+			name: "PHP name different from the Go type",
+			input: `package main
+
+//export_php:enum Status
+type status int
+
+const active status = 1`,
+			expected: []phpEnum{{
+				Name:          "Status",
+				GoType:        "status",
+				GoBackingType: "int",
+				BackingType:   phpInt,
+				Cases:         []phpEnumCase{{"active", "1"}},
+			}},
+		},
+		{
+			name: "float type",
+			input: `package main
+
+//export_php:enum Ratio
+type Ratio float64
+
+const Half Ratio = 0.5`,
+		},
+		{
+			name: "no cases",
+			input: `package main
+
+//export_php:enum Suit
+type Suit string`,
+		},
+		{
+			name: "duplicated values",
+			input: `package main
+
+//export_php:enum Suit
+type Suit string
+
+const (
+	Hearts Suit = "H"
+	Spades Suit = "H"
+)`,
+		},
+		{
+			name: "type without directive",
+			input: `package main
+
+type Suit string
+
+const Hearts Suit = "H"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			fileName := filepath.Join(tmpDir, "test.go")
+			require.NoError(t, os.WriteFile(fileName, []byte(tt.input), 0644))
+
+			parser := &EnumParser{}
+			enums, err := parser.parse(fileName)
+			require.NoError(t, err)
+
+			for i := range tt.expected {
+				tt.expected[i].filename = fileName
+			}
+			assert.Equal(t, tt.expected, enums)
+		})
+	}
+}
+
+func TestFuncParserEnums(t *testing.T) {
+	input := `package main
+
+//export_php:function opposite(Suit $suit, int $times): Suit
+func opposite(suit Suit, times int64) Suit {
+	return suit
+}
+
+//export_php:function nullable(?Suit $suit): void
+func nullable(suit Suit) {
+}
+
+//export_php:function mismatch(Suit $suit): void
+func mismatch(suit string) {
+}`
+
+	tmpDir := t.TempDir()
+	fileName := filepath.Join(tmpDir, "test.go")
+	require.NoError(t, os.WriteFile(fileName, []byte(input), 0644))
+
+	enums := []phpEnum{{Name: "Suit", GoType: "Suit", GoBackingType: "string", BackingType: phpString}}
+
+	parser := &FuncParser{enums: enums}
+	functions, err := parser.parse(fileName)
+	require.NoError(t, err)
+	require.Len(t, functions, 1, "Only functions using enums with the matching Go type must be exported")
+
+	fn := functions[0]
+	assert.Equal(t, "opposite", fn.Name)
+	require.NotNil(t, fn.Params[0].Enum)
+	assert.Equal(t, "Suit", fn.Params[0].Enum.Name)
+	assert.Nil(t, fn.Params[1].Enum)
+	require.NotNil(t, fn.ReturnEnum)
+	assert.True(t, fn.needsWrapper())
+}
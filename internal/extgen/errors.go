@@ -2,6 +2,12 @@ package extgen
 
 import "fmt"
 
+// printWarning prints a warning about the directive at the given line of the source file,
+// the "file:line:" prefix is recognized by editors and terminals
+func printWarning(filename string, line int, format string, args ...any) {
+	fmt.Printf("%s:%d: warning: %s\n", filename, line, fmt.Sprintf(format, args...))
+}
+
 type GeneratorError struct {
 	Stage   string
 	Message string
@@ -3,6 +3,9 @@ package extgen
 import (
 	"bufio"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"regexp"
 	"strings"
@@ -11,8 +14,11 @@ import (
 var phpFuncRegex = regexp.MustCompile(`//\s*export_php:function\s+([^{}\n]+)(?:\s*{\s*})?`)
 var signatureRegex = regexp.MustCompile(`(\w+)\s*\(([^)]*)\)\s*:\s*(\??[\w|]+)`)
 var typeNameRegex = regexp.MustCompile(`(\??[\w|]+)\s+\$?(\w+)`)
+var phpThrowsRegex = regexp.MustCompile(`^//\s*export_php:throws\s+(\\?[a-zA-Z_][a-zA-Z0-9_]*(?:\\[a-zA-Z_][a-zA-Z0-9_]*)*)\s*$`)
 
-type FuncParser struct{}
+type FuncParser struct {
+	enums []phpEnum
+}
 
 func (fp *FuncParser) parse(filename string) (functions []phpFunction, err error) {
 	file, err := os.Open(filename)
@@ -39,19 +45,21 @@ func (fp *FuncParser) parse(filename string) (functions []phpFunction, err error
 			signature := strings.TrimSpace(matches[1])
 			phpFunc, err := fp.parseSignature(signature)
 			if err != nil {
-				fmt.Printf("Warning: Error parsing signature '%s': %v\n", signature, err)
+				printWarning(filename, lineNumber, "error parsing signature '%s': %v", signature, err)
 
 				continue
 			}
 
+			fp.resolveEnums(phpFunc)
+
 			if err := validator.validateFunction(*phpFunc); err != nil {
-				fmt.Printf("Warning: Invalid function '%s': %v\n", phpFunc.Name, err)
+				printWarning(filename, lineNumber, "invalid function '%s': %v", phpFunc.Name, err)
 
 				continue
 			}
 
 			if err := validator.validateScalarTypes(*phpFunc); err != nil {
-				fmt.Printf("Warning: Function '%s' uses unsupported types: %v\n", phpFunc.Name, err)
+				printWarning(filename, lineNumber, "function '%s' uses unsupported types: %v", phpFunc.Name, err)
 
 				continue
 			}
@@ -60,6 +68,14 @@ func (fp *FuncParser) parse(filename string) (functions []phpFunction, err error
 			currentPHPFunc = phpFunc
 		}
 
+		if currentPHPFunc != nil {
+			if matches := phpThrowsRegex.FindStringSubmatch(line); matches != nil {
+				currentPHPFunc.ThrowsClass = strings.TrimPrefix(matches[1], `\`)
+
+				continue
+			}
+		}
+
 		if currentPHPFunc != nil && strings.HasPrefix(line, "func ") {
 			goFunc, err := fp.extractGoFunction(scanner, line)
 			if err != nil {
@@ -67,14 +83,19 @@ func (fp *FuncParser) parse(filename string) (functions []phpFunction, err error
 			}
 
 			currentPHPFunc.GoFunction = goFunc
+			currentPHPFunc.ReturnsError = fp.returnsError(goFunc)
 
 			if err := validator.validateGoFunctionSignatureWithOptions(*currentPHPFunc, false); err != nil {
-				fmt.Printf("Warning: Go function signature mismatch for %q: %v\n", currentPHPFunc.Name, err)
+				printWarning(filename, currentPHPFunc.lineNumber, "Go function signature mismatch for %q: %v", currentPHPFunc.Name, err)
 				currentPHPFunc = nil
 
 				continue
 			}
 
+			if currentPHPFunc.ThrowsClass != "" && !currentPHPFunc.ReturnsError {
+				printWarning(filename, currentPHPFunc.lineNumber, "//export_php:throws directive ignored for %q: the Go function doesn't return an error", currentPHPFunc.Name)
+			}
+
 			functions = append(functions, *currentPHPFunc)
 			currentPHPFunc = nil
 		}
@@ -87,6 +108,25 @@ func (fp *FuncParser) parse(filename string) (functions []phpFunction, err error
 	return functions, scanner.Err()
 }
 
+// resolveEnums links the parameters and the return value using an enum as type to its declaration
+func (fp *FuncParser) resolveEnums(fn *phpFunction) {
+	for i := range fn.Params {
+		fn.Params[i].Enum = fp.findEnum(fn.Params[i].PhpType)
+	}
+
+	fn.ReturnEnum = fp.findEnum(fn.ReturnType)
+}
+
+func (fp *FuncParser) findEnum(t phpType) *phpEnum {
+	for i := range fp.enums {
+		if fp.enums[i].Name == string(t) {
+			return &fp.enums[i]
+		}
+	}
+
+	return nil
+}
+
 func (fp *FuncParser) extractGoFunction(scanner *bufio.Scanner, firstLine string) (string, error) {
 	goFunc := firstLine + "\n"
 	braceCount := 1
@@ -112,6 +152,32 @@ func (fp *FuncParser) extractGoFunction(scanner *bufio.Scanner, firstLine string
 	return goFunc, nil
 }
 
+// returnsError checks if the last value returned by the Go function is an error
+func (fp *FuncParser) returnsError(goFunction string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package main\n"+goFunction, 0)
+	if err != nil {
+		return false
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		results := funcDecl.Type.Results
+		if results == nil || len(results.List) == 0 {
+			return false
+		}
+
+		ident, ok := results.List[len(results.List)-1].Type.(*ast.Ident)
+
+		return ok && ident.Name == "error"
+	}
+
+	return false
+}
+
 func (fp *FuncParser) parseSignature(signature string) (*phpFunction, error) {
 	matches := signatureRegex.FindStringSubmatch(signature)
 
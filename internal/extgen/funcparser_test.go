@@ -484,3 +484,52 @@ func validFloat(value float64) float64 {
 		})
 	}
 }
+
+func TestFunctionParserErrorReturn(t *testing.T) {
+	input := `package main
+
+//export_php:function divide(int $a, int $b): int
+//export_php:throws \App\DivisionByZero
+func divide(a int64, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+
+	return a / b, nil
+}
+
+//export_php:function save(string $data): void
+func save(data *C.zend_string) error {
+	return nil
+}
+
+//export_php:function mismatch(int $a): int
+func mismatch(a int64) (string, error) {
+	return "", nil
+}
+
+//export_php:function noError(int $a): int
+//export_php:throws LogicException
+func noError(a int64) int64 {
+	return a
+}`
+
+	fileName := filepath.Join(t.TempDir(), "errors.go")
+	require.NoError(t, os.WriteFile(fileName, []byte(input), 0644))
+
+	parser := &FuncParser{}
+	functions, err := parser.parse(fileName)
+	require.NoError(t, err)
+	require.Len(t, functions, 3, "the function with a mismatched return type should be rejected")
+
+	assert.Equal(t, "divide", functions[0].Name)
+	assert.True(t, functions[0].ReturnsError)
+	assert.Equal(t, `App\DivisionByZero`, functions[0].ThrowsClass)
+
+	assert.Equal(t, "save", functions[1].Name)
+	assert.True(t, functions[1].ReturnsError)
+	assert.Empty(t, functions[1].ThrowsClass)
+
+	assert.Equal(t, "noError", functions[2].Name)
+	assert.False(t, functions[2].ReturnsError)
+}
@@ -3,21 +3,30 @@ package extgen
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 const BuildDir = "build"
 
 type Generator struct {
 	BaseName   string
-	SourceFile string
+	SourceFile string // a Go file, or a directory containing the package of the extension and its subpackages
 	BuildDir   string
 	Functions  []phpFunction
 	Classes    []phpClass
 	Constants  []phpConstant
+	Enums      []phpEnum
+	Hooks      []phpHook
+	IniEntries []phpIniEntry
 	Namespace  string
+
+	stubUpdated bool // the stub changed since the previous generation, the arginfo file must be regenerated
 }
 
-// EXPERIMENTAL
+// EXPERIMENTAL: Generate generates the extension in the build directory.
+//
+// Files that are already up to date are left untouched, and the user code regions of the C and Go files are preserved,
+// so Generate can be called again when the source changes.
 func (g *Generator) Generate() error {
 	if err := g.setupBuildDirectory(); err != nil {
 		return fmt.Errorf("setup build directory: %w", err)
@@ -26,8 +35,8 @@ func (g *Generator) Generate() error {
 		return fmt.Errorf("parse source: %w", err)
 	}
 
-	if len(g.Functions) == 0 && len(g.Classes) == 0 && len(g.Constants) == 0 {
-		return fmt.Errorf("no PHP functions, classes, or constants found in source file")
+	if len(g.Functions) == 0 && len(g.Classes) == 0 && len(g.Constants) == 0 && len(g.Enums) == 0 && len(g.Hooks) == 0 && len(g.IniEntries) == 0 {
+		return fmt.Errorf("no PHP functions, classes, constants, enums, lifecycle hooks or INI settings found in source file")
 	}
 
 	generators := []struct {
@@ -40,6 +49,7 @@ func (g *Generator) Generate() error {
 		{"C file", g.generateCFile},
 		{"Go file", g.generateGoFile},
 		{"documentation", g.generateDocumentation},
+		{"tests", g.generateTests},
 	}
 
 	for _, gen := range generators {
@@ -52,39 +62,156 @@ func (g *Generator) Generate() error {
 }
 
 func (g *Generator) setupBuildDirectory() error {
-	if err := os.RemoveAll(g.BuildDir); err != nil {
-		return fmt.Errorf("removing build directory: %w", err)
-	}
-
 	return os.MkdirAll(g.BuildDir, 0755)
 }
 
 func (g *Generator) parseSource() error {
+	packages, err := collectSourcePackages(g.SourceFile, g.BuildDir)
+	if err != nil {
+		return fmt.Errorf("collecting source files: %w", err)
+	}
+
+	g.Functions, g.Classes, g.Constants, g.Enums, g.Hooks, g.IniEntries, g.Namespace = nil, nil, nil, nil, nil, nil, ""
+
 	parser := SourceParser{}
 
-	functions, err := parser.ParseFunctions(g.SourceFile)
+	// enums are parsed first because functions can use them as parameter and return types
+	for _, pkg := range packages {
+		enums, err := parser.ParseEnums(pkg.Files...)
+		if err != nil {
+			return fmt.Errorf("parsing enums: %w", err)
+		}
+		g.Enums = append(g.Enums, enums...)
+	}
+
+	names := make(declaredNames)
+	for _, enum := range g.Enums {
+		if err := names.declare("class", enum.Name, enum.filename); err != nil {
+			return err
+		}
+	}
+
+	namespaceFile := ""
+	analyzer := SourceAnalyzer{}
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			if err := g.parseFile(parser, file, names); err != nil {
+				return err
+			}
+
+			// the functions and variables of all the files are merged into the generated Go file
+			identifiers, err := analyzer.declaredIdentifiers(file)
+			if err != nil {
+				return fmt.Errorf("analyzing source file: %w", err)
+			}
+			for _, identifier := range identifiers {
+				if err := names.declare("Go identifier", identifier, file); err != nil {
+					return err
+				}
+			}
+
+			ns, err := parser.ParseNamespace(file)
+			if err != nil {
+				return fmt.Errorf("parsing namespace: %w", err)
+			}
+
+			if ns == "" {
+				continue
+			}
+
+			if g.Namespace != "" && ns != g.Namespace {
+				return fmt.Errorf("conflicting namespaces %q in %s and %q in %s", g.Namespace, namespaceFile, ns, file)
+			}
+			g.Namespace, namespaceFile = ns, file
+		}
+	}
+
+	return nil
+}
+
+// parseFile adds the functions, classes, constants, lifecycle hooks and INI settings declared in the file to the extension
+func (g *Generator) parseFile(parser SourceParser, file string, names declaredNames) error {
+	functions, err := parser.ParseFunctions(file, g.Enums)
 	if err != nil {
 		return fmt.Errorf("parsing functions: %w", err)
 	}
-	g.Functions = functions
+	for _, function := range functions {
+		if err := names.declare("function", function.Name, file); err != nil {
+			return err
+		}
+	}
+	g.Functions = append(g.Functions, functions...)
 
-	classes, err := parser.ParseClasses(g.SourceFile)
+	classes, err := parser.ParseClasses(file)
 	if err != nil {
 		return fmt.Errorf("parsing classes: %w", err)
 	}
-	g.Classes = classes
+	for _, class := range classes {
+		if err := names.declare("class", class.Name, file); err != nil {
+			return err
+		}
+	}
+	g.Classes = append(g.Classes, classes...)
 
-	constants, err := parser.ParseConstants(g.SourceFile)
+	constants, err := parser.ParseConstants(file)
 	if err != nil {
 		return fmt.Errorf("parsing constants: %w", err)
 	}
-	g.Constants = constants
+	for _, constant := range constants {
+		name := constant.Name
+		if constant.ClassName != "" {
+			name = constant.ClassName + "::" + name
+		}
+
+		if err := names.declare("constant", name, file); err != nil {
+			return err
+		}
+	}
+	g.Constants = append(g.Constants, constants...)
 
-	ns, err := parser.ParseNamespace(g.SourceFile)
+	hooks, err := parser.ParseHooks(file)
 	if err != nil {
-		return fmt.Errorf("parsing namespace: %w", err)
+		return fmt.Errorf("parsing lifecycle hooks: %w", err)
 	}
-	g.Namespace = ns
+	g.Hooks = append(g.Hooks, hooks...)
+
+	iniEntries, err := parser.ParseIniEntries(file)
+	if err != nil {
+		return fmt.Errorf("parsing INI settings: %w", err)
+	}
+	for _, entry := range iniEntries {
+		if err := names.declare("INI setting", entry.Name, file); err != nil {
+			return err
+		}
+	}
+	g.IniEntries = append(g.IniEntries, iniEntries...)
+
+	return nil
+}
+
+// declaredNames maps the PHP names and the Go identifiers declared by the extension to the file declaring them,
+// all the files are compiled in a single module so names must be unique across files
+type declaredNames map[string]string
+
+func (n declaredNames) declare(kind, name, file string) error {
+	key := kind + " " + name
+	if kind == "function" || kind == "class" {
+		// function and class names are case-insensitive in PHP
+		key = strings.ToLower(key)
+	}
+
+	if previous, ok := n[key]; ok {
+		if !strings.HasPrefix(kind, "Go ") {
+			kind = "PHP " + kind
+		}
+
+		if previous == file {
+			return fmt.Errorf("duplicate %s %q declared twice in %s", kind, name, file)
+		}
+
+		return fmt.Errorf("duplicate %s %q declared in %s and %s", kind, name, previous, file)
+	}
+	n[key] = file
 
 	return nil
 }
@@ -142,3 +269,12 @@ func (g *Generator) generateDocumentation() error {
 
 	return nil
 }
+
+func (g *Generator) generateTests() error {
+	generator := phptGenerator{g}
+	if err := generator.generate(); err != nil {
+		return &GeneratorError{"tests generation", "failed to generate tests", err}
+	}
+
+	return nil
+}
@@ -4,7 +4,12 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"path/filepath"
+	"slices"
+	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
@@ -22,9 +27,11 @@ type goTemplateData struct {
 	BaseName          string
 	Imports           []string
 	Constants         []phpConstant
+	Enums             []phpEnum
 	Variables         []string
 	InternalFunctions []string
 	Functions         []phpFunction
+	Hooks             []phpHook
 	Classes           []phpClass
 }
 
@@ -35,16 +42,37 @@ func (gg *GoFileGenerator) generate() error {
 		return fmt.Errorf("building Go file content: %w", err)
 	}
 
-	return writeFile(filename, content)
+	return writeFileWithUserCode(filename, content)
 }
 
 func (gg *GoFileGenerator) buildContent() (string, error) {
-	sourceAnalyzer := SourceAnalyzer{}
-	imports, variables, internalFunctions, err := sourceAnalyzer.analyze(gg.generator.SourceFile)
+	packages, err := collectSourcePackages(gg.generator.SourceFile, gg.generator.BuildDir)
 	if err != nil {
-		return "", fmt.Errorf("analyzing source file: %w", err)
+		return "", fmt.Errorf("collecting source files: %w", err)
+	}
+
+	// all the source files are merged into the generated package
+	var imports, variables, internalFunctions []string
+	sourceAnalyzer := SourceAnalyzer{}
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			fileImports, fileVariables, fileInternalFunctions, err := sourceAnalyzer.analyze(file)
+			if err != nil {
+				return "", fmt.Errorf("analyzing source file: %w", err)
+			}
+
+			for _, imp := range fileImports {
+				if !slices.Contains(imports, imp) {
+					imports = append(imports, imp)
+				}
+			}
+			variables = append(variables, fileVariables...)
+			internalFunctions = append(internalFunctions, fileInternalFunctions...)
+		}
 	}
 
+	imports, qualifiers := mergeSubpackageImports(imports, packages)
+
 	filteredImports := make([]string, 0, len(imports))
 	for _, imp := range imports {
 		if imp != `"C"` {
@@ -60,9 +88,11 @@ func (gg *GoFileGenerator) buildContent() (string, error) {
 		BaseName:          gg.generator.BaseName,
 		Imports:           filteredImports,
 		Constants:         gg.generator.Constants,
+		Enums:             gg.generator.Enums,
 		Variables:         variables,
 		InternalFunctions: internalFunctions,
 		Functions:         gg.generator.Functions,
+		Hooks:             gg.generator.Hooks,
 		Classes:           classes,
 	})
 
@@ -70,7 +100,7 @@ func (gg *GoFileGenerator) buildContent() (string, error) {
 		return "", fmt.Errorf("executing template: %w", err)
 	}
 
-	return templateContent, nil
+	return unqualifySubpackageReferences(templateContent, qualifiers)
 }
 
 func (gg *GoFileGenerator) getTemplateContent(data goTemplateData) (string, error) {
@@ -79,9 +109,19 @@ func (gg *GoFileGenerator) getTemplateContent(data goTemplateData) (string, erro
 	funcMap["isStringOrArray"] = func(t phpType) bool {
 		return t == phpString || t == phpArray
 	}
+	funcMap["isPointerReturn"] = func(t phpType) bool {
+		return t == phpString || t == phpArray || t == phpIterator
+	}
 	funcMap["isVoid"] = func(t phpType) bool {
 		return t == phpVoid
 	}
+	funcMap["needsWrapper"] = func(fn phpFunction) bool {
+		return fn.needsWrapper()
+	}
+	funcMap["functionWrapper"] = gg.functionWrapper
+	funcMap["wrapperParams"] = gg.wrapperParams
+	funcMap["wrapperArgs"] = gg.wrapperArgs
+	funcMap["goMethodName"] = gg.goMethodName
 
 	tmpl := template.Must(template.New("gofile").Funcs(funcMap).Parse(goFileContent))
 
@@ -121,3 +161,178 @@ func (gg *GoFileGenerator) phpTypeToGoType(phpT phpType) string {
 
 	return "interface{}"
 }
+
+// wrapperParams returns the parameters of the Go wrapper receiving the arguments passed by the C code
+func (gg *GoFileGenerator) wrapperParams(params []phpParameter) string {
+	goParams := make([]string, 0, len(params))
+	for _, param := range params {
+		var goType string
+		switch param.PhpType {
+		case phpString:
+			goType = "*C.zend_string"
+		case phpArray, phpCallable, phpMixed:
+			goType = "*C.zval"
+		default:
+			goType = gg.phpTypeToGoType(param.PhpType)
+			if param.IsNullable {
+				goType = "*" + goType
+			}
+		}
+
+		goParams = append(goParams, param.Name+" "+goType)
+	}
+
+	return strings.Join(goParams, ", ")
+}
+
+// wrapperArgs returns the parameters of the Go wrapper of a method, see cFileGenerator.methodCallArgs
+func (gg *GoFileGenerator) wrapperArgs(method phpClassMethod) string {
+	var params []string
+	if !method.IsStatic {
+		params = append(params, "handle C.uintptr_t")
+	}
+
+	if p := gg.wrapperParams(method.Params); p != "" {
+		params = append(params, p)
+	}
+
+	if method.ReturnType == phpMixed {
+		params = append(params, "returnValue *C.zval")
+	}
+
+	return strings.Join(params, ", ")
+}
+
+// goMethodName returns the name of the Go method or function implementing a PHP method
+func (gg *GoFileGenerator) goMethodName(method phpClassMethod) string {
+	if method.GoName != "" {
+		return method.GoName
+	}
+
+	return strings.ToUpper(method.Name[:1]) + method.Name[1:]
+}
+
+// functionWrapper generates the exported function calling a Go function that can't be called directly by the C code:
+// enum cases are converted from and to their values, and the message of the returned error is passed to the C code that throws it as an exception
+func (gg *GoFileGenerator) functionWrapper(fn phpFunction) (string, error) {
+	src := "package main\n" + fn.GoFunction
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return "", fmt.Errorf("parsing Go function %q: %w", fn.Name, err)
+	}
+
+	var goFunc *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			goFunc = funcDecl
+			break
+		}
+	}
+	if goFunc == nil {
+		return "", fmt.Errorf("go function %q not found", fn.Name)
+	}
+
+	var results []*ast.Field
+	if goFunc.Type.Results != nil {
+		results = goFunc.Type.Results.List
+	}
+	if fn.ReturnsError {
+		if len(results) == 0 {
+			return "", fmt.Errorf("go function %q doesn't return an error", fn.Name)
+		}
+
+		results = results[:len(results)-1]
+	}
+
+	source := func(node ast.Node) string {
+		return src[fset.Position(node.Pos()).Offset:fset.Position(node.End()).Offset]
+	}
+
+	var params, args []string
+	addParam := func(name string, field *ast.Field) {
+		i := len(args)
+		if i >= len(fn.Params) || fn.Params[i].Enum == nil {
+			params = append(params, name+" "+source(field.Type))
+			args = append(args, name)
+
+			return
+		}
+
+		enum := fn.Params[i].Enum
+		if enum.BackingType == phpString {
+			params = append(params, name+" *C.zend_string")
+			args = append(args, fmt.Sprintf("%s(frankenphp.GoString(unsafe.Pointer(%s)))", enum.GoType, name))
+
+			return
+		}
+
+		params = append(params, name+" int64")
+		args = append(args, fmt.Sprintf("%s(%s)", enum.GoType, name))
+	}
+
+	for i, field := range goFunc.Type.Params.List {
+		if len(field.Names) == 0 {
+			addParam(fmt.Sprintf("p%d", i), field)
+
+			continue
+		}
+
+		for _, name := range field.Names {
+			addParam(name.Name, field)
+		}
+	}
+	if fn.ReturnsError {
+		params = append(params, "exceptionMessage **C.char")
+	}
+
+	call := fmt.Sprintf("%s(%s)", fn.Name, strings.Join(args, ", "))
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("//export %s_wrapper\nfunc %s_wrapper(%s)", fn.Name, fn.Name, strings.Join(params, ", ")))
+
+	if len(results) == 0 {
+		if fn.ReturnsError {
+			builder.WriteString(fmt.Sprintf(` {
+	if err := %s; err != nil {
+		*exceptionMessage = C.CString(err.Error())
+	}
+}`, call))
+		} else {
+			builder.WriteString(fmt.Sprintf(" {\n\t%s\n}", call))
+		}
+
+		return builder.String(), nil
+	}
+
+	resultType, result := source(results[0].Type), "result"
+	if enum := fn.ReturnEnum; enum != nil {
+		if enum.BackingType == phpString {
+			resultType, result = "unsafe.Pointer", "frankenphp.PHPString(string(result), false)"
+		} else {
+			resultType, result = "int64", "int64(result)"
+		}
+	}
+
+	if !fn.ReturnsError {
+		builder.WriteString(fmt.Sprintf(` %s {
+	result := %s
+
+	return %s
+}`, resultType, call, result))
+
+		return builder.String(), nil
+	}
+
+	builder.WriteString(fmt.Sprintf(` %s {
+	result, err := %s
+	if err != nil {
+		*exceptionMessage = C.CString(err.Error())
+	}
+
+	return %s
+}`, resultType, call, result))
+
+	return builder.String(), nil
+}
@@ -663,6 +663,113 @@ func (nas *NullableArrayStruct) ProcessOptionalArray(items frankenphp.Associativ
 	assert.Contains(t, content, "//export ProcessOptionalArray_wrapper", "Generated content should contain export directive")
 }
 
+func TestGoFileGenerator_MethodWrapperWithCallableParams(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	sourceContent := `package main
+
+//export_php:class Emitter
+type EmitterStruct struct{}
+
+//export_php:method Emitter::on(string $event, callable $listener): void
+func (es *EmitterStruct) On(event *C.zend_string, listener *C.zval) {
+}`
+
+	sourceFile := filepath.Join(tmpDir, "test.go")
+	require.NoError(t, os.WriteFile(sourceFile, []byte(sourceContent), 0644))
+
+	methods := []phpClassMethod{
+		{
+			Name:       "On",
+			PhpName:    "on",
+			ClassName:  "Emitter",
+			Signature:  "on(string $event, callable $listener): void",
+			ReturnType: phpVoid,
+			Params: []phpParameter{
+				{Name: "event", PhpType: phpString},
+				{Name: "listener", PhpType: phpCallable},
+			},
+			GoFunction: `func (es *EmitterStruct) On(event *C.zend_string, listener *C.zval) {
+}`,
+		},
+	}
+
+	generator := &Generator{
+		BaseName:   "callable_test",
+		SourceFile: sourceFile,
+		Classes:    []phpClass{{Name: "Emitter", GoStruct: "EmitterStruct", Methods: methods}},
+		BuildDir:   tmpDir,
+	}
+
+	goGen := GoFileGenerator{generator}
+	content, err := goGen.buildContent()
+	require.NoError(t, err)
+
+	expectedWrapperSignature := "func On_wrapper(handle C.uintptr_t, event *C.zend_string, listener *C.zval)"
+	assert.Contains(t, content, expectedWrapperSignature, "Generated content should contain callable wrapper signature: %s", expectedWrapperSignature)
+
+	expectedCall := "structObj.On(event, listener)"
+	assert.Contains(t, content, expectedCall, "Generated content should contain method call: %s", expectedCall)
+}
+
+func TestGoFileGenerator_ErrorWrapper(t *testing.T) {
+	sourceFile := createTempSourceFile(t, `package main
+
+//export_php:function divide(int $a, int $b): int
+func divide(a, b int64) (int64, error) {
+	return a / b, nil
+}
+
+//export_php:function save(string $data): void
+func save(data *C.zend_string) error {
+	return nil
+}`)
+
+	generator := &Generator{
+		BaseName:   "errors",
+		SourceFile: sourceFile,
+		Functions: []phpFunction{
+			{
+				Name:         "divide",
+				ReturnType:   phpInt,
+				ReturnsError: true,
+				GoFunction: `func divide(a, b int64) (int64, error) {
+	return a / b, nil
+}`,
+			},
+			{
+				Name:         "save",
+				ReturnType:   phpVoid,
+				ReturnsError: true,
+				GoFunction: `func save(data *C.zend_string) error {
+	return nil
+}`,
+			},
+		},
+	}
+
+	goGen := GoFileGenerator{generator}
+	content, err := goGen.buildContent()
+	require.NoError(t, err)
+
+	assert.NotContains(t, content, "//export divide\n", "functions returning an error must not be exported directly")
+	assert.Contains(t, content, `//export divide_wrapper
+func divide_wrapper(a int64, b int64, exceptionMessage **C.char) int64 {
+	result, err := divide(a, b)
+	if err != nil {
+		*exceptionMessage = C.CString(err.Error())
+	}
+
+	return result
+}`)
+	assert.Contains(t, content, `//export save_wrapper
+func save_wrapper(data *C.zend_string, exceptionMessage **C.char) {
+	if err := save(data); err != nil {
+		*exceptionMessage = C.CString(err.Error())
+	}
+}`)
+}
+
 func createTempSourceFile(t *testing.T, content string) string {
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "source.go")
@@ -724,3 +831,209 @@ func testGoFileInternalFunctions(t *testing.T, content string) {
 		t.Log("No internal functions found (this may be expected)")
 	}
 }
+
+func TestGoFileGenerator_StaticMethodsConstructorAndProperties(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	sourceContent := `package main
+
+//export_php:class Point
+type PointStruct struct {
+	X int64 ` + "`php:\"x\"`" + `
+}
+
+//export_php:method Point::__construct(int $x)
+func NewPoint(x int64) *PointStruct {
+	return &PointStruct{X: x}
+}
+
+//export_php:static Point::origin(): int
+func Origin() int64 {
+	return 0
+}
+
+//export_php:method Point::getX(): int
+func (p *PointStruct) X() int64 {
+	return p.X
+}`
+
+	sourceFile := filepath.Join(tmpDir, "test.go")
+	require.NoError(t, os.WriteFile(sourceFile, []byte(sourceContent), 0644))
+
+	generator := &Generator{
+		BaseName:   "point_test",
+		SourceFile: sourceFile,
+		Classes: []phpClass{{
+			Name:     "Point",
+			GoStruct: "PointStruct",
+			Constructor: &phpClassMethod{
+				Name:       "__construct",
+				PhpName:    "__construct",
+				GoName:     "NewPoint",
+				Params:     []phpParameter{{Name: "x", PhpType: phpInt}},
+				GoFunction: "func NewPoint(x int64) *PointStruct {\n\treturn &PointStruct{X: x}\n}",
+			},
+			Properties: []phpClassProperty{
+				{Name: "X", PhpType: phpInt, GoType: "int64", PhpName: "x", Visibility: "public"},
+			},
+			Methods: []phpClassMethod{
+				{
+					Name:       "origin",
+					PhpName:    "origin",
+					GoName:     "Origin",
+					ClassName:  "Point",
+					ReturnType: phpInt,
+					IsStatic:   true,
+					GoFunction: "func Origin() int64 {\n\treturn 0\n}",
+				},
+				{
+					Name:       "getX",
+					PhpName:    "getX",
+					GoName:     "X",
+					ClassName:  "Point",
+					ReturnType: phpInt,
+					GoFunction: "func (p *PointStruct) X() int64 {\n\treturn p.X\n}",
+				},
+			},
+		}},
+		BuildDir: tmpDir,
+	}
+
+	goGen := GoFileGenerator{generator}
+	content, err := goGen.buildContent()
+	require.NoError(t, err)
+
+	expected := []string{
+		"func create_PointStruct_object(x int64) C.uintptr_t {",
+		"obj := NewPoint(x)",
+		"func NewPoint(x int64) *PointStruct {",
+		"func origin_wrapper() int64 {\n\treturn Origin()\n}",
+		"func getX_wrapper(handle C.uintptr_t) int64 {",
+		"return structObj.X()",
+		"//export PointStruct_read_property",
+		"func PointStruct_read_property(handle C.uintptr_t, index C.int, value *C.zval) *C.char {",
+		"v = obj.X",
+		"return C.CString(err.Error())",
+		"//export PointStruct_write_property",
+		"frankenphp.GoValue[int64](unsafe.Pointer(value))",
+	}
+
+	for _, e := range expected {
+		assert.Contains(t, content, e, "Generated content should contain %q", e)
+	}
+}
+
+func TestGoFileGenerator_EnumWrapper(t *testing.T) {
+	sourceFile := createTempSourceFile(t, `package main
+
+//export_php:enum Suit
+type Suit string
+
+//export_php:enum Level
+type Level uint8
+
+//export_php:function opposite(Suit $suit, int $times): Suit
+func opposite(suit Suit, times int64) Suit {
+	return suit
+}
+
+//export_php:function next(Level $level): Level
+func next(level Level) (Level, error) {
+	return level + 1, nil
+}`)
+
+	suit := phpEnum{Name: "Suit", GoType: "Suit", GoBackingType: "string", BackingType: phpString, Cases: []phpEnumCase{{"Hearts", "H"}, {"Spades", "S"}}}
+	level := phpEnum{Name: "Level", GoType: "Level", GoBackingType: "uint8", BackingType: phpInt, Cases: []phpEnumCase{{"Low", "1"}, {"High", "2"}}}
+
+	generator := &Generator{
+		BaseName:   "enums",
+		SourceFile: sourceFile,
+		Enums:      []phpEnum{suit, level},
+		Functions: []phpFunction{
+			{
+				Name:       "opposite",
+				ReturnType: "Suit",
+				ReturnEnum: &suit,
+				Params: []phpParameter{
+					{Name: "suit", PhpType: "Suit", Enum: &suit},
+					{Name: "times", PhpType: phpInt},
+				},
+				GoFunction: `func opposite(suit Suit, times int64) Suit {
+	return suit
+}`,
+			},
+			{
+				Name:         "next",
+				ReturnType:   "Level",
+				ReturnEnum:   &level,
+				ReturnsError: true,
+				Params:       []phpParameter{{Name: "level", PhpType: "Level", Enum: &level}},
+				GoFunction: `func next(level Level) (Level, error) {
+	return level + 1, nil
+}`,
+			},
+		},
+	}
+
+	goGen := GoFileGenerator{generator}
+	content, err := goGen.buildContent()
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "type Suit string\n\nconst (\n\tHearts Suit = \"H\"\n\tSpades Suit = \"S\"\n)")
+	assert.Contains(t, content, "type Level uint8\n\nconst (\n\tLow Level = 1\n\tHigh Level = 2\n)")
+
+	assert.NotContains(t, content, "//export opposite\n", "functions using enums must not be exported directly")
+	assert.Contains(t, content, `//export opposite_wrapper
+func opposite_wrapper(suit *C.zend_string, times int64) unsafe.Pointer {
+	result := opposite(Suit(frankenphp.GoString(unsafe.Pointer(suit))), times)
+
+	return frankenphp.PHPString(string(result), false)
+}`)
+	assert.Contains(t, content, `//export next_wrapper
+func next_wrapper(level int64, exceptionMessage **C.char) int64 {
+	result, err := next(Level(level))
+	if err != nil {
+		*exceptionMessage = C.CString(err.Error())
+	}
+
+	return int64(result)
+}`)
+}
+
+func TestGoFileGenerator_Hooks(t *testing.T) {
+	sourceFile := createTempSourceFile(t, `package main
+
+//export_php:minit
+func openPool() error {
+	return nil
+}
+
+//export_php:rshutdown
+func release() {
+	pool.Release()
+}`)
+
+	generator := &Generator{
+		BaseName:   "hooks",
+		SourceFile: sourceFile,
+		Hooks: []phpHook{
+			{Kind: hookMinit, Name: "openPool", ReturnsError: true, GoFunction: "func openPool() error {\n\treturn nil\n}"},
+			{Kind: hookRshutdown, Name: "release", GoFunction: "func release() {\n\tpool.Release()\n}"},
+		},
+	}
+
+	goGen := GoFileGenerator{generator}
+	content, err := goGen.buildContent()
+	require.NoError(t, err)
+
+	assert.NotContains(t, content, "//export openPool\n", "hooks returning an error must not be exported directly")
+	assert.Contains(t, content, `//export openPool_hook
+func openPool_hook() *C.char {
+	if err := openPool(); err != nil {
+		return C.CString(err.Error())
+	}
+
+	return nil
+}`)
+	assert.Contains(t, content, "//export release\nfunc release() {")
+}
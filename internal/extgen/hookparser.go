@@ -0,0 +1,74 @@
+package extgen
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var phpHookRegex = regexp.MustCompile(`^//\s*export_php:(minit|mshutdown|rinit|rshutdown)\s*$`)
+
+type HookParser struct{}
+
+func (hp *HookParser) parse(filename string) (hooks []phpHook, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		e := file.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	scanner := bufio.NewScanner(file)
+	var currentHook *phpHook
+	validator := Validator{}
+	funcParser := FuncParser{}
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if matches := phpHookRegex.FindStringSubmatch(line); matches != nil {
+			currentHook = &phpHook{Kind: phpHookKind(matches[1]), lineNumber: lineNumber}
+
+			continue
+		}
+
+		if currentHook == nil || !strings.HasPrefix(line, "func ") {
+			continue
+		}
+
+		goFunc, err := funcParser.extractGoFunction(scanner, line)
+		if err != nil {
+			return nil, fmt.Errorf("extracting Go function: %w", err)
+		}
+		lineNumber += strings.Count(goFunc, "\n") - 1
+
+		currentHook.GoFunction = goFunc
+		currentHook.ReturnsError = funcParser.returnsError(goFunc)
+
+		name, err := validator.validateHook(*currentHook)
+		if err != nil {
+			printWarning(filename, currentHook.lineNumber, "invalid %s hook: %v", currentHook.Kind, err)
+			currentHook = nil
+
+			continue
+		}
+
+		currentHook.Name = name
+		hooks = append(hooks, *currentHook)
+		currentHook = nil
+	}
+
+	if currentHook != nil {
+		return nil, fmt.Errorf("//export_php:%s directive at line %d is not followed by a function declaration", currentHook.Kind, currentHook.lineNumber)
+	}
+
+	return hooks, scanner.Err()
+}
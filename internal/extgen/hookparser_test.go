@@ -0,0 +1,113 @@
+package extgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []phpHook
+	}{
+		{
+			name: "all hooks",
+			input: `package main
+
+//export_php:minit
+func start() {
+	pool = newPool()
+}
+
+//export_php:mshutdown
+func stop() {
+	pool.Close()
+}
+
+//export_php:rinit
+func startRequest() error {
+	return pool.Acquire()
+}
+
+// releases the connection of the request
+//export_php:rshutdown
+func endRequest() {
+	pool.Release()
+}`,
+			expected: []phpHook{
+				{Kind: hookMinit, Name: "start", lineNumber: 3},
+				{Kind: hookMshutdown, Name: "stop", lineNumber: 8},
+				{Kind: hookRinit, Name: "startRequest", ReturnsError: true, lineNumber: 13},
+				{Kind: hookRshutdown, Name: "endRequest", lineNumber: 19},
+			},
+		},
+		{
+			name: "invalid hooks are skipped",
+			input: `package main
+
+//export_php:minit
+func withParams(size int) {
+	pool = newPool(size)
+}
+
+//export_php:minit
+func (p *Pool) method() {
+	p.Open()
+}
+
+//export_php:rinit
+func withResult() int {
+	return 42
+}
+
+//export_php:rinit
+func valid() {
+	pool.Acquire()
+}`,
+			expected: []phpHook{
+				{Kind: hookRinit, Name: "valid", lineNumber: 18},
+			},
+		},
+		{
+			name: "unknown directive",
+			input: `package main
+
+//export_php:startup
+func start() {
+	pool = newPool()
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := filepath.Join(t.TempDir(), "hooks.go")
+			require.NoError(t, os.WriteFile(tmpFile, []byte(tt.input), 0644))
+
+			parser := &HookParser{}
+			hooks, err := parser.parse(tmpFile)
+			require.NoError(t, err)
+
+			require.Len(t, hooks, len(tt.expected))
+			for i, hook := range hooks {
+				assert.NotEmpty(t, hook.GoFunction)
+				hook.GoFunction = ""
+				assert.Equal(t, tt.expected[i], hook)
+			}
+		})
+	}
+}
+
+func TestHookParserMissingFunction(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "hooks.go")
+	require.NoError(t, os.WriteFile(tmpFile, []byte("package main\n\n//export_php:minit\n"), 0644))
+
+	parser := &HookParser{}
+	_, err := parser.parse(tmpFile)
+	assert.ErrorContains(t, err, "//export_php:minit directive at line 3 is not followed by a function declaration")
+}
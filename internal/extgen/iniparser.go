@@ -0,0 +1,63 @@
+package extgen
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var phpIniRegex = regexp.MustCompile(`^//\s*export_php:ini\s+(\S+)(?:\s+(.*))?$`)
+var iniNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+type IniParser struct{}
+
+func (ip *IniParser) parse(filename string) (entries []phpIniEntry, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		e := file.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	scanner := bufio.NewScanner(file)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		matches := phpIniRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		if !iniNameRegex.MatchString(matches[1]) {
+			printWarning(filename, lineNumber, "invalid INI setting name %q", matches[1])
+
+			continue
+		}
+
+		// the default value can be quoted to contain spaces or be empty
+		defaultValue := strings.TrimSpace(matches[2])
+		if strings.HasPrefix(defaultValue, `"`) {
+			unquoted, err := strconv.Unquote(defaultValue)
+			if err != nil {
+				printWarning(filename, lineNumber, "invalid default value for INI setting %q: %s", matches[1], defaultValue)
+
+				continue
+			}
+
+			defaultValue = unquoted
+		}
+
+		entries = append(entries, phpIniEntry{Name: matches[1], DefaultValue: defaultValue, lineNumber: lineNumber})
+	}
+
+	return entries, scanner.Err()
+}
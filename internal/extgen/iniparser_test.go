@@ -0,0 +1,39 @@
+package extgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIniParser(t *testing.T) {
+	input := `package main
+
+//export_php:ini my_ext.pool_size 10
+//export_php:ini my_ext.dsn "mysql://localhost:3306/app db"
+//export_php:ini my_ext.prefix
+//export_php:ini my_ext.empty ""
+//export_php:ini 1invalid 10
+//export_php:ini my_ext.broken "unterminated
+
+// export_php:ini my_ext.debug off
+`
+
+	tmpFile := filepath.Join(t.TempDir(), "ini.go")
+	require.NoError(t, os.WriteFile(tmpFile, []byte(input), 0644))
+
+	parser := &IniParser{}
+	entries, err := parser.parse(tmpFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []phpIniEntry{
+		{Name: "my_ext.pool_size", DefaultValue: "10", lineNumber: 3},
+		{Name: "my_ext.dsn", DefaultValue: "mysql://localhost:3306/app db", lineNumber: 4},
+		{Name: "my_ext.prefix", DefaultValue: "", lineNumber: 5},
+		{Name: "my_ext.empty", DefaultValue: "", lineNumber: 6},
+		{Name: "my_ext.debug", DefaultValue: "off", lineNumber: 10},
+	}, entries)
+}
@@ -9,17 +9,19 @@ import (
 type phpType string
 
 const (
-	phpString phpType = "string"
-	phpInt    phpType = "int"
-	phpFloat  phpType = "float"
-	phpBool   phpType = "bool"
-	phpArray  phpType = "array"
-	phpObject phpType = "object"
-	phpMixed  phpType = "mixed"
-	phpVoid   phpType = "void"
-	phpNull   phpType = "null"
-	phpTrue   phpType = "true"
-	phpFalse  phpType = "false"
+	phpString   phpType = "string"
+	phpInt      phpType = "int"
+	phpFloat    phpType = "float"
+	phpBool     phpType = "bool"
+	phpArray    phpType = "array"
+	phpObject   phpType = "object"
+	phpMixed    phpType = "mixed"
+	phpCallable phpType = "callable"
+	phpIterator phpType = "Iterator"
+	phpVoid     phpType = "void"
+	phpNull     phpType = "null"
+	phpTrue     phpType = "true"
+	phpFalse    phpType = "false"
 )
 
 type phpFunction struct {
@@ -29,22 +31,70 @@ type phpFunction struct {
 	Params           []phpParameter
 	ReturnType       phpType
 	IsReturnNullable bool
+	ReturnsError     bool     // the Go function returns an error as last value, thrown as an exception
+	ThrowsClass      string   // the exception class set by the "//export_php:throws" directive
+	ReturnEnum       *phpEnum // set when the function returns an enum case
 	lineNumber       int
 }
 
+// needsWrapper reports whether the Go function is called through a generated wrapper converting its arguments and results
+func (fn phpFunction) needsWrapper() bool {
+	if fn.ReturnsError || fn.ReturnEnum != nil {
+		return true
+	}
+
+	for _, param := range fn.Params {
+		if param.Enum != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
 type phpParameter struct {
 	Name         string
 	PhpType      phpType
 	IsNullable   bool
 	DefaultValue string
 	HasDefault   bool
+	Enum         *phpEnum // set when the parameter is an enum case
+}
+
+// phpEnum is a backed enum declared with the "//export_php:enum" directive on a Go type
+type phpEnum struct {
+	Name          string
+	GoType        string
+	GoBackingType string  // the predeclared Go type the type of the enum is defined from
+	BackingType   phpType // string or int
+	Cases         []phpEnumCase
+	filename      string // the source file declaring the type, the cases can be declared in other files of the package
+}
+
+type phpEnumCase struct {
+	Name  string
+	Value string // unquoted for string-backed enums
 }
 
 type phpClass struct {
-	Name       string
-	GoStruct   string
-	Properties []phpClassProperty
-	Methods    []phpClassMethod
+	Name        string
+	GoStruct    string
+	Implements  []string
+	Properties  []phpClassProperty
+	Methods     []phpClassMethod
+	Constructor *phpClassMethod // set by the "__construct" method directive, mapped to a Go function returning the struct
+}
+
+// ExposedProperties returns the properties declared in PHP
+func (c phpClass) ExposedProperties() []phpClassProperty {
+	var properties []phpClassProperty
+	for _, prop := range c.Properties {
+		if prop.PhpName != "" {
+			properties = append(properties, prop)
+		}
+	}
+
+	return properties
 }
 
 type phpClassMethod struct {
@@ -58,6 +108,8 @@ type phpClassMethod struct {
 	isReturnNullable bool
 	lineNumber       int
 	ClassName        string // used by the "//export_php:method" directive
+	GoName           string // name of the Go method or function
+	IsStatic         bool   // set by the "//export_php:static" directive
 }
 
 type phpClassProperty struct {
@@ -65,6 +117,55 @@ type phpClassProperty struct {
 	PhpType    phpType
 	GoType     string
 	IsNullable bool
+	PhpName    string // set by the "php" struct tag, the property is hidden from PHP otherwise
+	Visibility string // public, protected or private
+	IsReadonly bool
+}
+
+// phpHookKind is a step of the lifecycle of the module, named after the matching Zend Engine callback
+type phpHookKind string
+
+const (
+	hookMinit     phpHookKind = "minit"
+	hookMshutdown phpHookKind = "mshutdown"
+	hookRinit     phpHookKind = "rinit"
+	hookRshutdown phpHookKind = "rshutdown"
+)
+
+// phpHook is a Go function called during the lifecycle of the module, declared with the "//export_php:minit",
+// "//export_php:mshutdown", "//export_php:rinit" and "//export_php:rshutdown" directives
+type phpHook struct {
+	Kind         phpHookKind
+	Name         string
+	GoFunction   string
+	ReturnsError bool // the module or the request fails to start if the Go function returns an error
+	lineNumber   int
+}
+
+// phpIniEntry is an INI setting of the module declared with the "//export_php:ini" directive
+type phpIniEntry struct {
+	Name         string
+	DefaultValue string
+	lineNumber   int
+}
+
+// phpInterface is a PHP interface that classes can implement
+type phpInterface struct {
+	ClassEntry string
+	Methods    map[string]phpType
+}
+
+// phpInterfaces are the interfaces supported in the "//export_php:class" directive with their required methods
+var phpInterfaces = map[string]phpInterface{
+	"ArrayAccess": {"zend_ce_arrayaccess", map[string]phpType{
+		"offsetExists": phpBool,
+		"offsetGet":    phpMixed,
+		"offsetSet":    phpVoid,
+		"offsetUnset":  phpVoid,
+	}},
+	"Countable":         {"zend_ce_countable", map[string]phpType{"count": phpInt}},
+	"IteratorAggregate": {"zend_ce_aggregate", map[string]phpType{"getIterator": phpIterator}},
+	"Stringable":        {"zend_ce_stringable", map[string]phpType{"__toString": phpString}},
 }
 
 type phpConstant struct {
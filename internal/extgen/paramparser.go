@@ -39,6 +39,10 @@ func (pp *ParameterParser) generateParamDeclarations(params []phpParameter) stri
 }
 
 func (pp *ParameterParser) generateSingleParamDeclaration(param phpParameter) []string {
+	if param.Enum != nil {
+		return []string{fmt.Sprintf("zend_object *%s = NULL;", param.Name)}
+	}
+
 	var decls []string
 
 	switch param.PhpType {
@@ -68,8 +72,11 @@ func (pp *ParameterParser) generateSingleParamDeclaration(param phpParameter) []
 		if param.IsNullable {
 			decls = append(decls, fmt.Sprintf("zend_bool %s_is_null = 0;", param.Name))
 		}
-	case phpArray:
+	case phpArray, phpMixed:
 		decls = append(decls, fmt.Sprintf("zval *%s = NULL;", param.Name))
+	case phpCallable:
+		decls = append(decls, fmt.Sprintf("zend_fcall_info %s_fci = empty_fcall_info;", param.Name))
+		decls = append(decls, fmt.Sprintf("zend_fcall_info_cache %s_fcc = empty_fcall_info_cache;", param.Name))
 	}
 
 	return decls
@@ -107,6 +114,10 @@ func (pp *ParameterParser) generateParamParsing(params []phpParameter, requiredC
 }
 
 func (pp *ParameterParser) generateParamParsingMacro(param phpParameter) string {
+	if param.Enum != nil {
+		return fmt.Sprintf("\n        Z_PARAM_OBJ_OF_CLASS(%s, %s_ce)", param.Name, param.Enum.Name)
+	}
+
 	if param.IsNullable {
 		switch param.PhpType {
 		case phpString:
@@ -119,6 +130,8 @@ func (pp *ParameterParser) generateParamParsingMacro(param phpParameter) string
 			return fmt.Sprintf("\n        Z_PARAM_BOOL_OR_NULL(%s, %s_is_null)", param.Name, param.Name)
 		case phpArray:
 			return fmt.Sprintf("\n        Z_PARAM_ARRAY_OR_NULL(%s)", param.Name)
+		case phpCallable:
+			return fmt.Sprintf("\n        Z_PARAM_FUNC_OR_NULL(%s_fci, %s_fcc)", param.Name, param.Name)
 		default:
 			return ""
 		}
@@ -134,6 +147,10 @@ func (pp *ParameterParser) generateParamParsingMacro(param phpParameter) string
 			return fmt.Sprintf("\n        Z_PARAM_BOOL(%s)", param.Name)
 		case phpArray:
 			return fmt.Sprintf("\n        Z_PARAM_ARRAY(%s)", param.Name)
+		case phpMixed:
+			return fmt.Sprintf("\n        Z_PARAM_ZVAL(%s)", param.Name)
+		case phpCallable:
+			return fmt.Sprintf("\n        Z_PARAM_FUNC(%s_fci, %s_fcc)", param.Name, param.Name)
 		default:
 			return ""
 		}
@@ -154,6 +171,15 @@ func (pp *ParameterParser) generateGoCallParams(params []phpParameter) string {
 }
 
 func (pp *ParameterParser) generateSingleGoCallParam(param phpParameter) string {
+	if param.Enum != nil {
+		// the Go wrapper receives the value of the case
+		if param.Enum.BackingType == phpString {
+			return fmt.Sprintf("Z_STR_P(zend_enum_fetch_case_value(%s))", param.Name)
+		}
+
+		return fmt.Sprintf("Z_LVAL_P(zend_enum_fetch_case_value(%s))", param.Name)
+	}
+
 	if param.IsNullable {
 		switch param.PhpType {
 		case phpString:
@@ -166,6 +192,8 @@ func (pp *ParameterParser) generateSingleGoCallParam(param phpParameter) string
 			return fmt.Sprintf("%s_is_null ? NULL : &%s", param.Name, param.Name)
 		case phpArray:
 			return param.Name
+		case phpCallable:
+			return fmt.Sprintf("ZEND_FCI_INITIALIZED(%s_fci) ? &%s_fci.function_name : NULL", param.Name, param.Name)
 		default:
 			return param.Name
 		}
@@ -181,6 +209,8 @@ func (pp *ParameterParser) generateSingleGoCallParam(param phpParameter) string
 			return fmt.Sprintf("(int) %s", param.Name)
 		case phpArray:
 			return param.Name
+		case phpCallable:
+			return fmt.Sprintf("&%s_fci.function_name", param.Name)
 		default:
 			return param.Name
 		}
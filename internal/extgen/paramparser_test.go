@@ -346,6 +346,16 @@ func TestParameterParser_GenerateParamParsingMacro(t *testing.T) {
 			param:    phpParameter{Name: "items", PhpType: phpArray, IsNullable: true},
 			expected: "\n        Z_PARAM_ARRAY_OR_NULL(items)",
 		},
+		{
+			name:     "callable parameter",
+			param:    phpParameter{Name: "callback", PhpType: phpCallable},
+			expected: "\n        Z_PARAM_FUNC(callback_fci, callback_fcc)",
+		},
+		{
+			name:     "nullable callable parameter",
+			param:    phpParameter{Name: "callback", PhpType: phpCallable, IsNullable: true},
+			expected: "\n        Z_PARAM_FUNC_OR_NULL(callback_fci, callback_fcc)",
+		},
 		{
 			name:     "unknown type",
 			param:    phpParameter{Name: "unknown", PhpType: phpType("unknown")},
@@ -456,6 +466,16 @@ func TestParameterParser_GenerateSingleGoCallParam(t *testing.T) {
 			param:    phpParameter{Name: "items", PhpType: phpArray, IsNullable: true},
 			expected: "items",
 		},
+		{
+			name:     "callable parameter",
+			param:    phpParameter{Name: "callback", PhpType: phpCallable},
+			expected: "&callback_fci.function_name",
+		},
+		{
+			name:     "nullable callable parameter",
+			param:    phpParameter{Name: "callback", PhpType: phpCallable, IsNullable: true},
+			expected: "ZEND_FCI_INITIALIZED(callback_fci) ? &callback_fci.function_name : NULL",
+		},
 		{
 			name:     "unknown type",
 			param:    phpParameter{Name: "unknown", PhpType: phpType("unknown")},
@@ -534,6 +554,11 @@ func TestParameterParser_GenerateSingleParamDeclaration(t *testing.T) {
 			param:    phpParameter{Name: "items", PhpType: phpArray, HasDefault: false, IsNullable: true},
 			expected: []string{"zval *items = NULL;"},
 		},
+		{
+			name:     "callable parameter",
+			param:    phpParameter{Name: "callback", PhpType: phpCallable, HasDefault: false},
+			expected: []string{"zend_fcall_info callback_fci = empty_fcall_info;", "zend_fcall_info_cache callback_fcc = empty_fcall_info_cache;"},
+		},
 	}
 
 	for _, tt := range tests {
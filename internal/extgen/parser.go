@@ -3,8 +3,8 @@ package extgen
 type SourceParser struct{}
 
 // EXPERIMENTAL
-func (p *SourceParser) ParseFunctions(filename string) ([]phpFunction, error) {
-	functionParser := &FuncParser{}
+func (p *SourceParser) ParseFunctions(filename string, enums []phpEnum) ([]phpFunction, error) {
+	functionParser := &FuncParser{enums: enums}
 	return functionParser.parse(filename)
 }
 
@@ -20,8 +20,26 @@ func (p *SourceParser) ParseConstants(filename string) ([]phpConstant, error) {
 	return constantParser.parse(filename)
 }
 
+// EXPERIMENTAL
+func (p *SourceParser) ParseEnums(filenames ...string) ([]phpEnum, error) {
+	enumParser := &EnumParser{}
+	return enumParser.parse(filenames...)
+}
+
 // EXPERIMENTAL
 func (p *SourceParser) ParseNamespace(filename string) (string, error) {
 	namespaceParser := NamespaceParser{}
 	return namespaceParser.parse(filename)
 }
+
+// EXPERIMENTAL
+func (p *SourceParser) ParseHooks(filename string) ([]phpHook, error) {
+	hookParser := &HookParser{}
+	return hookParser.parse(filename)
+}
+
+// EXPERIMENTAL
+func (p *SourceParser) ParseIniEntries(filename string) ([]phpIniEntry, error) {
+	iniParser := &IniParser{}
+	return iniParser.parse(filename)
+}
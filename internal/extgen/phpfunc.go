@@ -26,7 +26,13 @@ func (pfg *PHPFuncGenerator) generate(fn phpFunction) string {
 
 	builder.WriteString(pfg.generateGoCall(fn) + "\n")
 
-	if returnCode := pfg.generateReturnCode(fn.ReturnType); returnCode != "" {
+	if fn.ReturnsError {
+		builder.WriteString(pfg.generateExceptionCheck(fn) + "\n")
+	}
+
+	if fn.ReturnEnum != nil {
+		builder.WriteString(pfg.generateEnumReturnCode(*fn.ReturnEnum) + "\n")
+	} else if returnCode := pfg.generateReturnCode(fn.ReturnType); returnCode != "" {
 		builder.WriteString(returnCode + "\n")
 	}
 
@@ -37,20 +43,76 @@ func (pfg *PHPFuncGenerator) generate(fn phpFunction) string {
 
 func (pfg *PHPFuncGenerator) generateGoCall(fn phpFunction) string {
 	callParams := pfg.paramParser.generateGoCallParams(fn.Params)
+	goFunction := fn.Name
+
+	if fn.needsWrapper() {
+		goFunction += "_wrapper"
+	}
+
+	var declaration string
+	if fn.ReturnsError {
+		// the Go wrapper sets the message of the returned error
+		declaration = "    char *exception_message = NULL;\n"
+
+		if callParams != "" {
+			callParams += ", "
+		}
+		callParams += "&exception_message"
+	}
 
 	if fn.ReturnType == phpVoid {
-		return fmt.Sprintf("    %s(%s);", fn.Name, callParams)
+		return fmt.Sprintf("%s    %s(%s);", declaration, goFunction, callParams)
+	}
+
+	if fn.ReturnEnum != nil {
+		// the Go wrapper returns the value of the case
+		if fn.ReturnEnum.BackingType == phpString {
+			return fmt.Sprintf("%s    zend_string *result = %s(%s);", declaration, goFunction, callParams)
+		}
+
+		return fmt.Sprintf("%s    zend_long result = %s(%s);", declaration, goFunction, callParams)
 	}
 
 	if fn.ReturnType == phpString {
-		return fmt.Sprintf("    zend_string *result = %s(%s);", fn.Name, callParams)
+		return fmt.Sprintf("%s    zend_string *result = %s(%s);", declaration, goFunction, callParams)
 	}
 
 	if fn.ReturnType == phpArray {
-		return fmt.Sprintf("    zend_array *result = %s(%s);", fn.Name, callParams)
+		return fmt.Sprintf("%s    zend_array *result = %s(%s);", declaration, goFunction, callParams)
+	}
+
+	return fmt.Sprintf("%s    %s result = %s(%s);", declaration, pfg.getCReturnType(fn.ReturnType), goFunction, callParams)
+}
+
+func (pfg *PHPFuncGenerator) generateExceptionCheck(fn phpFunction) string {
+	className := "NULL"
+	if fn.ThrowsClass != "" {
+		className = `"` + strings.ReplaceAll(fn.ThrowsClass, `\`, `\\`) + `"`
+	}
+
+	return fmt.Sprintf(`    if (exception_message != NULL) {
+        throw_go_exception(exception_message, %s);
+        RETURN_THROWS();
+    }`, className)
+}
+
+// generateEnumReturnCode returns the case of the enum matching the value returned by Go,
+// a ValueError is thrown if there is no such case
+func (pfg *PHPFuncGenerator) generateEnumReturnCode(enum phpEnum) string {
+	setValue := "ZVAL_LONG(&value, result);"
+	if enum.BackingType == phpString {
+		// PHPString returns NULL for empty strings
+		setValue = `if (result) {
+        ZVAL_STR(&value, result);
+    } else {
+        ZVAL_EMPTY_STRING(&value);
+    }`
 	}
 
-	return fmt.Sprintf("    %s result = %s(%s);", pfg.getCReturnType(fn.ReturnType), fn.Name, callParams)
+	return fmt.Sprintf(`    zval value;
+    %s
+    zend_call_method_with_1_params(NULL, %s_ce, NULL, "from", return_value, &value);
+    zval_ptr_dtor(&value);`, setValue, enum.Name)
 }
 
 func (pfg *PHPFuncGenerator) getCReturnType(returnType phpType) string {
@@ -64,6 +64,38 @@ func TestPHPFunctionGenerator_Generate(t *testing.T) {
 				"doSomething(action);",
 			},
 		},
+		{
+			name: "function returning an error",
+			function: phpFunction{
+				Name:         "divide",
+				ReturnType:   phpInt,
+				ReturnsError: true,
+				ThrowsClass:  `App\DivisionByZero`,
+				Params: []phpParameter{
+					{Name: "a", PhpType: phpInt},
+					{Name: "b", PhpType: phpInt},
+				},
+			},
+			contains: []string{
+				"char *exception_message = NULL;",
+				"long result = divide_wrapper((long) a, (long) b, &exception_message);",
+				`throw_go_exception(exception_message, "App\\DivisionByZero");`,
+				"RETURN_THROWS();",
+				"RETURN_LONG(result);",
+			},
+		},
+		{
+			name: "void function returning an error",
+			function: phpFunction{
+				Name:         "save",
+				ReturnType:   phpVoid,
+				ReturnsError: true,
+			},
+			contains: []string{
+				"save_wrapper(&exception_message);",
+				"throw_go_exception(exception_message, NULL);",
+			},
+		},
 		{
 			name: "bool function with default",
 			function: phpFunction{
@@ -136,6 +168,53 @@ func TestPHPFunctionGenerator_Generate(t *testing.T) {
 				"Z_PARAM_OPTIONAL",
 			},
 		},
+		{
+			name: "string-backed enum parameter and return value",
+			function: phpFunction{
+				Name:       "opposite",
+				ReturnType: "Suit",
+				ReturnEnum: &phpEnum{Name: "Suit", BackingType: phpString},
+				Params: []phpParameter{
+					{Name: "suit", PhpType: "Suit", Enum: &phpEnum{Name: "Suit", BackingType: phpString}},
+				},
+			},
+			contains: []string{
+				"zend_object *suit = NULL;",
+				"Z_PARAM_OBJ_OF_CLASS(suit, Suit_ce)",
+				"zend_string *result = opposite_wrapper(Z_STR_P(zend_enum_fetch_case_value(suit)));",
+				"if (result) {\n        ZVAL_STR(&value, result);\n    } else {\n        ZVAL_EMPTY_STRING(&value);\n    }",
+				`zend_call_method_with_1_params(NULL, Suit_ce, NULL, "from", return_value, &value);`,
+			},
+		},
+		{
+			name: "int-backed enum parameter",
+			function: phpFunction{
+				Name:       "isHigh",
+				ReturnType: phpBool,
+				Params: []phpParameter{
+					{Name: "level", PhpType: "Level", Enum: &phpEnum{Name: "Level", BackingType: phpInt}},
+				},
+			},
+			contains: []string{
+				"Z_PARAM_OBJ_OF_CLASS(level, Level_ce)",
+				"int result = isHigh_wrapper(Z_LVAL_P(zend_enum_fetch_case_value(level)));",
+				"RETURN_BOOL(result);",
+			},
+		},
+		{
+			name: "int-backed enum return value",
+			function: phpFunction{
+				Name:         "defaultLevel",
+				ReturnType:   "Level",
+				ReturnEnum:   &phpEnum{Name: "Level", BackingType: phpInt},
+				ReturnsError: true,
+			},
+			contains: []string{
+				"zend_long result = defaultLevel_wrapper(&exception_message);",
+				"ZVAL_LONG(&value, result);",
+				`zend_call_method_with_1_params(NULL, Level_ce, NULL, "from", return_value, &value);`,
+			},
+		},
 	}
 
 	generator := PHPFuncGenerator{}
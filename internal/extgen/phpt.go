@@ -0,0 +1,397 @@
+package extgen
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/test.phpt.tpl
+var phptFileContent string
+
+//go:embed templates/extension_test.go.tpl
+var goTestFileContent string
+
+// phptGenerator generates a .phpt test for each function, class and enum of the extension, and the Go test running them.
+// Existing tests are never overwritten, they belong to the author of the extension once generated.
+type phptGenerator struct {
+	generator *Generator
+}
+
+type phptTest struct {
+	Name      string // name of the file, without extension
+	Title     string
+	Namespace string
+	Code      string
+	Expect    string
+}
+
+type goTestTemplateData struct {
+	PackageName string
+}
+
+func (pg *phptGenerator) generate() error {
+	testsDir := filepath.Join(pg.generator.BuildDir, "tests")
+	if err := os.MkdirAll(testsDir, 0755); err != nil {
+		return err
+	}
+
+	for _, test := range pg.buildTests() {
+		content, err := pg.buildPHPT(test)
+		if err != nil {
+			return err
+		}
+
+		if err := createFile(filepath.Join(testsDir, test.Name+".phpt"), content); err != nil {
+			return err
+		}
+	}
+
+	content, err := pg.buildGoTest()
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(pg.generator.BuildDir, pg.generator.BaseName+"_test.go"), content)
+}
+
+func (pg *phptGenerator) buildTests() []phptTest {
+	var tests []phptTest
+
+	for _, fn := range pg.generator.Functions {
+		tests = append(tests, pg.functionTest(fn))
+	}
+
+	for _, class := range pg.generator.Classes {
+		tests = append(tests, pg.classTest(class))
+	}
+
+	for _, enum := range pg.generator.Enums {
+		tests = append(tests, pg.enumTest(enum))
+	}
+
+	if len(pg.generator.Constants) > 0 {
+		tests = append(tests, pg.constantsTest())
+	}
+
+	return tests
+}
+
+func (pg *phptGenerator) buildPHPT(test phptTest) (string, error) {
+	tmpl := template.Must(template.New("phpt").Parse(phptFileContent))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, test); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (pg *phptGenerator) buildGoTest() (string, error) {
+	tmpl := template.Must(template.New("gotest").Parse(goTestFileContent))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, goTestTemplateData{PackageName: SanitizePackageName(pg.generator.BaseName)}); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (pg *phptGenerator) functionTest(fn phpFunction) phptTest {
+	var b phptBuilder
+
+	b.assert(fmt.Sprintf("function_exists(%s)", phpStringLiteral(pg.qualifiedName(fn.Name))))
+
+	exception := ""
+	if fn.ReturnsError {
+		exception = `\RuntimeException`
+		if fn.ThrowsClass != "" {
+			exception = `\` + fn.ThrowsClass
+		}
+	}
+
+	check := pg.resultCheck(fn.ReturnType, fn.IsReturnNullable, fn.ReturnEnum)
+	b.call(fn.Name+"("+pg.validArguments(fn.Params, false)+")", check, exception)
+	if pg.hasNullableParameter(fn.Params) {
+		b.call(fn.Name+"("+pg.validArguments(fn.Params, true)+")", check, exception)
+	}
+
+	b.reject(pg.invalidCalls(fn.Name, fn.Params))
+
+	return b.test("function_"+fn.Name, fn.Name+"(): accepted and rejected argument types", pg.generator.Namespace)
+}
+
+func (pg *phptGenerator) classTest(class phpClass) phptTest {
+	var b phptBuilder
+
+	b.assert(fmt.Sprintf("class_exists(%s)", phpStringLiteral(pg.qualifiedName(class.Name))))
+
+	var constructorParams []phpParameter
+	if class.Constructor != nil {
+		constructorParams = class.Constructor.Params
+	}
+	b.statement(fmt.Sprintf("$object = new %s(%s);", class.Name, pg.validArguments(constructorParams, false)))
+
+	for _, iface := range class.Implements {
+		b.assert(fmt.Sprintf(`$object instanceof \%s`, iface))
+	}
+
+	for _, prop := range class.ExposedProperties() {
+		if prop.Visibility != "public" {
+			continue
+		}
+
+		b.assert(strings.ReplaceAll(pg.resultCheck(prop.PhpType, prop.IsNullable, nil), "$result", "$object->"+prop.PhpName))
+	}
+
+	var invalidCalls []phptRejection
+	if class.Constructor != nil {
+		invalidCalls = pg.invalidCalls("new "+class.Name, class.Constructor.Params)
+	}
+
+	for _, method := range class.Methods {
+		call := "$object->" + method.PhpName
+		if method.IsStatic {
+			call = class.Name + "::" + method.PhpName
+		}
+
+		check := pg.resultCheck(method.ReturnType, method.isReturnNullable, nil)
+		b.call(call+"("+pg.validArguments(method.Params, false)+")", check, "")
+		if pg.hasNullableParameter(method.Params) {
+			b.call(call+"("+pg.validArguments(method.Params, true)+")", check, "")
+		}
+
+		invalidCalls = append(invalidCalls, pg.invalidCalls(call, method.Params)...)
+	}
+
+	b.reject(invalidCalls)
+
+	return b.test("class_"+class.Name, class.Name+": construction, properties and methods", pg.generator.Namespace)
+}
+
+func (pg *phptGenerator) enumTest(enum phpEnum) phptTest {
+	var b phptBuilder
+
+	b.assert(fmt.Sprintf("enum_exists(%s)", phpStringLiteral(pg.qualifiedName(enum.Name))))
+	b.assert(fmt.Sprintf("count(%s::cases()) === %d", enum.Name, len(enum.Cases)))
+
+	for _, enumCase := range enum.Cases {
+		value := enumCase.Value
+		if enum.BackingType == phpString {
+			value = phpStringLiteral(value)
+		}
+
+		b.assert(fmt.Sprintf("%s::from(%s) === %s::%s", enum.Name, value, enum.Name, enumCase.Name))
+	}
+
+	return b.test("enum_"+enum.Name, enum.Name+": cases", pg.generator.Namespace)
+}
+
+func (pg *phptGenerator) constantsTest() phptTest {
+	var b phptBuilder
+
+	for _, constant := range pg.generator.Constants {
+		name := constant.Name
+		if constant.ClassName != "" {
+			name = constant.ClassName + "::" + name
+		}
+
+		b.assert(fmt.Sprintf("defined(%s)", phpStringLiteral(pg.qualifiedName(name))))
+	}
+
+	return b.test("constants", "constants are defined", pg.generator.Namespace)
+}
+
+func (pg *phptGenerator) qualifiedName(name string) string {
+	if pg.generator.Namespace == "" {
+		return name
+	}
+
+	return pg.generator.Namespace + `\` + name
+}
+
+func (pg *phptGenerator) hasNullableParameter(params []phpParameter) bool {
+	for _, param := range params {
+		if param.IsNullable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validArguments returns arguments matching the types of the parameters, null is passed to nullable parameters if withNull is set
+func (pg *phptGenerator) validArguments(params []phpParameter, withNull bool) string {
+	args := make([]string, 0, len(params))
+	for _, param := range params {
+		if withNull && param.IsNullable {
+			args = append(args, "null")
+
+			continue
+		}
+
+		args = append(args, pg.validValue(param))
+	}
+
+	return strings.Join(args, ", ")
+}
+
+func (pg *phptGenerator) validValue(param phpParameter) string {
+	if param.Enum != nil {
+		return param.Enum.Name + "::" + param.Enum.Cases[0].Name
+	}
+
+	switch param.PhpType {
+	case phpInt:
+		return "42"
+	case phpFloat:
+		return "4.2"
+	case phpBool:
+		return "true"
+	case phpArray:
+		return "['FrankenPHP', 42]"
+	case phpObject:
+		return `new \stdClass()`
+	case phpCallable:
+		return "static fn (mixed ...$args): mixed => null"
+	default:
+		return "'FrankenPHP'"
+	}
+}
+
+// invalidValue returns a value of a type not accepted by the parameter in strict mode, mixed parameters accept everything
+func (pg *phptGenerator) invalidValue(param phpParameter) (string, bool) {
+	if param.Enum != nil {
+		return "'FrankenPHP'", true
+	}
+
+	switch param.PhpType {
+	case phpString, phpObject, phpCallable:
+		return "42", true
+	case phpInt:
+		return "'42'", true
+	case phpFloat:
+		return "'4.2'", true
+	case phpBool:
+		return "1", true
+	case phpArray:
+		return "'FrankenPHP'", true
+	default:
+		return "", false
+	}
+}
+
+// phptRejection is a PHP expression expected to throw an error
+type phptRejection struct {
+	Expression string
+	Error      string
+}
+
+// invalidCalls returns calls passing a value of the wrong type to each parameter, and a call without the required arguments
+func (pg *phptGenerator) invalidCalls(call string, params []phpParameter) []phptRejection {
+	var calls []phptRejection
+
+	for i, param := range params {
+		value, ok := pg.invalidValue(param)
+		if !ok {
+			continue
+		}
+
+		args := make([]string, len(params))
+		for j, p := range params {
+			args[j] = pg.validValue(p)
+		}
+		args[i] = value
+
+		calls = append(calls, phptRejection{call + "(" + strings.Join(args, ", ") + ")", "TypeError"})
+	}
+
+	if len(params) > 0 && !params[0].HasDefault {
+		calls = append(calls, phptRejection{call + "()", "ArgumentCountError"})
+	}
+
+	return calls
+}
+
+// resultCheck returns a PHP expression checking that $result matches the declared type
+func (pg *phptGenerator) resultCheck(returnType phpType, nullable bool, enum *phpEnum) string {
+	var check string
+	switch {
+	case enum != nil:
+		check = "$result instanceof " + enum.Name
+	case returnType == phpString, returnType == phpInt, returnType == phpFloat, returnType == phpBool,
+		returnType == phpArray, returnType == phpObject:
+		check = fmt.Sprintf("is_%s($result)", returnType)
+	case returnType == phpIterator:
+		check = `$result instanceof \Iterator`
+	case returnType == phpVoid, returnType == phpNull:
+		return "$result === null"
+	case returnType == phpTrue, returnType == phpFalse:
+		return "$result === " + string(returnType)
+	default:
+		return "true"
+	}
+
+	if nullable {
+		return "$result === null || " + check
+	}
+
+	return check
+}
+
+// phptBuilder writes the code of a test and its expected output
+type phptBuilder struct {
+	code   strings.Builder
+	expect strings.Builder
+}
+
+func (b *phptBuilder) statement(statement string) {
+	b.code.WriteString(statement + "\n")
+}
+
+// assert checks that a PHP expression is true
+func (b *phptBuilder) assert(expression string) {
+	fmt.Fprintf(&b.code, "var_dump(%s);\n", expression)
+	b.expect.WriteString("bool(true)\n")
+}
+
+// call checks the result of a PHP expression, the exception declared by the function is accepted as a result
+func (b *phptBuilder) call(expression, check, exception string) {
+	if exception == "" {
+		fmt.Fprintf(&b.code, "$result = %s;\nvar_dump(%s);\n", expression, check)
+	} else {
+		fmt.Fprintf(&b.code, "try {\n    $result = %s;\n    var_dump(%s);\n} catch (%s) {\n    var_dump(true);\n}\n", expression, check, exception)
+	}
+
+	b.expect.WriteString("bool(true)\n")
+}
+
+// reject checks that the PHP expressions throw the expected errors
+func (b *phptBuilder) reject(rejections []phptRejection) {
+	if len(rejections) == 0 {
+		return
+	}
+
+	b.code.WriteString("\nforeach ([\n")
+	for _, rejection := range rejections {
+		fmt.Fprintf(&b.code, "    fn () => %s,\n", rejection.Expression)
+		b.expect.WriteString(rejection.Error + "\n")
+	}
+	b.code.WriteString("] as $call) {\n    try {\n        $call();\n        echo \"no error\\n\";\n    } catch (\\TypeError $e) {\n        echo $e::class, \"\\n\";\n    }\n}\n")
+}
+
+func (b *phptBuilder) test(name, title, namespace string) phptTest {
+	return phptTest{
+		Name:      name,
+		Title:     title,
+		Namespace: namespace,
+		Code:      strings.TrimSuffix(b.code.String(), "\n"),
+		Expect:    strings.TrimSuffix(b.expect.String(), "\n"),
+	}
+}
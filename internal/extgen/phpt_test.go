@@ -0,0 +1,193 @@
+package extgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPHPTGenerator_FunctionTest(t *testing.T) {
+	generator := &Generator{Namespace: `Go\Ext`}
+	pg := phptGenerator{generator}
+
+	test := pg.functionTest(phpFunction{
+		Name: "repeat_this",
+		Params: []phpParameter{
+			{Name: "str", PhpType: phpString},
+			{Name: "count", PhpType: phpInt},
+			{Name: "reverse", PhpType: phpBool, IsNullable: true},
+		},
+		ReturnType:   phpString,
+		ReturnsError: true,
+	})
+
+	assert.Equal(t, "function_repeat_this", test.Name)
+	assert.Equal(t, `Go\Ext`, test.Namespace)
+	assert.Equal(t, `var_dump(function_exists('Go\\Ext\\repeat_this'));
+try {
+    $result = repeat_this('FrankenPHP', 42, true);
+    var_dump(is_string($result));
+} catch (\RuntimeException) {
+    var_dump(true);
+}
+try {
+    $result = repeat_this('FrankenPHP', 42, null);
+    var_dump(is_string($result));
+} catch (\RuntimeException) {
+    var_dump(true);
+}
+
+foreach ([
+    fn () => repeat_this(42, 42, true),
+    fn () => repeat_this('FrankenPHP', '42', true),
+    fn () => repeat_this('FrankenPHP', 42, 1),
+    fn () => repeat_this(),
+] as $call) {
+    try {
+        $call();
+        echo "no error\n";
+    } catch (\TypeError $e) {
+        echo $e::class, "\n";
+    }
+}`, test.Code)
+	assert.Equal(t, "bool(true)\nbool(true)\nbool(true)\nTypeError\nTypeError\nTypeError\nArgumentCountError", test.Expect)
+}
+
+func TestPHPTGenerator_ClassTest(t *testing.T) {
+	pg := phptGenerator{&Generator{}}
+
+	test := pg.classTest(phpClass{
+		Name:       "Counter",
+		Implements: []string{"Countable"},
+		Properties: []phpClassProperty{
+			{Name: "Value", PhpType: phpInt, PhpName: "value", Visibility: "public"},
+			{Name: "secret", PhpType: phpString, PhpName: "secret", Visibility: "private"},
+		},
+		Constructor: &phpClassMethod{Params: []phpParameter{{Name: "start", PhpType: phpInt, HasDefault: true, DefaultValue: "0"}}},
+		Methods: []phpClassMethod{
+			{PhpName: "count", ReturnType: phpInt},
+			{PhpName: "create", ReturnType: phpString, isReturnNullable: true, IsStatic: true, Params: []phpParameter{{Name: "items", PhpType: phpMixed}}},
+		},
+	})
+
+	assert.Equal(t, "class_Counter", test.Name)
+	assert.Equal(t, `var_dump(class_exists('Counter'));
+$object = new Counter(42);
+var_dump($object instanceof \Countable);
+var_dump(is_int($object->value));
+$result = $object->count();
+var_dump(is_int($result));
+$result = Counter::create('FrankenPHP');
+var_dump($result === null || is_string($result));
+
+foreach ([
+    fn () => new Counter('42'),
+    fn () => Counter::create(),
+] as $call) {
+    try {
+        $call();
+        echo "no error\n";
+    } catch (\TypeError $e) {
+        echo $e::class, "\n";
+    }
+}`, test.Code)
+	assert.Equal(t, "bool(true)\nbool(true)\nbool(true)\nbool(true)\nbool(true)\nTypeError\nArgumentCountError", test.Expect)
+}
+
+func TestPHPTGenerator_EnumAndConstantsTests(t *testing.T) {
+	pg := phptGenerator{&Generator{
+		Enums: []phpEnum{{Name: "Priority", BackingType: phpInt, Cases: []phpEnumCase{{"Low", "1"}, {"High", "2"}}}},
+		Constants: []phpConstant{
+			{Name: "MAX", Value: "10", PhpType: phpInt},
+			{Name: "MODE", Value: "1", PhpType: phpInt, ClassName: "Counter"},
+		},
+	}}
+
+	tests := pg.buildTests()
+	require.Len(t, tests, 2)
+
+	assert.Equal(t, "enum_Priority", tests[0].Name)
+	assert.Equal(t, `var_dump(enum_exists('Priority'));
+var_dump(count(Priority::cases()) === 2);
+var_dump(Priority::from(1) === Priority::Low);
+var_dump(Priority::from(2) === Priority::High);`, tests[0].Code)
+
+	assert.Equal(t, "constants", tests[1].Name)
+	assert.Equal(t, "var_dump(defined('MAX'));\nvar_dump(defined('Counter::MODE'));", tests[1].Code)
+	assert.Equal(t, "bool(true)\nbool(true)", tests[1].Expect)
+}
+
+func TestPHPTGenerator_ResultCheck(t *testing.T) {
+	enum := &phpEnum{Name: "Suit"}
+
+	tests := []struct {
+		returnType phpType
+		nullable   bool
+		enum       *phpEnum
+		expected   string
+	}{
+		{phpString, false, nil, "is_string($result)"},
+		{phpInt, true, nil, "$result === null || is_int($result)"},
+		{phpFloat, false, nil, "is_float($result)"},
+		{phpArray, true, nil, "$result === null || is_array($result)"},
+		{phpIterator, false, nil, `$result instanceof \Iterator`},
+		{phpVoid, false, nil, "$result === null"},
+		{phpFalse, false, nil, "$result === false"},
+		{phpMixed, false, nil, "true"},
+		{phpString, false, enum, "$result instanceof Suit"},
+	}
+
+	pg := phptGenerator{&Generator{}}
+	for _, tt := range tests {
+		t.Run(string(tt.returnType), func(t *testing.T) {
+			assert.Equal(t, tt.expected, pg.resultCheck(tt.returnType, tt.nullable, tt.enum))
+		})
+	}
+}
+
+func TestPHPTGenerator_Generate(t *testing.T) {
+	buildDir := filepath.Join(t.TempDir(), "build")
+	generator := &Generator{
+		BaseName:  "my-ext",
+		BuildDir:  buildDir,
+		Functions: []phpFunction{{Name: "hello", ReturnType: phpString}},
+	}
+
+	pg := phptGenerator{generator}
+	require.NoError(t, pg.generate())
+
+	testFile := filepath.Join(buildDir, "tests", "function_hello.phpt")
+	content, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, `--TEST--
+hello(): accepted and rejected argument types
+--FILE--
+<?php
+
+declare(strict_types=1);
+
+var_dump(function_exists('hello'));
+$result = hello();
+var_dump(is_string($result));
+--EXPECT--
+bool(true)
+bool(true)
+`, string(content))
+
+	goTest, err := os.ReadFile(filepath.Join(buildDir, "my-ext_test.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(goTest), "package my_ext")
+	assert.Contains(t, string(goTest), "frankenphp.ExecuteScriptCLI(script, []string{os.Args[0], script})")
+	assert.Contains(t, string(goTest), `filepath.Glob(filepath.Join("tests", "*.phpt"))`)
+
+	// tests edited by the author of the extension are kept
+	require.NoError(t, os.WriteFile(testFile, []byte("edited"), 0644))
+	require.NoError(t, pg.generate())
+
+	content, err = os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", string(content))
+}
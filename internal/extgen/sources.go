@@ -0,0 +1,254 @@
+package extgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// sourcePackage is a Go package of the extension, subpackages are merged into the generated package
+type sourcePackage struct {
+	Name       string // name of the package clause
+	ImportPath string // empty for the root package, or when the module path can't be found
+	Files      []string
+}
+
+// collectSourcePackages returns the Go packages of the extension. If path is a file, only this file is used,
+// if it's a directory, the non-test files of the package it contains and of its subpackages are used.
+func collectSourcePackages(path, buildDir string) ([]sourcePackage, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []sourcePackage{{Files: []string{path}}}, nil
+	}
+
+	absBuildDir, err := filepath.Abs(buildDir)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleDir, modulePath := findModule(path)
+
+	var packages []sourcePackage
+	err = filepath.WalkDir(path, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if dir != path {
+			if skipSourceDir(dir, absBuildDir) {
+				return filepath.SkipDir
+			}
+		}
+
+		pkg, err := readSourcePackage(dir)
+		if err != nil || pkg == nil {
+			return err
+		}
+
+		if dir != path && modulePath != "" {
+			if rel, err := filepath.Rel(moduleDir, dir); err == nil {
+				pkg.ImportPath = modulePath + "/" + filepath.ToSlash(rel)
+			}
+		}
+
+		packages = append(packages, *pkg)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("no Go files found in %q", path)
+	}
+
+	return packages, nil
+}
+
+// skipSourceDir reports whether the directory must be ignored: the build directory, directories ignored by the go tool,
+// and nested modules
+func skipSourceDir(dir, absBuildDir string) bool {
+	name := filepath.Base(dir)
+	if name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+
+	if absDir, err := filepath.Abs(dir); err == nil && absDir == absBuildDir {
+		return true
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+
+	return err == nil
+}
+
+// readSourcePackage returns the package in the directory, or nil if it doesn't contain Go files matching the current build context
+func readSourcePackage(dir string) (*sourcePackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg *sourcePackage
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		if match, err := build.Default.MatchFile(dir, name); err != nil || !match {
+			continue
+		}
+
+		filename := filepath.Join(dir, name)
+		node, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return nil, fmt.Errorf("parsing file: %w", err)
+		}
+
+		if pkg == nil {
+			pkg = &sourcePackage{Name: node.Name.Name}
+		} else if node.Name.Name != pkg.Name {
+			return nil, fmt.Errorf("found packages %s and %s in %q", pkg.Name, node.Name.Name, dir)
+		}
+
+		pkg.Files = append(pkg.Files, filename)
+	}
+
+	return pkg, nil
+}
+
+// findModule returns the directory and the path of the module containing the directory
+func findModule(dir string) (string, string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+
+	for {
+		if content, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			for line := range strings.Lines(string(content)) {
+				if modulePath, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+					return dir, strings.Trim(strings.TrimSpace(modulePath), `"`)
+				}
+			}
+
+			return "", ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+
+		dir = parent
+	}
+}
+
+// mergeSubpackageImports removes the imports of the subpackages, which are merged into the generated package,
+// and returns the names they are referenced with
+func mergeSubpackageImports(imports []string, packages []sourcePackage) ([]string, []string) {
+	filtered := make([]string, 0, len(imports))
+	var qualifiers []string
+
+	for _, imp := range imports {
+		name, importPath, hasName := strings.Cut(imp, " ")
+		if !hasName {
+			importPath = name
+		}
+
+		unquoted, err := strconv.Unquote(importPath)
+		if err != nil {
+			filtered = append(filtered, imp)
+
+			continue
+		}
+
+		i := slices.IndexFunc(packages, func(pkg sourcePackage) bool {
+			return pkg.ImportPath != "" && pkg.ImportPath == unquoted
+		})
+		if i == -1 {
+			filtered = append(filtered, imp)
+
+			continue
+		}
+
+		if !hasName {
+			name = packages[i].Name
+		}
+		if name != "_" && name != "." && !slices.Contains(qualifiers, name) {
+			qualifiers = append(qualifiers, name)
+		}
+	}
+
+	return filtered, qualifiers
+}
+
+// unqualifySubpackageReferences turns the references to identifiers of merged subpackages (e.g. "db.Open")
+// into references to the identifiers of the generated package ("Open")
+func unqualifySubpackageReferences(content string, qualifiers []string) (string, error) {
+	if len(qualifiers) == 0 {
+		return content, nil
+	}
+
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return "", fmt.Errorf("parsing generated code: %w", err)
+	}
+
+	// the imports of the subpackages are removed, so the qualifiers are unresolved identifiers,
+	// unless they are shadowed by a variable of the same name
+	info := &types.Info{Uses: make(map[*ast.Ident]types.Object)}
+	conf := types.Config{
+		Importer:    failingImporter{},
+		FakeImportC: true,
+		Error:       func(error) {},
+	}
+	_, _ = conf.Check(node.Name.Name, fset, []*ast.File{node}, info)
+
+	var offsets []int
+	ast.Inspect(node, func(n ast.Node) bool {
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		ident, ok := selector.X.(*ast.Ident)
+		if ok && slices.Contains(qualifiers, ident.Name) && info.Uses[ident] == nil {
+			offsets = append(offsets, fset.Position(ident.Pos()).Offset)
+		}
+
+		return true
+	})
+
+	slices.Sort(offsets)
+
+	var b strings.Builder
+	last := 0
+	for _, offset := range offsets {
+		b.WriteString(content[last:offset])
+		last = offset + strings.Index(content[offset:], ".") + 1
+	}
+	b.WriteString(content[last:])
+
+	return b.String(), nil
+}
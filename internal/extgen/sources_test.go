@@ -0,0 +1,281 @@
+package extgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSourceFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func TestCollectSourcePackages(t *testing.T) {
+	dir := writeSourceFiles(t, map[string]string{
+		"go.mod":              "module example.com/ext\n\ngo 1.25\n",
+		"ext.go":              "package ext\n",
+		"helpers.go":          "package ext\n",
+		"ext_test.go":         "package ext\n",
+		"ignored_windows.go":  "package ext\n",
+		"db/db.go":            "package database\n",
+		"db/pool/pool.go":     "package pool\n",
+		"build/ext.go":        "package ext\n",
+		"testdata/fixture.go": "package fixture\n",
+		"nested/go.mod":       "module example.com/nested\n",
+		"nested/nested.go":    "package nested\n",
+	})
+
+	packages, err := collectSourcePackages(dir, filepath.Join(dir, "build"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []sourcePackage{
+		{Name: "ext", Files: []string{filepath.Join(dir, "ext.go"), filepath.Join(dir, "helpers.go")}},
+		{Name: "database", ImportPath: "example.com/ext/db", Files: []string{filepath.Join(dir, "db", "db.go")}},
+		{Name: "pool", ImportPath: "example.com/ext/db/pool", Files: []string{filepath.Join(dir, "db", "pool", "pool.go")}},
+	}, packages)
+
+	packages, err = collectSourcePackages(filepath.Join(dir, "ext.go"), filepath.Join(dir, "build"))
+	require.NoError(t, err)
+	assert.Equal(t, []sourcePackage{{Files: []string{filepath.Join(dir, "ext.go")}}}, packages)
+}
+
+func TestCollectSourcePackagesErrors(t *testing.T) {
+	dir := writeSourceFiles(t, map[string]string{
+		"a.go": "package a\n",
+		"b.go": "package b\n",
+	})
+
+	_, err := collectSourcePackages(dir, filepath.Join(dir, "build"))
+	assert.ErrorContains(t, err, "found packages a and b")
+
+	_, err = collectSourcePackages(t.TempDir(), "build")
+	assert.ErrorContains(t, err, "no Go files found")
+
+	_, err = collectSourcePackages(filepath.Join(dir, "missing.go"), "build")
+	assert.Error(t, err)
+}
+
+func TestMergeSubpackageImports(t *testing.T) {
+	packages := []sourcePackage{
+		{Name: "ext"},
+		{Name: "database", ImportPath: "example.com/ext/db"},
+		{Name: "pool", ImportPath: "example.com/ext/db/pool"},
+	}
+
+	imports, qualifiers := mergeSubpackageImports([]string{
+		`"fmt"`,
+		`"example.com/ext/db"`,
+		`p "example.com/ext/db/pool"`,
+		`"example.com/other"`,
+	}, packages)
+
+	assert.Equal(t, []string{`"fmt"`, `"example.com/other"`}, imports)
+	assert.Equal(t, []string{"database", "p"}, qualifiers)
+}
+
+func TestUnqualifySubpackageReferences(t *testing.T) {
+	content := `package ext
+
+import "C"
+import "fmt"
+
+func count() int {
+	return db.Count() + len(db.Prefix)
+}
+
+func shadowed() {
+	db := struct{ Prefix string }{}
+	fmt.Println(db.Prefix)
+}
+`
+
+	result, err := unqualifySubpackageReferences(content, []string{"db"})
+	require.NoError(t, err)
+
+	assert.Contains(t, result, "return Count() + len(Prefix)")
+	assert.Contains(t, result, "fmt.Println(db.Prefix)")
+}
+
+func TestGeneratorParseSourceDirectory(t *testing.T) {
+	dir := writeSourceFiles(t, map[string]string{
+		"go.mod": "module example.com/ext\n\ngo 1.25\n",
+		"ext.go": `package ext
+
+//export_php:namespace Go\Ext
+
+//export_php:function greet(): void
+func greet() {}
+
+//export_php:enum Suit
+type Suit string
+
+const Hearts Suit = "H"
+`,
+		"suits.go": `package ext
+
+const Spades Suit = "S"
+
+//export_php:function best_suit(): Suit
+func best_suit() Suit {
+	return Spades
+}
+`,
+		"db/db.go": `package db
+
+//export_php:class Connection
+type Connection struct{}
+
+//export_php:const
+const MAX_CONNECTIONS = 10
+`,
+	})
+
+	generator := &Generator{BaseName: "ext", SourceFile: dir, BuildDir: filepath.Join(dir, "build")}
+	require.NoError(t, generator.parseSource())
+
+	assert.Equal(t, `Go\Ext`, generator.Namespace)
+	require.Len(t, generator.Functions, 2)
+	assert.Equal(t, "greet", generator.Functions[0].Name)
+	assert.Equal(t, "best_suit", generator.Functions[1].Name)
+	assert.NotNil(t, generator.Functions[1].ReturnEnum)
+	require.Len(t, generator.Enums, 1)
+	assert.Equal(t, []phpEnumCase{{"Hearts", "H"}, {"Spades", "S"}}, generator.Enums[0].Cases)
+	require.Len(t, generator.Classes, 1)
+	assert.Equal(t, "Connection", generator.Classes[0].Name)
+	require.Len(t, generator.Constants, 1)
+	assert.Equal(t, "MAX_CONNECTIONS", generator.Constants[0].Name)
+}
+
+func TestGeneratorParseSourceConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name: "duplicate function",
+			files: map[string]string{
+				"a.go": "package ext\n\n//export_php:function hello(): void\nfunc hello() {}\n",
+				"b.go": "package ext\n\n//export_php:function Hello(): void\nfunc hello2() {}\n",
+			},
+			expected: `duplicate PHP function "Hello" declared in`,
+		},
+		{
+			name: "class and enum with the same name",
+			files: map[string]string{
+				"a.go": "package ext\n\n//export_php:class Suit\ntype Card struct{}\n",
+				"b.go": "package ext\n\n//export_php:enum Suit\ntype Suit string\n\nconst Hearts Suit = \"H\"\n",
+			},
+			expected: `duplicate PHP class "Suit" declared in`,
+		},
+		{
+			name: "duplicate constant",
+			files: map[string]string{
+				"a.go":     "package ext\n\n//export_php:const\nconst MAX = 1\n",
+				"sub/b.go": "package sub\n\n//export_php:const\nconst MAX = 2\n",
+			},
+			expected: `duplicate PHP constant "MAX" declared in`,
+		},
+		{
+			name: "duplicate Go variable",
+			files: map[string]string{
+				"a.go": "package ext\n\nvar cache = map[string]string{}\n",
+				"b.go": "package ext\n\nvar (\n\tcount int\n\tcache []string\n)\n",
+			},
+			expected: `duplicate Go identifier "cache" declared in`,
+		},
+		{
+			name: "duplicate Go method",
+			files: map[string]string{
+				"a.go":     "package ext\n\ntype store struct{}\n\nfunc (s *store) get() {}\n",
+				"sub/b.go": "package sub\n\ntype store struct{}\n\nfunc (s store) get() {}\n",
+			},
+			expected: `duplicate Go identifier "store.get" declared in`,
+		},
+		{
+			name: "conflicting namespaces",
+			files: map[string]string{
+				"a.go": "package ext\n\n//export_php:namespace A\n\n//export_php:const\nconst MAX = 1\n",
+				"b.go": "package ext\n\n//export_php:namespace B\n",
+			},
+			expected: `conflicting namespaces "A"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeSourceFiles(t, tt.files)
+
+			generator := &Generator{BaseName: "ext", SourceFile: dir, BuildDir: filepath.Join(dir, "build")}
+			assert.ErrorContains(t, generator.parseSource(), tt.expected)
+		})
+	}
+}
+
+func TestGeneratorParseSourceGoIdentifierConflict(t *testing.T) {
+	dir := writeSourceFiles(t, map[string]string{
+		"ext.go":     "package ext\n\nfunc init() {}\n\nvar _ = helper\n\nfunc helper() string {\n\treturn \"ext\"\n}\n",
+		"sub/sub.go": "package sub\n\nfunc init() {}\n\nvar _ = helper\n\nfunc helper() string {\n\treturn \"sub\"\n}\n",
+	})
+
+	generator := &Generator{BaseName: "ext", SourceFile: dir, BuildDir: filepath.Join(dir, "build")}
+	err := generator.parseSource()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `duplicate Go identifier "helper" declared in`)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "ext.go"))
+	assert.Contains(t, err.Error(), filepath.Join(dir, "sub", "sub.go"))
+}
+
+func TestGoFileGeneratorMergesSubpackages(t *testing.T) {
+	dir := writeSourceFiles(t, map[string]string{
+		"go.mod": "module example.com/ext\n\ngo 1.25\n",
+		"ext.go": `package ext
+
+import (
+	"C"
+	"example.com/ext/db"
+)
+
+//export_php:function connections(): int
+func connections() int64 {
+	return db.Count()
+}
+`,
+		"db/db.go": `package db
+
+import "strings"
+
+var Prefix = "db"
+
+func Count() int64 {
+	return int64(len(strings.Repeat(Prefix, 5)))
+}
+`,
+	})
+
+	generator := &Generator{BaseName: "ext", SourceFile: dir, BuildDir: filepath.Join(dir, "build")}
+	require.NoError(t, generator.parseSource())
+
+	goGen := GoFileGenerator{generator}
+	content, err := goGen.buildContent()
+	require.NoError(t, err)
+
+	assert.NotContains(t, content, "example.com/ext/db")
+	assert.Contains(t, content, `import "strings"`)
+	assert.Contains(t, content, "return Count()")
+	assert.Contains(t, content, "func Count() int64 {")
+	assert.Contains(t, content, `var Prefix = "db"`)
+}
@@ -2,12 +2,17 @@ package extgen
 
 import (
 	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
 	"os"
+	"regexp"
 	"strings"
 )
 
+// standaloneDirectiveRegex matches the directives that don't apply to the following declaration
+var standaloneDirectiveRegex = regexp.MustCompile(`^//\s*export_php:(namespace|ini)\b`)
+
 type SourceAnalyzer struct{}
 
 func (sa *SourceAnalyzer) analyze(filename string) (imports []string, variables []string, internalFunctions []string, err error) {
@@ -39,6 +44,61 @@ func (sa *SourceAnalyzer) analyze(filename string) (imports []string, variables
 	return imports, variables, internalFunctions, nil
 }
 
+// declaredIdentifiers returns the package-level functions and variables declared in the file,
+// methods are prefixed by the name of their receiver type
+func (sa *SourceAnalyzer) declaredIdentifiers(filename string) ([]string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parsing file: %w", err)
+	}
+
+	var identifiers []string
+	for _, decl := range node.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			// several init functions can be declared in the same package
+			if d.Recv == nil && d.Name.Name == "init" {
+				continue
+			}
+
+			name := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				name = receiverTypeName(d.Recv.List[0].Type) + "." + name
+			}
+			identifiers = append(identifiers, name)
+		case *ast.GenDecl:
+			if d.Tok != token.VAR {
+				continue
+			}
+
+			for _, spec := range d.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					if name.Name != "_" {
+						identifiers = append(identifiers, name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return identifiers, nil
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+
+	return ""
+}
+
 func (sa *SourceAnalyzer) extractVariables(content string) []string {
 	lines := strings.Split(content, "\n")
 	var (
@@ -109,7 +169,7 @@ func (sa *SourceAnalyzer) extractInternalFunctions(content string) []string {
 					continue
 				}
 
-				if strings.Contains(prevLine, "export_php:") {
+				if strings.Contains(prevLine, "export_php:") && !standaloneDirectiveRegex.MatchString(prevLine) {
 					hasPHPFunc = true
 
 					break
@@ -351,6 +351,22 @@ func exported3() {}`,
 			}
 		}
 	}
+}`,
+			},
+		},
+		{
+			name: "function after a standalone directive",
+			content: `//export_php:ini my_ext.size 10
+
+func helper() int {
+	return 10
+}
+
+//export_php:minit
+func start() {}`,
+			expected: []string{
+				`func helper() int {
+	return 10
 }`,
 			},
 		},
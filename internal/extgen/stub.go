@@ -21,12 +21,15 @@ func (sg *StubGenerator) generate() error {
 		return err
 	}
 
-	return writeFile(filename, content)
+	sg.Generator.stubUpdated, err = updateFile(filename, content)
+
+	return err
 }
 
 func (sg *StubGenerator) buildContent() (string, error) {
 	tmpl, err := template.New("stub.php.tpl").Funcs(template.FuncMap{
-		"phpType": getPhpTypeAnnotation,
+		"phpType":   getPhpTypeAnnotation,
+		"phpString": phpStringLiteral,
 	}).Parse(templateContent)
 	if err != nil {
 		return "", err
@@ -57,3 +60,8 @@ func getPhpTypeAnnotation(t phpType) string {
 		return "int"
 	}
 }
+
+// phpStringLiteral quotes a string as a single-quoted PHP string
+func phpStringLiteral(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
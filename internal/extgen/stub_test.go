@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStubGenerator_Generate(t *testing.T) {
@@ -216,6 +217,25 @@ func TestStubGenerator_FunctionSignatures(t *testing.T) {
 			},
 			expected: "function process(array $data, ?string $prefix = null, bool $strict = false): ?array {}",
 		},
+		{
+			name: "function returning an error",
+			function: phpFunction{
+				Name:         "divide",
+				Signature:    "divide(int $a, int $b): int",
+				ReturnsError: true,
+			},
+			expected: "/**\n * @throws \\RuntimeException\n */\nfunction divide(int $a, int $b): int {}",
+		},
+		{
+			name: "function throwing a custom exception",
+			function: phpFunction{
+				Name:         "divide",
+				Signature:    "divide(int $a, int $b): int",
+				ReturnsError: true,
+				ThrowsClass:  `App\DivisionByZero`,
+			},
+			expected: "/**\n * @throws \\App\\DivisionByZero\n */\nfunction divide(int $a, int $b): int {}",
+		},
 	}
 
 	for _, tt := range tests {
@@ -260,6 +280,53 @@ func TestStubGenerator_ClassGeneration(t *testing.T) {
 				"}",
 			},
 		},
+		{
+			name: "class implementing interfaces",
+			class: phpClass{
+				Name:       "Bag",
+				Implements: []string{"Countable", "IteratorAggregate"},
+				Methods: []phpClassMethod{
+					{Name: "count", Signature: "count(): int", ReturnType: phpInt},
+					{Name: "getIterator", Signature: `getIterator(): \Iterator`, ReturnType: phpIterator},
+				},
+			},
+			contains: []string{
+				`class Bag implements \Countable, \IteratorAggregate {`,
+				"public function count(): int {}",
+				`public function getIterator(): \Iterator {}`,
+			},
+		},
+		{
+			name: "class with static methods and a constructor",
+			class: phpClass{
+				Name: "Point",
+				Constructor: &phpClassMethod{
+					Name:      "__construct",
+					Signature: "__construct(int $x, int $y = 0)",
+				},
+				Methods: []phpClassMethod{
+					{Name: "origin", Signature: "origin(): string", ReturnType: phpString, IsStatic: true},
+				},
+			},
+			contains: []string{
+				"public function __construct(int $x, int $y = 0) {}",
+				"public static function origin(): string {}",
+			},
+		},
+		{
+			name: "class with exposed properties",
+			class: phpClass{
+				Name: "User",
+				Properties: []phpClassProperty{
+					{Name: "ID", PhpType: phpInt, PhpName: "id", Visibility: "public", IsReadonly: true},
+					{Name: "Nickname", PhpType: phpString, IsNullable: true, PhpName: "nickname", Visibility: "protected"},
+					{Name: "password", PhpType: phpString},
+				},
+			},
+			contains: []string{
+				"    public readonly int $id;\n    protected ?string $nickname;\n",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -610,3 +677,23 @@ func testStubConstants(t *testing.T, content string, constants []phpConstant) {
 		}
 	}
 }
+
+func TestStubGenerator_Enums(t *testing.T) {
+	generator := &Generator{
+		Enums: []phpEnum{
+			{Name: "Suit", BackingType: phpString, Cases: []phpEnumCase{{"Hearts", "H"}, {"Quote", `it's`}}},
+			{Name: "Level", BackingType: phpInt, Cases: []phpEnumCase{{"Low", "1"}, {"High", "2"}}},
+		},
+		Functions: []phpFunction{
+			{Name: "opposite", Signature: "opposite(Suit $suit): Suit"},
+		},
+	}
+
+	stubGen := StubGenerator{generator}
+	content, err := stubGen.buildContent()
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "enum Suit: string {\n    case Hearts = 'H';\n    case Quote = 'it\\'s';\n}")
+	assert.Contains(t, content, "enum Level: int {\n    case Low = 1;\n    case High = 2;\n}")
+	assert.Contains(t, content, "function opposite(Suit $suit): Suit {}")
+}
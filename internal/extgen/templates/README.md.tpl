@@ -16,12 +16,37 @@ Auto-generated PHP extension from Go code.
 {{end}}
 {{end}}**Returns:** {{.ReturnType}}{{if .IsReturnNullable}} (nullable){{end}}
 
+{{end}}{{end}}{{if .Enums}}## Enums
+
+{{range .Enums}}### {{.Name}}
+
+Backed by {{.BackingType}} values.
+
+**Cases:**
+
+{{$enum := .}}{{range .Cases}}- `{{.Name}}`: {{if eq $enum.BackingType "string"}}`{{.Value}}`{{else}}{{.Value}}{{end}}
+{{end}}
 {{end}}{{end}}{{if .Classes}}## Classes
 
 {{range .Classes}}### {{.Name}}
 
-{{if .Properties}}**Properties:**
+{{if .Implements}}**Implements:** {{range $i, $iface := .Implements}}{{if $i}}, {{end}}`{{$iface}}`{{end}}
+
+{{end}}{{if .Constructor}}**Constructor:**
 
-{{range .Properties}}- `{{.Name}}`: {{.PhpType}}{{if .IsNullable}} (nullable){{end}}
+```php
+{{.Constructor.Signature}}
+```
+
+{{end}}{{if .Properties}}**Properties:**
+
+{{range .Properties}}- `{{if .PhpName}}{{.PhpName}}{{else}}{{.Name}}{{end}}`: {{.PhpType}}{{if .IsNullable}} (nullable){{end}}{{if .PhpName}} ({{.Visibility}}{{if .IsReadonly}}, readonly{{end}}){{end}}
 {{end}}
-{{end}}{{end}}{{end}}
+{{end}}{{if .Methods}}**Methods:**
+
+{{range .Methods}}- `{{if .IsStatic}}static {{end}}{{.Signature}}`
+{{end}}
+{{end}}{{end}}{{end}}{{if .IniEntries}}## INI Settings
+
+{{range .IniEntries}}- `{{.Name}}` (default: {{if .DefaultValue}}`{{.DefaultValue}}`{{else}}empty{{end}})
+{{end}}{{end}}
@@ -1,13 +1,74 @@
 #include <php.h>
 #include <Zend/zend_API.h>
+#include <Zend/zend_enum.h>
+#include <Zend/zend_exceptions.h>
 #include <Zend/zend_hash.h>
+#include <Zend/zend_interfaces.h>
 #include <Zend/zend_types.h>
+#include <ext/spl/spl_array.h>
+#include <ext/spl/spl_exceptions.h>
 #include <stddef.h>
+#include <stdlib.h>
 
 #include "{{.BaseName}}.h"
 #include "{{.BaseName}}_arginfo.h"
 #include "_cgo_export.h"
 
+/* BEGIN USER CODE: includes */
+/* END USER CODE: includes */
+{{- if .Enums}}
+{{range .Enums}}
+static zend_class_entry *{{.Name}}_ce = NULL;
+{{- end}}
+{{- end}}
+
+{{- if .IniEntries}}
+
+PHP_INI_BEGIN()
+{{- range .IniEntries}}
+    PHP_INI_ENTRY({{printf "%q" .Name}}, {{printf "%q" .DefaultValue}}, PHP_INI_ALL, NULL)
+{{- end}}
+PHP_INI_END()
+{{- end}}
+
+{{- if .ReturnsHookErrors}}
+
+/* reports the error returned by a Go lifecycle hook, the message is allocated by Go */
+static zend_result go_hook_result(char *error, const char *hook) {
+    if (error == NULL) {
+        return SUCCESS;
+    }
+
+    php_error_docref(NULL, E_WARNING, "%s() failed: %s", hook, error);
+    free(error);
+
+    return FAILURE;
+}
+{{- end}}
+
+{{- if .ThrowsExceptions}}
+
+/* throws the error returned by a Go function, the message is allocated by Go */
+static void throw_go_exception(char *message, const char *class_name) {
+    zend_class_entry *ce = spl_ce_RuntimeException;
+
+    if (class_name != NULL) {
+        zend_string *name = zend_string_init(class_name, strlen(class_name), 0);
+        zend_class_entry *custom_ce = zend_lookup_class(name);
+        zend_string_release(name);
+
+        if (custom_ce != NULL && instanceof_function(custom_ce, zend_ce_throwable)) {
+            ce = custom_ce;
+        } else {
+            php_error_docref(NULL, E_WARNING, "Exception class %s not found, throwing a RuntimeException instead", class_name);
+        }
+    }
+
+    zend_throw_exception(ce, message, 0);
+    free(message);
+}
+{{- end}}
+
 {{- if .Classes}}
 
 #define VALIDATE_GO_HANDLE(intern) \
@@ -18,6 +79,53 @@
         } \
     } while (0)
 
+{{- if .ExposesProperties}}
+
+/* returns an error message allocated by Go, or NULL on success */
+typedef char *(*go_property_reader)(uintptr_t handle, int index, zval *value);
+typedef void (*go_property_writer)(uintptr_t handle, int index, zval *value);
+
+typedef struct {
+    const char *name;
+    bool readonly;
+} go_property;
+
+/* copies the fields of the Go struct to the PHP properties, readonly properties are only set when initializing the object */
+static void go_properties_to_php(zend_object *object, zend_class_entry *scope, uintptr_t handle, const go_property *properties, int count, go_property_reader read, bool initialize) {
+    for (int i = 0; i < count; i++) {
+        if (properties[i].readonly && !initialize) {
+            continue;
+        }
+
+        zval value;
+        ZVAL_NULL(&value);
+        char *error = read(handle, i, &value);
+        if (error != NULL) {
+            zend_throw_error(NULL, "Unable to read the %s property: %s", properties[i].name, error);
+            free(error);
+
+            return;
+        }
+
+        zend_update_property(scope, object, properties[i].name, strlen(properties[i].name), &value);
+        zval_ptr_dtor(&value);
+    }
+}
+
+/* copies the PHP properties, which may have been changed by PHP code, to the fields of the Go struct */
+static void go_properties_from_php(zend_object *object, zend_class_entry *scope, uintptr_t handle, const go_property *properties, int count, go_property_writer write) {
+    for (int i = 0; i < count; i++) {
+        if (properties[i].readonly) {
+            continue;
+        }
+
+        zval rv;
+        zval *value = zend_read_property(scope, object, properties[i].name, strlen(properties[i].name), true, &rv);
+        write(handle, i, value);
+    }
+}
+{{- end}}
+
 static zend_object_handlers object_handlers_{{.BaseName}};
 
 typedef struct {
@@ -58,11 +166,26 @@ void init_object_handlers() {
     object_handlers_{{.BaseName}}.offset = offsetof({{.BaseName}}_object, std);
 }
 {{- end}}
-{{ range .Classes}}
+{{ range $class := .Classes}}
 static zend_class_entry *{{.Name}}_ce = NULL;
+{{- if .ExposedProperties}}
+
+static const go_property {{.Name}}_properties[] = {
+{{- range .ExposedProperties}}
+    {"{{.PhpName}}", {{if .IsReadonly}}true{{else}}false{{end}}},
+{{- end}}
+};
+{{- end}}
 
 PHP_METHOD({{namespacedClassName $.Namespace .Name}}, __construct) {
+{{- if .Constructor}}
+{{- with paramDeclarations .Constructor.Params}}
+{{.}}
+{{- end}}
+{{paramParsing .Constructor.Params}}
+{{- else}}
     ZEND_PARSE_PARAMETERS_NONE();
+{{- end}}
 
     {{$.BaseName}}_object *intern = {{$.BaseName}}_object_from_obj(Z_OBJ_P(ZEND_THIS));
 
@@ -71,80 +194,74 @@ PHP_METHOD({{namespacedClassName $.Namespace .Name}}, __construct) {
         return;
     }
 
-    intern->go_handle = create_{{.GoStruct}}_object();
+    intern->go_handle = create_{{.GoStruct}}_object({{if .Constructor}}{{callParams .Constructor.Params}}{{end}});
+{{- if .ExposedProperties}}
+    if (intern->go_handle != 0) {
+        go_properties_to_php(&intern->std, {{.Name}}_ce, intern->go_handle, {{.Name}}_properties, {{len .ExposedProperties}}, {{.GoStruct}}_read_property, true);
+    }
+{{- end}}
 }
-
 {{ range .Methods}}
 PHP_METHOD({{namespacedClassName $.Namespace .ClassName}}, {{.PhpName}}) {
+{{- if not .IsStatic}}
     {{$.BaseName}}_object *intern = {{$.BaseName}}_object_from_obj(Z_OBJ_P(ZEND_THIS));
-    
+
     VALIDATE_GO_HANDLE(intern);
-    
-    {{- if .Params -}}
-    {{range $i, $param := .Params -}}
-    {{- if eq $param.PhpType "string"}}
-    zend_string *{{$param.Name}} = NULL;{{if $param.IsNullable}}
-    zend_bool {{$param.Name}}_is_null = 0;{{end}}
-    {{- else if eq $param.PhpType "int"}}
-    zend_long {{$param.Name}} = {{if $param.HasDefault}}{{$param.DefaultValue}}{{else}}0{{end}};{{if $param.IsNullable}}
-    zend_bool {{$param.Name}}_is_null = 0;{{end}}
-    {{- else if eq $param.PhpType "float"}}
-    double {{$param.Name}} = {{if $param.HasDefault}}{{$param.DefaultValue}}{{else}}0.0{{end}};{{if $param.IsNullable}}
-    zend_bool {{$param.Name}}_is_null = 0;{{end}}
-    {{- else if eq $param.PhpType "bool"}}
-    zend_bool {{$param.Name}} = {{if $param.HasDefault}}{{if eq $param.DefaultValue "true"}}1{{else}}0{{end}}{{else}}0{{end}};{{if $param.IsNullable}}
-    zend_bool {{$param.Name}}_is_null = 0;{{end}}
-    {{- else if eq $param.PhpType "array"}}
-    zval *{{$param.Name}} = NULL;
-    {{- end}}
-    {{- end}}
-    
-    {{$requiredCount := 0}}{{range .Params}}{{if not .HasDefault}}{{$requiredCount = add1 $requiredCount}}{{end}}{{end -}}
-    ZEND_PARSE_PARAMETERS_START({{$requiredCount}}, {{len .Params}})
-        {{$optionalStarted := false}}{{range .Params}}{{if .HasDefault}}{{if not $optionalStarted -}}
-        Z_PARAM_OPTIONAL
-        {{$optionalStarted = true}}{{end}}{{end -}}
-        {{if .IsNullable}}{{if eq .PhpType "string"}}Z_PARAM_STR_OR_NULL({{.Name}}, {{.Name}}_is_null){{else if eq .PhpType "int"}}Z_PARAM_LONG_OR_NULL({{.Name}}, {{.Name}}_is_null){{else if eq .PhpType "float"}}Z_PARAM_DOUBLE_OR_NULL({{.Name}}, {{.Name}}_is_null){{else if eq .PhpType "bool"}}Z_PARAM_BOOL_OR_NULL({{.Name}}, {{.Name}}_is_null){{else if eq .PhpType "array"}}Z_PARAM_ARRAY_OR_NULL({{.Name}}){{end}}{{else}}{{if eq .PhpType "string"}}Z_PARAM_STR({{.Name}}){{else if eq .PhpType "int"}}Z_PARAM_LONG({{.Name}}){{else if eq .PhpType "float"}}Z_PARAM_DOUBLE({{.Name}}){{else if eq .PhpType "bool"}}Z_PARAM_BOOL({{.Name}}){{else if eq .PhpType "array"}}Z_PARAM_ARRAY({{.Name}}){{end}}{{end}}
-        {{end -}}
-    ZEND_PARSE_PARAMETERS_END();
-    {{else}}
-    ZEND_PARSE_PARAMETERS_NONE();
-    {{end}}
-    
-    {{- if ne .ReturnType "void"}}
-    {{- if eq .ReturnType "string"}}
-    zend_string* result = {{.Name}}_wrapper(intern->go_handle{{if .Params}}{{range .Params}}, {{if .IsNullable}}{{if eq .PhpType "string"}}{{.Name}}_is_null ? NULL : {{.Name}}{{else if eq .PhpType "int"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "float"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "bool"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "array"}}{{.Name}}{{end}}{{else}}{{.Name}}{{end}}{{end}}{{end}});
-    RETURN_STR(result);
-    {{- else if eq .ReturnType "int"}}
-    zend_long result = {{.Name}}_wrapper(intern->go_handle{{if .Params}}{{range .Params}}, {{if .IsNullable}}{{if eq .PhpType "string"}}{{.Name}}_is_null ? NULL : {{.Name}}{{else if eq .PhpType "int"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "float"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "bool"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "array"}}{{.Name}}{{end}}{{else}}{{if eq .PhpType "array"}}{{.Name}}{{else}}(long){{.Name}}{{end}}{{end}}{{end}}{{end}});
+{{end}}
+{{- with paramDeclarations .Params}}
+{{.}}
+{{- end}}
+{{paramParsing .Params}}
+{{- if and $class.ExposedProperties (not .IsStatic)}}
+
+    go_properties_from_php(&intern->std, {{$class.Name}}_ce, intern->go_handle, {{$class.Name}}_properties, {{len $class.ExposedProperties}}, {{$class.GoStruct}}_write_property);
+{{- end}}
+
+    {{if eq .ReturnType "string"}}zend_string *result = {{else if eq .ReturnType "int"}}zend_long result = {{else if eq .ReturnType "float"}}double result = {{else if eq .ReturnType "bool"}}int result = {{else if eq .ReturnType "array" "Iterator"}}zend_array *result = {{end}}{{.Name}}_wrapper({{methodCallArgs .}});
+{{- if and $class.ExposedProperties (not .IsStatic)}}
+    go_properties_to_php(&intern->std, {{$class.Name}}_ce, intern->go_handle, {{$class.Name}}_properties, {{len $class.ExposedProperties}}, {{$class.GoStruct}}_read_property, false);
+{{- end}}
+{{- if eq .ReturnType "string"}}
+
+    if (result) {
+        RETURN_STR(result);
+    }
+
+    RETURN_EMPTY_STRING();
+{{- else if eq .ReturnType "int"}}
     RETURN_LONG(result);
-    {{- else if eq .ReturnType "float"}}
-    double result = {{.Name}}_wrapper(intern->go_handle{{if .Params}}{{range .Params}}, {{if .IsNullable}}{{if eq .PhpType "string"}}{{.Name}}_is_null ? NULL : {{.Name}}{{else if eq .PhpType "int"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "float"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "bool"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "array"}}{{.Name}}{{end}}{{else}}{{if eq .PhpType "array"}}{{.Name}}{{else}}(double){{.Name}}{{end}}{{end}}{{end}}{{end}});
+{{- else if eq .ReturnType "float"}}
     RETURN_DOUBLE(result);
-    {{- else if eq .ReturnType "bool"}}
-    int result = {{.Name}}_wrapper(intern->go_handle{{if .Params}}{{range .Params}}, {{if .IsNullable}}{{if eq .PhpType "string"}}{{.Name}}_is_null ? NULL : {{.Name}}{{else if eq .PhpType "int"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "float"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "bool"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "array"}}{{.Name}}{{end}}{{else}}{{if eq .PhpType "array"}}{{.Name}}{{else}}(int){{.Name}}{{end}}{{end}}{{end}}{{end}});
+{{- else if eq .ReturnType "bool"}}
     RETURN_BOOL(result);
-    {{- else if eq .ReturnType "array"}}
-    void* result = {{.Name}}_wrapper(intern->go_handle{{if .Params}}{{range .Params}}, {{if .IsNullable}}{{if eq .PhpType "string"}}{{.Name}}_is_null ? NULL : {{.Name}}{{else if eq .PhpType "int"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "float"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "bool"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "array"}}{{.Name}}{{end}}{{else}}{{.Name}}{{end}}{{end}}{{end}});
-    if (result != NULL) {
-        HashTable *ht = (HashTable*)result;
-        RETURN_ARR(ht);
+{{- else if eq .ReturnType "array"}}
+
+    if (result) {
+        RETURN_ARR(result);
+    }
+
+    RETURN_NULL();
+{{- else if eq .ReturnType "Iterator"}}
+
+    zval array;
+    if (result) {
+        ZVAL_ARR(&array, result);
     } else {
-        RETURN_NULL();
+        ZVAL_EMPTY_ARRAY(&array);
     }
-    {{- end}}
-    {{- else}}
-    {{.Name}}_wrapper(intern->go_handle{{if .Params}}{{range .Params}}, {{if .IsNullable}}{{if eq .PhpType "string"}}{{.Name}}_is_null ? NULL : {{.Name}}{{else if eq .PhpType "int"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "float"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "bool"}}{{.Name}}_is_null ? NULL : &{{.Name}}{{else if eq .PhpType "array"}}{{.Name}}{{end}}{{else}}{{if eq .PhpType "string"}}{{.Name}}{{else if eq .PhpType "int"}}(long){{.Name}}{{else if eq .PhpType "float"}}(double){{.Name}}{{else if eq .PhpType "bool"}}(int){{.Name}}{{else if eq .PhpType "array"}}{{.Name}}{{end}}{{end}}{{end}}{{end}});
-    {{- end}}
+
+    object_init_ex(return_value, spl_ce_ArrayIterator);
+    zend_call_known_instance_method_with_1_params(spl_ce_ArrayIterator->constructor, Z_OBJ_P(return_value), NULL, &array);
+    zval_ptr_dtor(&array);
+{{- end}}
 }
 {{end}}{{end}}
-
 {{- if .Classes}}
 void register_all_classes() {
     init_object_handlers();
     
     {{- range .Classes}}
-    {{.Name}}_ce = register_class_{{namespacedClassName $.Namespace .Name}}();
+    {{.Name}}_ce = register_class_{{namespacedClassName $.Namespace .Name}}({{interfaceClassEntries .}});
     if (!{{.Name}}_ce) {
         php_error_docref(NULL, E_ERROR, "Failed to register class {{.Name}}");
         return;
@@ -154,8 +271,26 @@ void register_all_classes() {
 }
 {{- end}}
 
+{{- define "hookCalls"}}
+{{- range .}}
+{{- if .ReturnsError}}
+    if (go_hook_result({{.Name}}_hook(), "{{.Name}}") == FAILURE) {
+        return FAILURE;
+    }
+{{- else}}
+    {{.Name}}();
+{{- end}}
+{{- end}}
+{{- end}}
+
 PHP_MINIT_FUNCTION({{.BaseName}}) {
+    {{- if .IniEntries}}
+    REGISTER_INI_ENTRIES();
+    {{- end}}
     {{ if .Classes}}register_all_classes();{{end}}
+    {{- range .Enums}}
+    {{.Name}}_ce = register_class_{{namespacedClassName $.Namespace .Name}}();
+    {{- end}}
     
     {{- range .Constants}}
     {{- if eq .ClassName ""}}
@@ -167,17 +302,48 @@ PHP_MINIT_FUNCTION({{.BaseName}}) {
     {{- end}}
     {{- end}}
     {{- end}}
+    {{- template "hookCalls" hooks "minit"}}
+    return SUCCESS;
+}
+{{- if or .IniEntries (hooks "mshutdown")}}
+
+PHP_MSHUTDOWN_FUNCTION({{.BaseName}}) {
+    {{- template "hookCalls" hooks "mshutdown"}}
+    {{- if .IniEntries}}
+    UNREGISTER_INI_ENTRIES();
+    {{- end}}
     return SUCCESS;
 }
+{{- end}}
+{{- if hooks "rinit"}}
+
+PHP_RINIT_FUNCTION({{.BaseName}}) {
+    {{- template "hookCalls" hooks "rinit"}}
+    return SUCCESS;
+}
+{{- end}}
+{{- if hooks "rshutdown"}}
+
+PHP_RSHUTDOWN_FUNCTION({{.BaseName}}) {
+    {{- template "hookCalls" hooks "rshutdown"}}
+    return SUCCESS;
+}
+{{- end}}
+{{- if .IniEntries}}
+
+PHP_MINFO_FUNCTION({{.BaseName}}) {
+    DISPLAY_INI_ENTRIES();
+}
+{{- end}}
 
 zend_module_entry {{.BaseName}}_module_entry = {STANDARD_MODULE_HEADER,
                                          "{{.BaseName}}",
                                          ext_functions,             /* Functions */
                                          PHP_MINIT({{.BaseName}}),  /* MINIT */
-                                         NULL,                      /* MSHUTDOWN */
-                                         NULL,                      /* RINIT */
-                                         NULL,                      /* RSHUTDOWN */
-                                         NULL,                      /* MINFO */
+                                         {{if or .IniEntries (hooks "mshutdown")}}PHP_MSHUTDOWN({{.BaseName}}){{else}}NULL{{end}}, /* MSHUTDOWN */
+                                         {{if hooks "rinit"}}PHP_RINIT({{.BaseName}}){{else}}NULL{{end}}, /* RINIT */
+                                         {{if hooks "rshutdown"}}PHP_RSHUTDOWN({{.BaseName}}){{else}}NULL{{end}}, /* RSHUTDOWN */
+                                         {{if .IniEntries}}PHP_MINFO({{.BaseName}}){{else}}NULL{{end}}, /* MINFO */
                                          "1.0.0",                   /* Version */
                                          STANDARD_MODULE_PROPERTIES};
 
@@ -9,6 +9,9 @@ import "C"
 import {{.}}
 {{- end}}
 
+// BEGIN USER CODE: imports
+// END USER CODE: imports
+
 func init() {
 	frankenphp.RegisterExtension(unsafe.Pointer(&C.{{.BaseName}}_module_entry))
 }
@@ -24,10 +27,44 @@ const {{.Name}} = {{.Value}}
 {{.}}
 {{- end}}
 
+{{- range .Enums}}
+
+type {{.GoType}} {{.GoBackingType}}
+
+const (
+{{- $enum := .}}
+{{- range .Cases}}
+	{{.Name}} {{$enum.GoType}} = {{if eq $enum.BackingType "string"}}{{printf "%q" .Value}}{{else}}{{.Value}}{{end}}
+{{- end}}
+)
+{{- end}}
+
 {{- range .Functions}}
+{{- if needsWrapper .}}
+{{.GoFunction}}
+{{functionWrapper .}}
+{{- else}}
+//export {{.Name}}
+{{.GoFunction}}
+{{- end}}
+{{- end}}
+
+{{- range .Hooks}}
+{{- if .ReturnsError}}
+{{.GoFunction}}
+//export {{.Name}}_hook
+func {{.Name}}_hook() *C.char {
+	if err := {{.Name}}(); err != nil {
+		return C.CString(err.Error())
+	}
+
+	return nil
+}
+{{- else}}
 //export {{.Name}}
 {{.GoFunction}}
 {{- end}}
+{{- end}}
 
 {{- range .Classes}}
 type {{.GoStruct}} struct {
@@ -61,10 +98,60 @@ func removeGoObject(handle C.uintptr_t) {
 
 {{- range $class := .Classes}}
 //export create_{{.GoStruct}}_object
-func create_{{.GoStruct}}_object() C.uintptr_t {
+func create_{{.GoStruct}}_object({{if .Constructor}}{{wrapperParams .Constructor.Params}}{{end}}) C.uintptr_t {
+{{- if .Constructor}}
+	obj := {{.Constructor.GoName}}({{range $i, $param := .Constructor.Params}}{{if $i}}, {{end}}{{$param.Name}}{{end}})
+	if obj == nil {
+		return 0
+	}
+
+{{- else}}
 	obj := &{{.GoStruct}}{}
+{{- end}}
 	return registerGoObject(obj)
 }
+{{- if .Constructor}}
+
+{{.Constructor.GoFunction}}
+{{- end}}
+
+{{- if .ExposedProperties}}
+//export {{.GoStruct}}_read_property
+func {{.GoStruct}}_read_property(handle C.uintptr_t, index C.int, value *C.zval) *C.char {
+	obj := getGoObject(handle).(*{{.GoStruct}})
+
+	var v any
+	switch index {
+{{- range $i, $prop := .ExposedProperties}}
+	case {{$i}}:
+		v = obj.{{$prop.Name}}
+{{- end}}
+	}
+
+	zval, err := frankenphp.PHPValue(v)
+	if err != nil {
+		return C.CString(err.Error())
+	}
+
+	*value = *(*C.zval)(zval)
+
+	return nil
+}
+
+//export {{.GoStruct}}_write_property
+func {{.GoStruct}}_write_property(handle C.uintptr_t, index C.int, value *C.zval) {
+	obj := getGoObject(handle).(*{{.GoStruct}})
+
+	switch index {
+{{- range $i, $prop := .ExposedProperties}}
+	case {{$i}}:
+		if v, err := frankenphp.GoValue[{{if $prop.IsNullable}}*{{end}}{{$prop.GoType}}](unsafe.Pointer(value)); err == nil {
+			obj.{{$prop.Name}} = v
+		}
+{{- end}}
+	}
+}
+{{- end}}
 
 {{- range .Methods}}
 {{- if .GoFunction}}
@@ -74,11 +161,16 @@ func create_{{.GoStruct}}_object() C.uintptr_t {
 
 {{- range .Methods}}
 //export {{.Name}}_wrapper
-func {{.Name}}_wrapper(handle C.uintptr_t{{range .Params}}{{if eq .PhpType "string"}}, {{.Name}} *C.zend_string{{else if eq .PhpType "array"}}, {{.Name}} *C.zval{{else}}, {{.Name}} {{if .IsNullable}}*{{end}}{{phpTypeToGoType .PhpType}}{{end}}{{end}}){{if not (isVoid .ReturnType)}}{{if isStringOrArray .ReturnType}} unsafe.Pointer{{else}} {{phpTypeToGoType .ReturnType}}{{end}}{{end}} {
+func {{.Name}}_wrapper({{wrapperArgs .}}){{if eq .ReturnType "mixed"}}{{else if not (isVoid .ReturnType)}}{{if isPointerReturn .ReturnType}} unsafe.Pointer{{else}} {{phpTypeToGoType .ReturnType}}{{end}}{{end}} {
+{{- if .IsStatic}}
+	{{if eq .ReturnType "mixed"}}result := {{else if not (isVoid .ReturnType)}}return {{end}}{{goMethodName .}}({{range $i, $param := .Params}}{{if $i}}, {{end}}{{$param.Name}}{{end}})
+{{- else}}
 	obj := getGoObject(handle)
 	if obj == nil {
-{{- if not (isVoid .ReturnType)}}
-{{- if isStringOrArray .ReturnType}}
+{{- if eq .ReturnType "mixed"}}
+		return
+{{- else if not (isVoid .ReturnType)}}
+{{- if isPointerReturn .ReturnType}}
 		return nil
 {{- else}}
 		var zero {{phpTypeToGoType .ReturnType}}
@@ -89,7 +181,17 @@ func {{.Name}}_wrapper(handle C.uintptr_t{{range .Params}}{{if eq .PhpType "stri
 {{- end}}
 	}
 	structObj := obj.(*{{$class.GoStruct}})
-	{{if not (isVoid .ReturnType)}}return {{end}}structObj.{{.Name | title}}({{range $i, $param := .Params}}{{if $i}}, {{end}}{{$param.Name}}{{end}})
+	{{if eq .ReturnType "mixed"}}result := {{else if not (isVoid .ReturnType)}}return {{end}}structObj.{{goMethodName .}}({{range $i, $param := .Params}}{{if $i}}, {{end}}{{$param.Name}}{{end}})
+{{- end}}
+{{- if eq .ReturnType "mixed"}}
+
+	if zval, err := frankenphp.PHPValue(result); err == nil {
+		*returnValue = *(*C.zval)(zval)
+	}
+{{- end}}
 }
 {{end}}
 {{- end}}
+
+// BEGIN USER CODE: code
+// END USER CODE: code
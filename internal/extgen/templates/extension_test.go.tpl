@@ -0,0 +1,93 @@
+package {{.PackageName}}
+
+// The .phpt files of the tests directory are executed by the test binary, which embeds PHP and the extension.
+// Only the --TEST--, --FILE-- and --EXPECT-- sections are supported.
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dunglas/frankenphp"
+)
+
+// phptScriptEnv is set when the test binary is executed as a PHP CLI running a test script
+const phptScriptEnv = "FRANKENPHP_PHPT_SCRIPT"
+
+var phptSectionRegex = regexp.MustCompile(`(?m)^--([A-Z_]+)--\r?\n`)
+
+func TestMain(m *testing.M) {
+	if script := os.Getenv(phptScriptEnv); script != "" {
+		os.Exit(frankenphp.ExecuteScriptCLI(script, []string{os.Args[0], script}))
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestPHPT(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("tests", "*.phpt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".phpt")
+
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sections := parsePHPT(string(content))
+			code, ok := sections["FILE"]
+			if !ok {
+				t.Fatalf("%s: missing --FILE-- section", file)
+			}
+			expected, ok := sections["EXPECT"]
+			if !ok {
+				t.Fatalf("%s: missing --EXPECT-- section", file)
+			}
+
+			script := filepath.Join(t.TempDir(), name+".php")
+			if err := os.WriteFile(script, []byte(code), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := exec.Command(os.Args[0])
+			cmd.Env = append(os.Environ(), phptScriptEnv+"="+script)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Errorf("%s: %v", file, err)
+			}
+
+			if actual := normalizePHPTOutput(string(output)); actual != normalizePHPTOutput(expected) {
+				t.Errorf("%s: %s\n--- expected\n%s\n--- actual\n%s", file, sections["TEST"], expected, actual)
+			}
+		})
+	}
+}
+
+// parsePHPT returns the sections of a .phpt file indexed by name
+func parsePHPT(content string) map[string]string {
+	sections := make(map[string]string)
+
+	matches := phptSectionRegex.FindAllStringSubmatchIndex(content, -1)
+	for i, match := range matches {
+		end := len(content)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+
+		sections[content[match[2]:match[3]]] = content[match[1]:end]
+	}
+
+	return sections
+}
+
+func normalizePHPTOutput(output string) string {
+	return strings.TrimSpace(strings.ReplaceAll(output, "\r\n", "\n"))
+}
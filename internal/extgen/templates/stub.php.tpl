@@ -15,9 +15,16 @@ const {{.Name}} = UNKNOWN;
  */
 const {{.Name}} = {{.Value}};
 
-{{end}}{{end}}{{end}}{{range .Functions}}function {{.Signature}} {}
+{{end}}{{end}}{{end}}{{range .Enums}}{{$enum := .}}enum {{.Name}}: {{.BackingType}} {
+{{range .Cases}}    case {{.Name}} = {{if eq $enum.BackingType "string"}}{{phpString .Value}}{{else}}{{.Value}}{{end}};
+{{end}}}
 
-{{end}}{{range .Classes}}{{$className := .Name}}class {{.Name}} {
+{{end}}{{range .Functions}}{{if .ReturnsError}}/**
+ * @throws \{{if .ThrowsClass}}{{.ThrowsClass}}{{else}}RuntimeException{{end}}
+ */
+{{end}}function {{.Signature}} {}
+
+{{end}}{{range .Classes}}{{$className := .Name}}class {{.Name}}{{range $i, $iface := .Implements}}{{if $i}},{{else}} implements{{end}} \{{$iface}}{{end}} {
 {{range $.Constants}}{{if eq .ClassName $className}}{{if .IsIota}}    /**
      * @var int
      * @cvalue {{.Name}}
@@ -29,10 +36,11 @@ const {{.Name}} = {{.Value}};
      */
     public const {{.Name}} = {{.Value}};
 
-{{end}}{{end}}{{end}}
-    public function __construct() {}
+{{end}}{{end}}{{end}}{{range .ExposedProperties}}    {{.Visibility}} {{if .IsReadonly}}readonly {{end}}{{if .IsNullable}}?{{end}}{{phpType .PhpType}} ${{.PhpName}};
+{{end}}
+    public function {{if .Constructor}}{{.Constructor.Signature}}{{else}}__construct(){{end}} {}
 {{range .Methods}}
-    public function {{.Signature}} {}
+    public {{if .IsStatic}}static {{end}}function {{.Signature}} {}
 {{end}}
 }
 
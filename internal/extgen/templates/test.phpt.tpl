@@ -0,0 +1,12 @@
+--TEST--
+{{.Title}}
+--FILE--
+<?php
+
+declare(strict_types=1);
+{{if .Namespace}}
+namespace {{.Namespace}};
+{{end}}
+{{.Code}}
+--EXPECT--
+{{.Expect}}
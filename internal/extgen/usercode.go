@@ -0,0 +1,44 @@
+package extgen
+
+import (
+	"os"
+	"regexp"
+)
+
+// userCodeRegex matches the regions of the generated files that can be edited by hand, delimited by
+// "BEGIN USER CODE: name" and "END USER CODE: name" comments
+var userCodeRegex = regexp.MustCompile(`(?ms)^([ \t]*(?://|/\*) BEGIN USER CODE: ([\w-]+)[^\n]*\n)(.*?)(^[ \t]*(?://|/\*) END USER CODE: ([\w-]+))`)
+
+// preserveUserCode copies the content of the user code regions of the previous version of a file to the new one
+func preserveUserCode(content, previous string) string {
+	regions := make(map[string]string)
+	for _, matches := range userCodeRegex.FindAllStringSubmatch(previous, -1) {
+		if matches[2] == matches[5] {
+			regions[matches[2]] = matches[3]
+		}
+	}
+
+	if len(regions) == 0 {
+		return content
+	}
+
+	return userCodeRegex.ReplaceAllStringFunc(content, func(region string) string {
+		matches := userCodeRegex.FindStringSubmatch(region)
+
+		body, ok := regions[matches[2]]
+		if !ok || matches[2] != matches[5] {
+			return region
+		}
+
+		return matches[1] + body + matches[4]
+	})
+}
+
+// writeFileWithUserCode writes a generated file, keeping the user code regions of the existing file
+func writeFileWithUserCode(filename, content string) error {
+	if previous, err := os.ReadFile(filename); err == nil {
+		content = preserveUserCode(content, string(previous))
+	}
+
+	return writeFile(filename, content)
+}
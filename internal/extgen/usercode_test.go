@@ -0,0 +1,88 @@
+package extgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreserveUserCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		previous string
+		expected string
+	}{
+		{
+			name:     "no previous file",
+			content:  "a\n// BEGIN USER CODE: code\n// END USER CODE: code\n",
+			previous: "",
+			expected: "a\n// BEGIN USER CODE: code\n// END USER CODE: code\n",
+		},
+		{
+			name:     "Go region",
+			content:  "new\n// BEGIN USER CODE: code\n// END USER CODE: code\n",
+			previous: "old\n// BEGIN USER CODE: code\nfunc helper() {}\n// END USER CODE: code\n",
+			expected: "new\n// BEGIN USER CODE: code\nfunc helper() {}\n// END USER CODE: code\n",
+		},
+		{
+			name:     "multiple C regions",
+			content:  "/* BEGIN USER CODE: includes */\n/* END USER CODE: includes */\nnew\n/* BEGIN USER CODE: code */\n/* END USER CODE: code */\n",
+			previous: "/* BEGIN USER CODE: includes */\n#include <math.h>\n/* END USER CODE: includes */\nold\n/* BEGIN USER CODE: code */\nstatic int i;\n\nstatic int j;\n/* END USER CODE: code */\n",
+			expected: "/* BEGIN USER CODE: includes */\n#include <math.h>\n/* END USER CODE: includes */\nnew\n/* BEGIN USER CODE: code */\nstatic int i;\n\nstatic int j;\n/* END USER CODE: code */\n",
+		},
+		{
+			name:     "region removed from the new content",
+			content:  "new\n",
+			previous: "// BEGIN USER CODE: code\nfunc helper() {}\n// END USER CODE: code\n",
+			expected: "new\n",
+		},
+		{
+			name:     "unbalanced region in the previous file",
+			content:  "// BEGIN USER CODE: code\n// END USER CODE: code\n",
+			previous: "// BEGIN USER CODE: code\nfunc helper() {}\n// END USER CODE: other\n",
+			expected: "// BEGIN USER CODE: code\n// END USER CODE: code\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, preserveUserCode(tt.content, tt.previous))
+		})
+	}
+}
+
+func TestGenerateKeepsUserCode(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceFile := filepath.Join(tmpDir, "ext.go")
+	require.NoError(t, os.WriteFile(sourceFile, []byte(`package ext
+
+import "C"
+
+//export_php:function answer(): int
+func answer() int64 {
+	return 42
+}`), 0644))
+
+	generator := &Generator{BaseName: "ext", SourceFile: sourceFile, BuildDir: filepath.Join(tmpDir, "build")}
+	require.NoError(t, generator.setupBuildDirectory())
+	require.NoError(t, generator.parseSource())
+	require.NoError(t, generator.generateGoFile())
+
+	goFile := filepath.Join(generator.BuildDir, "ext.go")
+	content, err := os.ReadFile(goFile)
+	require.NoError(t, err)
+
+	edited := preserveUserCode(string(content), "// BEGIN USER CODE: code\nfunc helper() int64 {\n\treturn 1\n}\n// END USER CODE: code\n")
+	require.NoError(t, os.WriteFile(goFile, []byte(edited), 0644))
+
+	require.NoError(t, generator.setupBuildDirectory())
+	require.NoError(t, generator.generateGoFile())
+
+	content, err = os.ReadFile(goFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "// BEGIN USER CODE: code\nfunc helper() int64 {\n\treturn 1\n}\n// END USER CODE: code\n")
+}
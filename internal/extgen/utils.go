@@ -6,7 +6,29 @@ import (
 	"unicode"
 )
 
+// writeFile writes the content to the file unless it is already up to date,
+// to keep the modification time of unchanged files and avoid useless rebuilds
 func writeFile(filename, content string) error {
+	_, err := updateFile(filename, content)
+
+	return err
+}
+
+// updateFile writes the content to the file unless it is already up to date and reports whether the file was written
+func updateFile(filename, content string) (bool, error) {
+	if current, err := os.ReadFile(filename); err == nil && string(current) == content {
+		return false, nil
+	}
+
+	return true, os.WriteFile(filename, []byte(content), 0644)
+}
+
+// createFile writes the content to the file unless it already exists, to keep the changes made by the user
+func createFile(filename, content string) error {
+	if _, err := os.Stat(filename); err == nil {
+		return nil
+	}
+
 	return os.WriteFile(filename, []byte(content), 0644)
 }
 
@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWriteFile(t *testing.T) {
@@ -73,6 +75,33 @@ func TestWriteFile(t *testing.T) {
 	}
 }
 
+func TestUpdateFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.txt")
+
+	updated, err := updateFile(filename, "hello")
+	require.NoError(t, err)
+	assert.True(t, updated, "a missing file must be written")
+
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filename, past, past))
+
+	updated, err = updateFile(filename, "hello")
+	require.NoError(t, err)
+	assert.False(t, updated, "an up to date file must not be written")
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(past), "the modification time of an up to date file must be kept")
+
+	updated, err = updateFile(filename, "world")
+	require.NoError(t, err)
+	assert.True(t, updated, "a changed file must be written")
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(content))
+}
+
 func TestReadFile(t *testing.T) {
 	tests := []struct {
 		name        string
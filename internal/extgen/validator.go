@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"maps"
 	"regexp"
 	"slices"
 	"strings"
@@ -15,7 +16,7 @@ func scalarTypes() []phpType {
 }
 
 func paramTypes() []phpType {
-	return []phpType{phpString, phpInt, phpFloat, phpBool, phpArray, phpObject, phpMixed}
+	return []phpType{phpString, phpInt, phpFloat, phpBool, phpArray, phpObject, phpMixed, phpCallable}
 }
 
 func returnTypes() []phpType {
@@ -48,6 +49,14 @@ func (v *Validator) validateFunction(fn phpFunction) error {
 		}
 	}
 
+	if fn.ReturnEnum != nil {
+		if fn.IsReturnNullable {
+			return fmt.Errorf("return type: nullable enums are not supported")
+		}
+
+		return nil
+	}
+
 	if err := v.validateReturnType(fn.ReturnType); err != nil {
 		return fmt.Errorf("return type: %w", err)
 	}
@@ -55,6 +64,54 @@ func (v *Validator) validateFunction(fn phpFunction) error {
 	return nil
 }
 
+// validateHook checks that a lifecycle hook is a Go function without parameters, optionally returning an error,
+// and returns its name
+func (v *Validator) validateHook(hook phpHook) (string, error) {
+	goFunc, err := v.parseGoFunction(hook.GoFunction)
+	if err != nil {
+		return "", err
+	}
+
+	if goFunc.Recv != nil {
+		return "", fmt.Errorf("%s must be a function, not a method", goFunc.Name.Name)
+	}
+
+	if goFunc.Type.Params != nil && len(goFunc.Type.Params.List) > 0 {
+		return "", fmt.Errorf("%s must not have parameters", goFunc.Name.Name)
+	}
+
+	if results := goFunc.Type.Results.NumFields(); results > 1 || results == 1 && !hook.ReturnsError {
+		return "", fmt.Errorf("%s must return nothing or an error", goFunc.Name.Name)
+	}
+
+	return goFunc.Name.Name, nil
+}
+
+func (v *Validator) validateEnum(enum phpEnum) error {
+	if !classNameRegex.MatchString(enum.Name) {
+		return fmt.Errorf("invalid enum name: %s", enum.Name)
+	}
+
+	if enum.BackingType != phpString && enum.BackingType != phpInt {
+		return fmt.Errorf("the Go type %s must be defined from a string or integer type", enum.GoType)
+	}
+
+	if len(enum.Cases) == 0 {
+		return fmt.Errorf("no constant of type %s found", enum.GoType)
+	}
+
+	values := make(map[string]string, len(enum.Cases))
+	for _, enumCase := range enum.Cases {
+		if other, ok := values[enumCase.Value]; ok {
+			return fmt.Errorf("cases %s and %s have the same value", other, enumCase.Name)
+		}
+
+		values[enumCase.Value] = enumCase.Name
+	}
+
+	return nil
+}
+
 func (v *Validator) validateParameter(param phpParameter) error {
 	if param.Name == "" {
 		return fmt.Errorf("parameter name cannot be empty")
@@ -64,6 +121,18 @@ func (v *Validator) validateParameter(param phpParameter) error {
 		return fmt.Errorf("invalid parameter name: %s", param.Name)
 	}
 
+	if param.Enum != nil {
+		if param.IsNullable {
+			return fmt.Errorf("nullable enums are not supported")
+		}
+
+		if param.HasDefault {
+			return fmt.Errorf("default values of enum parameters are not supported")
+		}
+
+		return nil
+	}
+
 	validTypes := paramTypes()
 	if !v.isValidPHPType(param.PhpType, validTypes) {
 		return fmt.Errorf("invalid parameter type: %s", param.PhpType)
@@ -95,6 +164,35 @@ func (v *Validator) validateClass(class phpClass) error {
 		}
 	}
 
+	for _, name := range class.Implements {
+		if err := v.validateInterface(class, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateInterface checks that the class declares the methods required by the interface
+func (v *Validator) validateInterface(class phpClass, name string) error {
+	iface, ok := phpInterfaces[name]
+	if !ok {
+		return fmt.Errorf("unsupported interface %s, supported interfaces are: %s", name, strings.Join(slices.Sorted(maps.Keys(phpInterfaces)), ", "))
+	}
+
+	for _, methodName := range slices.Sorted(maps.Keys(iface.Methods)) {
+		i := slices.IndexFunc(class.Methods, func(method phpClassMethod) bool {
+			return method.PhpName == methodName && !method.IsStatic
+		})
+		if i == -1 {
+			return fmt.Errorf("class %s implements %s but doesn't declare the %s() method", class.Name, name, methodName)
+		}
+
+		if returnType := iface.Methods[methodName]; class.Methods[i].ReturnType != returnType {
+			return fmt.Errorf("method %s::%s() must return %s to implement %s, found %s", class.Name, methodName, returnType, name, class.Methods[i].ReturnType)
+		}
+	}
+
 	return nil
 }
 
@@ -112,6 +210,22 @@ func (v *Validator) validateClassProperty(prop phpClassProperty) error {
 		return fmt.Errorf("invalid property type: %s", prop.PhpType)
 	}
 
+	if prop.PhpName == "" {
+		return nil
+	}
+
+	if !propNameRegex.MatchString(prop.PhpName) {
+		return fmt.Errorf("invalid PHP property name: %s", prop.PhpName)
+	}
+
+	if !v.isScalarPHPType(prop.PhpType, scalarTypes()) {
+		return fmt.Errorf("property of type %s can't be exposed to PHP", prop.PhpType)
+	}
+
+	if !slices.Contains([]string{"public", "protected", "private"}, prop.Visibility) {
+		return fmt.Errorf("invalid property visibility: %s", prop.Visibility)
+	}
+
 	return nil
 }
 
@@ -124,18 +238,43 @@ func (v *Validator) validateScalarTypes(fn phpFunction) error {
 	supportedTypes := scalarTypes()
 
 	for i, param := range fn.Params {
+		// callables can only be used as parameters
+		if param.PhpType == phpCallable || param.Enum != nil {
+			continue
+		}
+
 		if !v.isScalarPHPType(param.PhpType, supportedTypes) {
-			return fmt.Errorf("parameter %d (%s) has unsupported type '%s'. Only scalar types (string, int, float, bool, array) and their nullable variants are supported", i+1, param.Name, param.PhpType)
+			return fmt.Errorf("parameter %d (%s) has unsupported type '%s'. Only scalar types (string, int, float, bool, array), callable and their nullable variants are supported", i+1, param.Name, param.PhpType)
 		}
 	}
 
-	if fn.ReturnType != phpVoid && !v.isScalarPHPType(fn.ReturnType, supportedTypes) {
+	if fn.ReturnType != phpVoid && fn.ReturnEnum == nil && !v.isScalarPHPType(fn.ReturnType, supportedTypes) {
 		return fmt.Errorf("return type '%s' is not supported. Only scalar types (string, int, float, bool, array), void, and their nullable variants are supported", fn.ReturnType)
 	}
 
 	return nil
 }
 
+// validateMethodTypes checks if a PHP method signature contains only supported types,
+// methods also support mixed values and returning an Iterator, required to implement ArrayAccess and IteratorAggregate
+func (v *Validator) validateMethodTypes(fn phpFunction) error {
+	for i, param := range fn.Params {
+		if param.PhpType == phpMixed && param.IsNullable {
+			return fmt.Errorf("parameter %d (%s) can't be nullable: mixed already includes null", i+1, param.Name)
+		}
+	}
+
+	scalarFn := fn
+	scalarFn.Params = slices.DeleteFunc(slices.Clone(fn.Params), func(param phpParameter) bool {
+		return param.PhpType == phpMixed
+	})
+	if fn.ReturnType == phpMixed || fn.ReturnType == phpIterator {
+		scalarFn.ReturnType = phpVoid
+	}
+
+	return v.validateScalarTypes(scalarFn)
+}
+
 func (v *Validator) isScalarPHPType(phpType phpType, supportedTypes []phpType) bool {
 	return slices.Contains(supportedTypes, phpType)
 }
@@ -146,24 +285,79 @@ func (v *Validator) validateGoFunctionSignatureWithOptions(phpFunc phpFunction,
 		return fmt.Errorf("no Go function found for PHP function '%s'", phpFunc.Name)
 	}
 
+	goFunc, err := v.parseGoFunction(phpFunc.GoFunction)
+	if err != nil {
+		return err
+	}
+
+	if err := v.validateGoParameters(phpFunc, goFunc, isMethod); err != nil {
+		return err
+	}
+
+	results := goFunc.Type.Results
+	if phpFunc.ReturnsError && results != nil && len(results.List) > 0 {
+		// the error is thrown as an exception, it isn't part of the PHP return type
+		results = &ast.FieldList{List: results.List[:len(results.List)-1]}
+	}
+
+	expectedGoReturnType := v.phpReturnTypeToGoType(phpFunc.ReturnType)
+	if phpFunc.ReturnEnum != nil {
+		expectedGoReturnType = phpFunc.ReturnEnum.GoType
+	}
+	actualGoReturnType := v.goReturnTypeToString(results)
+
+	if !v.isCompatibleGoType(expectedGoReturnType, actualGoReturnType) {
+		return fmt.Errorf("return type mismatch: PHP '%s' requires Go return type '%s' but found '%s'", phpFunc.ReturnType, expectedGoReturnType, actualGoReturnType)
+	}
+
+	return nil
+}
+
+// validateConstructor checks that the Go function mapped to the constructor of a class
+// takes the arguments of the constructor and returns a pointer to the struct of the class
+func (v *Validator) validateConstructor(class phpClass, constructor phpClassMethod) error {
+	if constructor.GoFunction == "" {
+		return fmt.Errorf("no Go function found for the constructor of class '%s'", class.Name)
+	}
+
+	goFunc, err := v.parseGoFunction(constructor.GoFunction)
+	if err != nil {
+		return err
+	}
+
+	if goFunc.Recv != nil {
+		return fmt.Errorf("the constructor must be mapped to a function, not to a method")
+	}
+
+	if err := v.validateGoParameters(phpFunction{Name: constructor.Name, Params: constructor.Params}, goFunc, false); err != nil {
+		return err
+	}
+
+	expectedGoReturnType := "*" + class.GoStruct
+	if actualGoReturnType := v.goReturnTypeToString(goFunc.Type.Results); actualGoReturnType != expectedGoReturnType {
+		return fmt.Errorf("return type mismatch: the constructor requires Go return type '%s' but found '%s'", expectedGoReturnType, actualGoReturnType)
+	}
+
+	return nil
+}
+
+func (v *Validator) parseGoFunction(goFunction string) (*ast.FuncDecl, error) {
 	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", "package main\n"+phpFunc.GoFunction, 0)
+	file, err := parser.ParseFile(fset, "", "package main\n"+goFunction, 0)
 	if err != nil {
-		return fmt.Errorf("failed to parse Go function: %w", err)
+		return nil, fmt.Errorf("failed to parse Go function: %w", err)
 	}
 
-	var goFunc *ast.FuncDecl
 	for _, decl := range file.Decls {
 		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-			goFunc = funcDecl
-			break
+			return funcDecl, nil
 		}
 	}
 
-	if goFunc == nil {
-		return fmt.Errorf("no function declaration found in Go function")
-	}
+	return nil, fmt.Errorf("no function declaration found in Go function")
+}
 
+func (v *Validator) validateGoParameters(phpFunc phpFunction, goFunc *ast.FuncDecl, isMethod bool) error {
 	goParamCount := 0
 	if goFunc.Type.Params != nil {
 		goParamCount = len(goFunc.Type.Params.List)
@@ -196,6 +390,9 @@ func (v *Validator) validateGoFunctionSignatureWithOptions(phpFunc phpFunction,
 
 			goParam := goFunc.Type.Params.List[goParamIndex]
 			expectedGoType := v.phpTypeToGoType(phpParam.PhpType, phpParam.IsNullable)
+			if phpParam.Enum != nil {
+				expectedGoType = phpParam.Enum.GoType
+			}
 			actualGoType := v.goTypeToString(goParam.Type)
 
 			if !v.isCompatibleGoType(expectedGoType, actualGoType) {
@@ -204,13 +401,6 @@ func (v *Validator) validateGoFunctionSignatureWithOptions(phpFunc phpFunction,
 		}
 	}
 
-	expectedGoReturnType := v.phpReturnTypeToGoType(phpFunc.ReturnType)
-	actualGoReturnType := v.goReturnTypeToString(goFunc.Type.Results)
-
-	if !v.isCompatibleGoType(expectedGoReturnType, actualGoReturnType) {
-		return fmt.Errorf("return type mismatch: PHP '%s' requires Go return type '%s' but found '%s'", phpFunc.ReturnType, expectedGoReturnType, actualGoReturnType)
-	}
-
 	return nil
 }
 
@@ -225,13 +415,13 @@ func (v *Validator) phpTypeToGoType(t phpType, isNullable bool) string {
 		baseType = "float64"
 	case phpBool:
 		baseType = "bool"
-	case phpArray:
+	case phpArray, phpCallable, phpMixed:
 		baseType = "*C.zval"
 	default:
 		baseType = "interface{}"
 	}
 
-	if isNullable && t != phpString && t != phpArray {
+	if isNullable && t != phpString && t != phpArray && t != phpCallable && t != phpMixed {
 		return "*" + baseType
 	}
 
@@ -251,6 +441,8 @@ func (v *Validator) isCompatibleGoType(expectedType, actualType string) bool {
 		return actualType == "*int"
 	case "*float64":
 		return actualType == "*float32"
+	case "interface{}":
+		return actualType == "any"
 	}
 
 	return false
@@ -268,7 +460,7 @@ func (v *Validator) phpReturnTypeToGoType(phpReturnType phpType) string {
 		return "float64"
 	case phpBool:
 		return "bool"
-	case phpArray:
+	case phpArray, phpIterator:
 		return "unsafe.Pointer"
 	default:
 		return "interface{}"
@@ -283,6 +475,12 @@ func (v *Validator) goTypeToString(expr ast.Expr) string {
 		return "*" + v.goTypeToString(t.X)
 	case *ast.SelectorExpr:
 		return v.goTypeToString(t.X) + "." + t.Sel.Name
+	case *ast.InterfaceType:
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return "interface{}"
+		}
+
+		return "unknown"
 	default:
 		return "unknown"
 	}
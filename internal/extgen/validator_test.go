@@ -238,6 +238,49 @@ func TestValidateClassProperty(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "valid exposed property",
+			prop: phpClassProperty{
+				Name:       "ID",
+				PhpType:    phpInt,
+				GoType:     "int64",
+				PhpName:    "id",
+				Visibility: "protected",
+				IsReadonly: true,
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid exposed property name",
+			prop: phpClassProperty{
+				Name:       "ID",
+				PhpType:    phpInt,
+				PhpName:    "1d",
+				Visibility: "public",
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid exposed property visibility",
+			prop: phpClassProperty{
+				Name:       "ID",
+				PhpType:    phpInt,
+				PhpName:    "id",
+				Visibility: "internal",
+			},
+			expectError: true,
+		},
+		{
+			name: "exposed property of unsupported type",
+			prop: phpClassProperty{
+				Name:       "Owner",
+				PhpType:    phpMixed,
+				GoType:     "*User",
+				PhpName:    "owner",
+				Visibility: "public",
+			},
+			expectError: true,
+		},
 	}
 
 	validator := Validator{}
@@ -401,6 +444,39 @@ func TestValidateClass(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "class implementing an interface",
+			class: phpClass{
+				Name:       "Bag",
+				GoStruct:   "BagStruct",
+				Implements: []string{"Countable"},
+				Methods: []phpClassMethod{
+					{Name: "count", PhpName: "count", ReturnType: phpInt},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "interface method declared as static",
+			class: phpClass{
+				Name:       "Bag",
+				GoStruct:   "BagStruct",
+				Implements: []string{"Countable"},
+				Methods: []phpClassMethod{
+					{Name: "count", PhpName: "count", ReturnType: phpInt, IsStatic: true},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "unsupported interface",
+			class: phpClass{
+				Name:       "Bag",
+				GoStruct:   "BagStruct",
+				Implements: []string{"Traversable"},
+			},
+			expectError: true,
+		},
 	}
 
 	validator := Validator{}
@@ -484,6 +560,19 @@ func TestValidateScalarTypes(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "valid callable parameters",
+			function: phpFunction{
+				Name:       "callableFunction",
+				ReturnType: phpArray,
+				Params: []phpParameter{
+					{Name: "items", PhpType: phpArray},
+					{Name: "callback", PhpType: phpCallable},
+					{Name: "onError", PhpType: phpCallable, IsNullable: true},
+				},
+			},
+			expectError: false,
+		},
 		{
 			name: "invalid object parameter",
 			function: phpFunction{
@@ -583,6 +672,52 @@ func TestValidateGoFunctionSignature(t *testing.T) {
 				},
 				GoFunction: `func voidFunc(message *C.zend_string) {
 	// Do something
+}`,
+			},
+			expectError: false,
+		},
+		{
+			name: "valid function returning an error",
+			phpFunc: phpFunction{
+				Name:         "divide",
+				ReturnType:   phpInt,
+				ReturnsError: true,
+				Params: []phpParameter{
+					{Name: "a", PhpType: phpInt},
+					{Name: "b", PhpType: phpInt},
+				},
+				GoFunction: `func divide(a int64, b int64) (int64, error) {
+	return a / b, nil
+}`,
+			},
+			expectError: false,
+		},
+		{
+			name: "error return without the flag",
+			phpFunc: phpFunction{
+				Name:       "divide",
+				ReturnType: phpInt,
+				Params: []phpParameter{
+					{Name: "a", PhpType: phpInt},
+				},
+				GoFunction: `func divide(a int64) (int64, error) {
+	return a, nil
+}`,
+			},
+			expectError: true,
+			errorMsg:    "return type mismatch",
+		},
+		{
+			name: "valid callable parameter",
+			phpFunc: phpFunction{
+				Name:       "mapFunc",
+				ReturnType: phpArray,
+				Params: []phpParameter{
+					{Name: "items", PhpType: phpArray},
+					{Name: "callback", PhpType: phpCallable},
+				},
+				GoFunction: `func mapFunc(items *C.zval, callback *C.zval) unsafe.Pointer {
+	return nil
 }`,
 			},
 			expectError: false,
@@ -751,6 +886,8 @@ func TestPhpTypeToGoType(t *testing.T) {
 		{"bool", true, "*bool"},
 		{"array", false, "*C.zval"},
 		{"array", true, "*C.zval"},
+		{"callable", false, "*C.zval"},
+		{"callable", true, "*C.zval"},
 		{"unknown", false, "interface{}"},
 	}
 
@@ -791,3 +928,69 @@ func TestPhpReturnTypeToGoType(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConstructor(t *testing.T) {
+	class := phpClass{Name: "Point", GoStruct: "PointStruct"}
+
+	tests := []struct {
+		name        string
+		constructor phpClassMethod
+		expectError bool
+	}{
+		{
+			name: "valid constructor",
+			constructor: phpClassMethod{
+				Params:     []phpParameter{{Name: "x", PhpType: phpInt}, {Name: "label", PhpType: phpString, IsNullable: true}},
+				GoFunction: "func NewPoint(x int64, label *C.zend_string) *PointStruct {\n\treturn nil\n}",
+			},
+			expectError: false,
+		},
+		{
+			name:        "missing Go function",
+			constructor: phpClassMethod{},
+			expectError: true,
+		},
+		{
+			name: "returns the struct by value",
+			constructor: phpClassMethod{
+				GoFunction: "func NewPoint() PointStruct {\n\treturn PointStruct{}\n}",
+			},
+			expectError: true,
+		},
+		{
+			name: "returns another struct",
+			constructor: phpClassMethod{
+				GoFunction: "func NewPoint() *OtherStruct {\n\treturn nil\n}",
+			},
+			expectError: true,
+		},
+		{
+			name: "parameters mismatch",
+			constructor: phpClassMethod{
+				Params:     []phpParameter{{Name: "x", PhpType: phpInt}},
+				GoFunction: "func NewPoint(x float64) *PointStruct {\n\treturn nil\n}",
+			},
+			expectError: true,
+		},
+		{
+			name: "mapped to a method",
+			constructor: phpClassMethod{
+				GoFunction: "func (p *PointStruct) Init() *PointStruct {\n\treturn p\n}",
+			},
+			expectError: true,
+		},
+	}
+
+	validator := Validator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.validateConstructor(class, tt.constructor)
+
+			if tt.expectError {
+				assert.Error(t, err, "validateConstructor() should return an error")
+			} else {
+				assert.NoError(t, err, "validateConstructor() should not return an error")
+			}
+		})
+	}
+}
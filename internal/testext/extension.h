@@ -5,5 +5,6 @@
 
 extern zend_module_entry module1_entry;
 extern zend_module_entry module2_entry;
+extern zend_module_entry types_module_entry;
 
 #endif
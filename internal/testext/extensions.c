@@ -1,4 +1,5 @@
 #include <php.h>
+#include <stdlib.h>
 #include <zend_exceptions.h>
 
 #include "_cgo_export.h"
@@ -24,3 +25,158 @@ zend_module_entry module2_entry = {STANDARD_MODULE_HEADER,
                                    NULL, /* MINFO */
                                    "0.1.0",
                                    STANDARD_MODULE_PROPERTIES};
+
+/* throws the error returned by a Go function */
+static void throw_go_error(char *error) {
+  if (error == NULL) {
+    return;
+  }
+
+  zend_throw_exception(NULL, error, 0);
+  free(error);
+}
+
+ZEND_BEGIN_ARG_INFO_EX(arginfo_testext_translate_point, 0, 0, 2)
+ZEND_ARG_INFO(0, point)
+ZEND_ARG_INFO(0, dx)
+ZEND_END_ARG_INFO()
+
+PHP_FUNCTION(testext_translate_point) {
+  zval *point;
+  zend_long dx;
+
+  ZEND_PARSE_PARAMETERS_START(2, 2)
+  Z_PARAM_ZVAL(point)
+  Z_PARAM_LONG(dx)
+  ZEND_PARSE_PARAMETERS_END();
+
+  throw_go_error(go_translate_point(point, dx, return_value));
+}
+
+ZEND_BEGIN_ARG_INFO_EX(arginfo_testext_describe_order, 0, 0, 1)
+ZEND_ARG_INFO(0, value)
+ZEND_END_ARG_INFO()
+
+PHP_FUNCTION(testext_describe_order) {
+  zval *value;
+
+  ZEND_PARSE_PARAMETERS_START(1, 1)
+  Z_PARAM_ZVAL(value)
+  ZEND_PARSE_PARAMETERS_END();
+
+  throw_go_error(go_describe_order(value, return_value));
+}
+
+ZEND_BEGIN_ARG_INFO_EX(arginfo_testext_date, 0, 0, 1)
+ZEND_ARG_INFO(0, timestamp)
+ZEND_END_ARG_INFO()
+
+PHP_FUNCTION(testext_date) {
+  zend_long timestamp;
+
+  ZEND_PARSE_PARAMETERS_START(1, 1)
+  Z_PARAM_LONG(timestamp)
+  ZEND_PARSE_PARAMETERS_END();
+
+  throw_go_error(go_date(timestamp, return_value));
+}
+
+ZEND_BEGIN_ARG_INFO_EX(arginfo_testext_map, 0, 0, 2)
+ZEND_ARG_INFO(0, items)
+ZEND_ARG_TYPE_INFO(0, callback, IS_CALLABLE, 0)
+ZEND_END_ARG_INFO()
+
+PHP_FUNCTION(testext_map) {
+  zval *items;
+  zend_fcall_info callback_fci = empty_fcall_info;
+  zend_fcall_info_cache callback_fcc = empty_fcall_info_cache;
+
+  ZEND_PARSE_PARAMETERS_START(2, 2)
+  Z_PARAM_ARRAY(items)
+  Z_PARAM_FUNC(callback_fci, callback_fcc)
+  ZEND_PARSE_PARAMETERS_END();
+
+  throw_go_error(go_map(items, &callback_fci.function_name, return_value));
+}
+
+ZEND_BEGIN_ARG_INFO_EX(arginfo_testext_call_both, 0, 0, 2)
+ZEND_ARG_TYPE_INFO(0, first, IS_CALLABLE, 0)
+ZEND_ARG_TYPE_INFO(0, second, IS_CALLABLE, 0)
+ZEND_END_ARG_INFO()
+
+PHP_FUNCTION(testext_call_both) {
+  zend_fcall_info first_fci = empty_fcall_info;
+  zend_fcall_info_cache first_fcc = empty_fcall_info_cache;
+  zend_fcall_info second_fci = empty_fcall_info;
+  zend_fcall_info_cache second_fcc = empty_fcall_info_cache;
+
+  ZEND_PARSE_PARAMETERS_START(2, 2)
+  Z_PARAM_FUNC(first_fci, first_fcc)
+  Z_PARAM_FUNC(second_fci, second_fcc)
+  ZEND_PARSE_PARAMETERS_END();
+
+  throw_go_error(go_call_both(&first_fci.function_name,
+                              &second_fci.function_name, return_value));
+}
+
+ZEND_BEGIN_ARG_INFO_EX(arginfo_testext_reverse, 0, 0, 1)
+ZEND_ARG_INFO(0, value)
+ZEND_END_ARG_INFO()
+
+PHP_FUNCTION(testext_reverse) {
+  zend_string *value;
+
+  ZEND_PARSE_PARAMETERS_START(1, 1)
+  Z_PARAM_STR(value)
+  ZEND_PARSE_PARAMETERS_END();
+
+  zend_string *result = go_reverse(value);
+  if (result == NULL) {
+    RETURN_EMPTY_STRING();
+  }
+
+  RETURN_STR(result);
+}
+
+ZEND_BEGIN_ARG_INFO_EX(arginfo_testext_intern, 0, 0, 1)
+ZEND_ARG_INFO(0, value)
+ZEND_END_ARG_INFO()
+
+PHP_FUNCTION(testext_intern) {
+  zend_string *value;
+
+  ZEND_PARSE_PARAMETERS_START(1, 1)
+  Z_PARAM_STR(value)
+  ZEND_PARSE_PARAMETERS_END();
+
+  zend_string *result = go_intern(value);
+  if (result == NULL) {
+    RETURN_EMPTY_STRING();
+  }
+
+  RETURN_INTERNED_STR(result);
+}
+
+// clang-format off
+static const zend_function_entry types_functions[] = {
+  PHP_FE(testext_translate_point, arginfo_testext_translate_point)
+  PHP_FE(testext_describe_order, arginfo_testext_describe_order)
+  PHP_FE(testext_date, arginfo_testext_date)
+  PHP_FE(testext_map, arginfo_testext_map)
+  PHP_FE(testext_call_both, arginfo_testext_call_both)
+  PHP_FE(testext_reverse, arginfo_testext_reverse)
+  PHP_FE(testext_intern, arginfo_testext_intern)
+  PHP_FE_END
+};
+// clang-format on
+
+zend_module_entry types_module_entry = {STANDARD_MODULE_HEADER,
+                                        "testext_types",
+                                        types_functions, /* Functions */
+                                        NULL,            /* MINIT */
+                                        NULL,            /* MSHUTDOWN */
+                                        NULL,            /* RINIT */
+                                        NULL,            /* RSHUTDOWN */
+                                        NULL,            /* MINFO */
+                                        "0.1.0",
+                                        STANDARD_MODULE_PROPERTIES};
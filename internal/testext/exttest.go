@@ -23,22 +23,66 @@ import (
 func testRegisterExtension(t *testing.T) {
 	frankenphp.RegisterExtension(unsafe.Pointer(&C.module1_entry))
 	frankenphp.RegisterExtension(unsafe.Pointer(&C.module2_entry))
+	frankenphp.RegisterExtension(unsafe.Pointer(&C.types_module_entry))
+	frankenphp.RegisterPHPClass("Point", point{})
 
+	// extensions are registered only once per process
 	err := frankenphp.Init()
 	require.Nil(t, err)
 	defer frankenphp.Shutdown()
 
-	req := httptest.NewRequest("GET", "http://example.com/index.php", nil)
+	body := serve(t, "index.php")
+	assert.Contains(t, body, "ext1")
+	assert.Contains(t, body, "ext2")
+
+	t.Run("types", func(t *testing.T) {
+		assert.Equal(t, `Point 11 2 NULL
+Point 4 4
+DateTimeImmutable 2023-11-14T22:13:20+00:00
+S 10 EUR 4,4 2025-03-14T15:09:26.535897+01:00
+cannot convert PHP string to Go int64 at "x"
+cannot convert recursive PHP object at "self"
+PHP exception: invalid price at "price"
+A0,B1
+0,4
+10,20
+PHP exception: failure in the callback
+olleH
+KEY interned
+`, serve(t, "types.php"))
+	})
+
+	t.Run("fatal error in a callable", func(t *testing.T) {
+		body := serve(t, "fatal-callback.php")
+
+		assert.Contains(t, body, "before")
+		assert.Contains(t, body, "Allowed memory size")
+		assert.NotContains(t, body, "caught")
+		assert.NotContains(t, body, "after")
+	})
+
+	t.Run("callable called after a fatal error", func(t *testing.T) {
+		body := serve(t, "fatal-callbacks.php")
+
+		assert.Contains(t, body, "first")
+		assert.Contains(t, body, "Allowed memory size")
+		assert.NotContains(t, body, "second")
+		assert.NotContains(t, body, "caught")
+		assert.NotContains(t, body, "after")
+	})
+}
+
+func serve(t *testing.T, script string) string {
+	req := httptest.NewRequest("GET", "http://example.com/"+script, nil)
 	w := httptest.NewRecorder()
 
-	req, err = frankenphp.NewRequestWithContext(req, frankenphp.WithRequestDocumentRoot("./testdata", false))
+	req, err := frankenphp.NewRequestWithContext(req, frankenphp.WithRequestDocumentRoot("./testdata", false))
 	assert.NoError(t, err)
 
 	err = frankenphp.ServeHTTP(w, req)
 	assert.NoError(t, err)
 
-	resp := w.Result()
-	body, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(body), "ext1")
-	assert.Contains(t, string(body), "ext2")
+	body, _ := io.ReadAll(w.Result().Body)
+
+	return string(body)
 }
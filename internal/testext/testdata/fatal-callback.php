@@ -0,0 +1,14 @@
+<?php
+
+ini_set('memory_limit', '8M');
+
+try {
+    testext_map([1], function (): void {
+        echo "before\n";
+        str_repeat('x', 16 * 1024 * 1024);
+    });
+} catch (Throwable $e) {
+    echo "caught\n";
+}
+
+echo "after\n";
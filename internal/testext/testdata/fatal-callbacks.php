@@ -0,0 +1,16 @@
+<?php
+
+ini_set('memory_limit', '8M');
+
+try {
+    testext_call_both(function (): void {
+        echo "first\n";
+        str_repeat('x', 16 * 1024 * 1024);
+    }, function (): void {
+        echo "second\n";
+    });
+} catch (Throwable $e) {
+    echo "caught\n";
+}
+
+echo "after\n";
@@ -0,0 +1,89 @@
+<?php
+
+final class Point
+{
+    public int $x = 0;
+    public int $y = 0;
+    public ?string $label = null;
+}
+
+enum Suit: string
+{
+    case Hearts = 'H';
+    case Spades = 'S';
+}
+
+final class Money implements JsonSerializable
+{
+    public function __construct(private int $amount, private string $currency)
+    {
+    }
+
+    public function jsonSerialize(): mixed
+    {
+        return $this->amount . ' ' . $this->currency;
+    }
+}
+
+$point = testext_translate_point(['x' => 1, 'y' => 2], 10);
+echo get_class($point), ' ', $point->x, ' ', $point->y, ' ', var_export($point->label, true), "\n";
+
+$object = new stdClass();
+$object->x = 3;
+$object->y = 4;
+$point = testext_translate_point($object, 1);
+echo get_class($point), ' ', $point->x, ' ', $point->y, "\n";
+
+$date = testext_date(1700000000);
+echo get_class($date), ' ', $date->format(DATE_ATOM), "\n";
+
+echo testext_describe_order([
+    'suit' => Suit::Spades,
+    'price' => new Money(10, 'EUR'),
+    'point' => $point,
+    'date' => new DateTimeImmutable('2025-03-14T15:09:26.535897+01:00'),
+]), "\n";
+
+try {
+    testext_translate_point(['x' => 'foo'], 1);
+} catch (Exception $e) {
+    echo $e->getMessage(), "\n";
+}
+
+$object->self = $object;
+try {
+    testext_translate_point($object, 1);
+} catch (Exception $e) {
+    echo $e->getMessage(), "\n";
+}
+
+try {
+    testext_describe_order(['price' => new class implements JsonSerializable {
+        public function jsonSerialize(): mixed
+        {
+            throw new LogicException('invalid price');
+        }
+    }]);
+} catch (Exception $e) {
+    echo $e->getMessage(), "\n";
+}
+
+echo implode(',', testext_map(['a', 'b'], fn (string $value, int $i): string => strtoupper($value) . $i)), "\n";
+echo implode(',', testext_map([-2, 4], 'max')), "\n";
+echo implode(',', testext_map([1, 2], new class {
+    public function __invoke(int $value): int
+    {
+        return $value * 10;
+    }
+})), "\n";
+
+try {
+    testext_map([1], function (): never {
+        throw new LogicException('failure in the callback');
+    });
+} catch (Exception $e) {
+    echo $e->getMessage(), "\n";
+}
+
+echo testext_reverse('Hello'), testext_reverse(''), "\n";
+echo testext_intern('key'), ' ', testext_intern('key') === 'KEY' ? 'interned' : 'invalid', "\n";
@@ -0,0 +1,126 @@
+package testext
+
+// #include <stdlib.h>
+// #include "extension.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/dunglas/frankenphp"
+)
+
+type point struct {
+	X     int64  `php:"x"`
+	Y     int64  `php:"y"`
+	Label string `php:"label,omitempty"`
+}
+
+// setResult converts value to a zval stored in result, or returns the error as a C string
+func setResult(value any, err error, result *C.zval) *C.char {
+	if err != nil {
+		return C.CString(err.Error())
+	}
+
+	zval, err := frankenphp.PHPValue(value)
+	if err != nil {
+		return C.CString(err.Error())
+	}
+
+	*result = *(*C.zval)(zval)
+
+	return nil
+}
+
+//export go_translate_point
+func go_translate_point(value *C.zval, dx C.zend_long, result *C.zval) *C.char {
+	p, err := frankenphp.GoValue[point](unsafe.Pointer(value))
+	p.X += int64(dx)
+
+	return setResult(p, err, result)
+}
+
+type order struct {
+	Suit  string    `php:"suit"`
+	Price string    `php:"price"`
+	Point point     `php:"point"`
+	Date  time.Time `php:"date"`
+}
+
+//export go_describe_order
+func go_describe_order(value *C.zval, result *C.zval) *C.char {
+	o, err := frankenphp.GoValue[order](unsafe.Pointer(value))
+	description := fmt.Sprintf("%s %s %d,%d %s", o.Suit, o.Price, o.Point.X, o.Point.Y, o.Date.Format(time.RFC3339Nano))
+
+	return setResult(description, err, result)
+}
+
+//export go_date
+func go_date(timestamp C.zend_long, result *C.zval) *C.char {
+	return setResult(time.Unix(int64(timestamp), 0).UTC(), nil, result)
+}
+
+//export go_map
+func go_map(items *C.zval, callback *C.zval, result *C.zval) *C.char {
+	values, err := frankenphp.GoValue[[]any](unsafe.Pointer(items))
+	if err != nil {
+		return setResult(nil, err, result)
+	}
+
+	c, err := frankenphp.NewPHPCallable(unsafe.Pointer(callback))
+	if err != nil {
+		return setResult(nil, err, result)
+	}
+	defer c.Release()
+
+	mapped := make([]any, 0, len(values))
+	for i, value := range values {
+		v, err := c.Call(value, i)
+		if err != nil {
+			return setResult(nil, err, result)
+		}
+
+		mapped = append(mapped, v)
+	}
+
+	return setResult(mapped, nil, result)
+}
+
+//export go_call_both
+func go_call_both(first *C.zval, second *C.zval, result *C.zval) *C.char {
+	var errs []error
+	for _, callback := range []*C.zval{first, second} {
+		c, err := frankenphp.NewPHPCallable(unsafe.Pointer(callback))
+		if err != nil {
+			return setResult(nil, err, result)
+		}
+
+		// the second callable is called even if the first one failed
+		_, err = c.Call()
+		c.Release()
+
+		errs = append(errs, err)
+	}
+
+	return setResult(nil, errors.Join(errs...), result)
+}
+
+//export go_reverse
+func go_reverse(value *C.zend_string) *C.zend_string {
+	view := frankenphp.UnsafeBytesView(unsafe.Pointer(value))
+
+	reversed := make([]byte, len(view))
+	for i, b := range view {
+		reversed[len(view)-1-i] = b
+	}
+
+	return (*C.zend_string)(frankenphp.PHPBytes(reversed, false))
+}
+
+//export go_intern
+func go_intern(value *C.zend_string) *C.zend_string {
+	return (*C.zend_string)(frankenphp.PHPInternedString([]byte(strings.ToUpper(frankenphp.UnsafeStringView(unsafe.Pointer(value))))))
+}
@@ -0,0 +1,171 @@
+package frankenphp
+
+// #include "frankenphp.h"
+import "C"
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// maximum time to wait for a busy thread to answer a snapshot request
+// threads blocked in I/O will only answer once control returns to the VM
+const snapshotTimeout = 500 * time.Millisecond
+
+var (
+	ErrThreadNotFound         = errors.New("thread not found")
+	ErrThreadIsIdle           = errors.New("thread is not handling a request")
+	ErrThreadNotInterruptible = errors.New("the PHP VM of the thread can't be interrupted, it is starting or stopping")
+	ErrTerminationPending     = errors.New("the script will be terminated as soon as it returns to the PHP VM")
+)
+
+// threadSnapshot contains information that can only be collected on the PHP thread itself
+type threadSnapshot struct {
+	memoryUsage     int64
+	peakMemoryUsage int64
+	stack           string
+}
+
+// interrupt asks the PHP VM running on the thread to call go_frankenphp_on_vm_interrupt
+// must be called with interruptMu locked
+func (thread *phpThread) interrupt() bool {
+	if thread.vmInterrupt == nil {
+		return false
+	}
+
+	C.frankenphp_interrupt_thread(thread.vmInterrupt)
+
+	return true
+}
+
+// requestSnapshot interrupts the script currently running on the thread to collect its memory usage and stack
+func (thread *phpThread) requestSnapshot(withStack bool) (threadSnapshot, bool) {
+	// only one snapshot can be requested at a time per thread
+	thread.snapshotMu.Lock()
+	defer thread.snapshotMu.Unlock()
+
+	ch := make(chan threadSnapshot, 1)
+
+	thread.interruptMu.Lock()
+	thread.snapshotChan = ch
+	thread.snapshotStack = withStack
+	if !thread.interrupt() {
+		thread.snapshotChan = nil
+		thread.interruptMu.Unlock()
+
+		return threadSnapshot{}, false
+	}
+	thread.interruptMu.Unlock()
+
+	select {
+	case s := <-ch:
+		return s, true
+	case <-time.After(snapshotTimeout):
+		thread.interruptMu.Lock()
+		thread.snapshotChan = nil
+		thread.interruptMu.Unlock()
+
+		return threadSnapshot{}, false
+	}
+}
+
+// EXPERIMENTAL: TerminateThreadRequest stops the script handling the current request of a PHP thread.
+//
+// The script bails out like on a timeout, the request is rejected with a 503 status
+// and the thread restarts its worker script or waits for the next request.
+// Threads blocked outside the PHP VM (e.g. on I/O) are only stopped once control returns to the VM,
+// in which case ErrTerminationPending is returned.
+func TerminateThreadRequest(index int) error {
+	if index < 0 || index >= len(phpThreads) {
+		return ErrThreadNotFound
+	}
+
+	thread := phpThreads[index]
+	fc := thread.activeRequest.Load()
+	if fc == nil {
+		return ErrThreadIsIdle
+	}
+
+	thread.interruptMu.Lock()
+	thread.terminateRequest = fc
+	if !thread.interrupt() {
+		thread.terminateRequest = nil
+		thread.interruptMu.Unlock()
+
+		return ErrThreadNotInterruptible
+	}
+	thread.interruptMu.Unlock()
+
+	select {
+	case <-fc.done:
+		return nil
+	case <-time.After(snapshotTimeout):
+		return ErrTerminationPending
+	}
+}
+
+// go_frankenphp_register_vm_interrupt is called when a PHP thread starts and right before it stops
+//
+//export go_frankenphp_register_vm_interrupt
+func go_frankenphp_register_vm_interrupt(threadIndex C.uintptr_t, vmInterrupt *C.zend_atomic_bool) {
+	thread := phpThreads[threadIndex]
+	thread.interruptMu.Lock()
+	thread.vmInterrupt = vmInterrupt
+	thread.interruptMu.Unlock()
+}
+
+// go_frankenphp_on_vm_interrupt is called on the PHP thread after it has been interrupted
+// it returns the actions the thread should take
+//
+//export go_frankenphp_on_vm_interrupt
+func go_frankenphp_on_vm_interrupt(threadIndex C.uintptr_t) C.int {
+	thread := phpThreads[threadIndex]
+	thread.interruptMu.Lock()
+	defer thread.interruptMu.Unlock()
+
+	var actions C.int
+	if fc := thread.terminateRequest; fc != nil {
+		thread.terminateRequest = nil
+
+		// make sure the request that should be terminated is still the one being handled
+		if fc == thread.activeRequest.Load() {
+			fc.logger.LogAttrs(context.Background(), slog.LevelWarn, "terminating request", slog.Int("thread", thread.threadIndex), slog.String("url", fc.request.RequestURI))
+			fc.reject(http.StatusServiceUnavailable, "Service Unavailable")
+			actions |= C.FRANKENPHP_INTERRUPT_TERMINATE
+		}
+	}
+
+	if thread.snapshotChan != nil {
+		actions |= C.FRANKENPHP_INTERRUPT_SNAPSHOT
+		if thread.snapshotStack {
+			actions |= C.FRANKENPHP_INTERRUPT_STACK
+		}
+	}
+
+	return actions
+}
+
+//export go_frankenphp_report_snapshot
+func go_frankenphp_report_snapshot(threadIndex C.uintptr_t, memoryUsage C.size_t, peakMemoryUsage C.size_t, stack *C.char, stackLen C.size_t) {
+	thread := phpThreads[threadIndex]
+	thread.interruptMu.Lock()
+	defer thread.interruptMu.Unlock()
+
+	if thread.snapshotChan == nil {
+		// the requester has given up waiting
+		return
+	}
+
+	s := threadSnapshot{
+		memoryUsage:     int64(memoryUsage),
+		peakMemoryUsage: int64(peakMemoryUsage),
+	}
+	if stack != nil {
+		s.stack = C.GoStringN(stack, C.int(stackLen))
+	}
+
+	thread.snapshotChan <- s
+	thread.snapshotChan = nil
+}
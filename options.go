@@ -19,13 +19,18 @@ type WorkerOption func(*workerOpt) error
 //
 // If you change this, also update the Caddy module and the documentation.
 type opt struct {
-	numThreads  int
-	maxThreads  int
-	workers     []workerOpt
-	logger      *slog.Logger
-	metrics     Metrics
-	phpIni      map[string]string
-	maxWaitTime time.Duration
+	numThreads        int
+	maxThreads        int
+	workers           []workerOpt
+	logger            *slog.Logger
+	metrics           Metrics
+	phpIni            map[string]string
+	maxWaitTime       time.Duration
+	errorReporter     ErrorReporter
+	workerEvents      func(WorkerEvent)
+	maxQueueDepth     int
+	responseFilters   []ResponseFilter
+	responseCacheSize int64
 }
 
 type workerOpt struct {
@@ -141,3 +146,61 @@ func WithMaxWaitTime(maxWaitTime time.Duration) Option {
 		return nil
 	}
 }
+
+// WithErrorReporter configures a reporter receiving the errors and uncaught exceptions raised by PHP scripts.
+func WithErrorReporter(r ErrorReporter) Option {
+	return func(o *opt) error {
+		o.errorReporter = r
+
+		return nil
+	}
+}
+
+// WithWorkerEventHandler configures a function receiving the lifecycle events of worker threads.
+//
+// The handler is called synchronously, often from the PHP thread itself: it must return quickly.
+func WithWorkerEventHandler(handler func(WorkerEvent)) Option {
+	return func(o *opt) error {
+		o.workerEvents = handler
+
+		return nil
+	}
+}
+
+// WithReadinessMaxQueueDepth configures the number of queued requests from which FrankenPHP is reported as not ready.
+//
+// Defaults to the number of PHP threads.
+func WithReadinessMaxQueueDepth(maxQueueDepth int) Option {
+	return func(o *opt) error {
+		if maxQueueDepth < 0 {
+			return fmt.Errorf("readiness max queue depth must be positive, got %d", maxQueueDepth)
+		}
+		o.maxQueueDepth = maxQueueDepth
+
+		return nil
+	}
+}
+
+// WithResponseFilter adds a filter applied to the responses of all requests.
+func WithResponseFilter(filter ResponseFilter) Option {
+	return func(o *opt) error {
+		o.responseFilters = append(o.responseFilters, filter)
+
+		return nil
+	}
+}
+
+// WithResponseCache enables the in-memory cache of the responses generated by PHP scripts, up to the given size in bytes.
+//
+// Responses to GET requests are stored according to their Cache-Control, Vary and Surrogate-Key headers,
+// and are served without using a PHP thread.
+func WithResponseCache(size int64) Option {
+	return func(o *opt) error {
+		if size < 0 {
+			return fmt.Errorf("response cache size must be positive, got %d", size)
+		}
+		o.responseCacheSize = size
+
+		return nil
+	}
+}
@@ -0,0 +1,209 @@
+package frankenphp
+
+// #include "frankenphp.h"
+import "C"
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// PHPError is a structured representation of an error, a warning or an uncaught exception raised by a PHP script.
+type PHPError struct {
+	// Type is the name of the PHP error constant (E_WARNING, E_ERROR...)
+	Type    string
+	Level   int
+	Message string
+	File    string
+	Line    int
+	// Trace is the PHP stack trace, if available
+	Trace string
+	// Exception is the class of the uncaught exception, if any
+	Exception  string
+	RequestURI string
+	Worker     string
+}
+
+// ErrorReporter receives the errors and uncaught exceptions raised by PHP scripts.
+//
+// ReportPHPError is called synchronously on the PHP thread, implementations
+// should hand off any slow work (e.g. network calls) to another goroutine.
+type ErrorReporter interface {
+	ReportPHPError(ctx context.Context, e PHPError)
+}
+
+var errorReporter ErrorReporter
+
+// PHP error levels, see https://www.php.net/manual/en/errorfunc.constants.php
+var phpErrorTypes = map[int]string{
+	1:     "E_ERROR",
+	2:     "E_WARNING",
+	4:     "E_PARSE",
+	8:     "E_NOTICE",
+	16:    "E_CORE_ERROR",
+	32:    "E_CORE_WARNING",
+	64:    "E_COMPILE_ERROR",
+	128:   "E_COMPILE_WARNING",
+	256:   "E_USER_ERROR",
+	512:   "E_USER_WARNING",
+	1024:  "E_USER_NOTICE",
+	2048:  "E_STRICT",
+	4096:  "E_RECOVERABLE_ERROR",
+	8192:  "E_DEPRECATED",
+	16384: "E_USER_DEPRECATED",
+}
+
+// slogLevel maps the PHP error level to a slog level
+func (e PHPError) slogLevel() slog.Level {
+	switch e.Type {
+	case "E_ERROR", "E_PARSE", "E_CORE_ERROR", "E_COMPILE_ERROR", "E_USER_ERROR", "E_RECOVERABLE_ERROR":
+		return slog.LevelError
+	case "E_WARNING", "E_CORE_WARNING", "E_COMPILE_WARNING", "E_USER_WARNING":
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func (e PHPError) attrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("type", e.Type),
+		slog.String("file", e.File),
+		slog.Int("line", e.Line),
+	}
+	if e.Exception != "" {
+		attrs = append(attrs, slog.String("exception", e.Exception))
+	}
+	if e.Trace != "" {
+		attrs = append(attrs, slog.String("trace", e.Trace))
+	}
+	if e.RequestURI != "" {
+		attrs = append(attrs, slog.String("uri", e.RequestURI))
+	}
+	if e.Worker != "" {
+		attrs = append(attrs, slog.String("worker", e.Worker))
+	}
+
+	return attrs
+}
+
+// newPHPError creates a PHPError, uncaught exceptions are parsed from the message
+// built by zend_exception_error(): "Uncaught Class: message in file:line\nStack trace:\n#0..."
+func newPHPError(level int, file string, line int, message string, trace string) PHPError {
+	e := PHPError{
+		Type:    phpErrorTypes[level],
+		Level:   level,
+		Message: message,
+		File:    file,
+		Line:    line,
+		Trace:   trace,
+	}
+	if e.Type == "" {
+		e.Type = "E_UNKNOWN"
+	}
+
+	uncaught, ok := strings.CutPrefix(message, "Uncaught ")
+	if !ok {
+		return e
+	}
+
+	uncaught = strings.TrimSuffix(uncaught, "\n  thrown")
+	uncaught, e.Trace, _ = strings.Cut(uncaught, "\nStack trace:\n")
+	class, msg, ok := strings.Cut(uncaught, ": ")
+	if !ok || strings.ContainsAny(class, " \n") {
+		return e
+	}
+
+	e.Exception = class
+	e.Message = strings.TrimSuffix(msg, " in "+file+":"+strconv.Itoa(line))
+
+	return e
+}
+
+// setTraceAllErrors tells the error callback whether the stack trace of all errors is used,
+// otherwise it is only built for the errors recorded by the logger of the request (see go_is_php_error_logged)
+func setTraceAllErrors() {
+	C.frankenphp_set_trace_all_errors(C.bool(errorReporter != nil))
+}
+
+// isPHPErrorLogged reports whether the logger records the errors of the given PHP level
+func isPHPErrorLogged(ctx context.Context, l *slog.Logger, level int) bool {
+	e := PHPError{Type: phpErrorTypes[level]}
+
+	return l.Enabled(ctx, e.slogLevel())
+}
+
+// errorLogger returns the logger of the request handled by the thread, or the global logger
+func (thread *phpThread) errorLogger() (*slog.Logger, context.Context) {
+	if fc := thread.getRequestContext(); fc != nil {
+		return fc.logger, fc.request.Context()
+	}
+
+	return logger, context.Background()
+}
+
+// go_is_php_error_logged reports whether an error of the given level will be recorded by the logger
+// of the current request, to build the stack trace only when it will be used
+//
+//export go_is_php_error_logged
+func go_is_php_error_logged(threadIndex C.uintptr_t, level C.int) C.bool {
+	l, ctx := phpThreads[threadIndex].errorLogger()
+
+	return C.bool(isPHPErrorLogged(ctx, l, int(level)))
+}
+
+// go_report_php_error is called on the PHP thread when an error is raised
+// the error is kept until PHP decides whether to log it (see go_log_php_error)
+//
+//export go_report_php_error
+func go_report_php_error(threadIndex C.uintptr_t, level C.int, file *C.char, fileLen C.size_t, line C.uint32_t, message *C.char, messageLen C.size_t, trace *C.char, traceLen C.size_t) {
+	thread := phpThreads[threadIndex]
+
+	var goTrace string
+	if trace != nil {
+		goTrace = C.GoStringN(trace, C.int(traceLen))
+	}
+
+	var goFile string
+	if file != nil {
+		goFile = C.GoStringN(file, C.int(fileLen))
+	}
+
+	e := newPHPError(int(level), goFile, int(line), C.GoStringN(message, C.int(messageLen)), goTrace)
+
+	ctx := context.Background()
+	if fc := thread.getRequestContext(); fc != nil {
+		ctx = fc.request.Context()
+		if fc.responseWriter != nil {
+			e.RequestURI = fc.requestURI()
+		}
+		if fc.worker != nil {
+			e.Worker = fc.worker.name
+		}
+	}
+
+	thread.pendingError = &e
+
+	if errorReporter != nil {
+		errorReporter.ReportPHPError(ctx, e)
+	}
+}
+
+// go_log_php_error is called instead of go_log when PHP logs the error previously passed to go_report_php_error
+//
+//export go_log_php_error
+func go_log_php_error(threadIndex C.uintptr_t, message *C.char, level C.int) {
+	thread := phpThreads[threadIndex]
+	e := thread.pendingError
+	thread.pendingError = nil
+
+	if e == nil {
+		go_log(message, level)
+
+		return
+	}
+
+	l, ctx := thread.errorLogger()
+	l.LogAttrs(ctx, e.slogLevel(), e.Message, e.attrs()...)
+}
@@ -0,0 +1,58 @@
+package frankenphp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPHPError(t *testing.T) {
+	e := newPHPError(2, "/app/index.php", 3, "Undefined variable $foo", "#0 {main}")
+
+	assert.Equal(t, "E_WARNING", e.Type)
+	assert.Equal(t, "Undefined variable $foo", e.Message)
+	assert.Equal(t, "#0 {main}", e.Trace)
+	assert.Empty(t, e.Exception)
+	assert.Equal(t, slog.LevelWarn, e.slogLevel())
+}
+
+func TestNewPHPErrorFromUncaughtException(t *testing.T) {
+	e := newPHPError(
+		1,
+		"/app/index.php",
+		7,
+		"Uncaught RuntimeException: something went wrong in /app/index.php:7\nStack trace:\n#0 /app/index.php(12): {closure}()\n#1 {main}\n  thrown",
+		"",
+	)
+
+	assert.Equal(t, "E_ERROR", e.Type)
+	assert.Equal(t, "RuntimeException", e.Exception)
+	assert.Equal(t, "something went wrong", e.Message)
+	assert.Equal(t, "#0 /app/index.php(12): {closure}()\n#1 {main}", e.Trace)
+	assert.Equal(t, slog.LevelError, e.slogLevel())
+}
+
+func TestNewPHPErrorWithUnknownLevel(t *testing.T) {
+	e := newPHPError(1<<20, "", 0, "Uncaught exception", "")
+
+	assert.Equal(t, "E_UNKNOWN", e.Type)
+	assert.Equal(t, "Uncaught exception", e.Message)
+	assert.Empty(t, e.Exception)
+	assert.Equal(t, slog.LevelInfo, e.slogLevel())
+}
+
+func TestIsPHPErrorLogged(t *testing.T) {
+	ctx := context.Background()
+	warnLogger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	assert.True(t, isPHPErrorLogged(ctx, warnLogger, 1), "E_ERROR must be logged")
+	assert.True(t, isPHPErrorLogged(ctx, warnLogger, 2), "E_WARNING must be logged")
+	assert.False(t, isPHPErrorLogged(ctx, warnLogger, 8), "E_NOTICE must not be logged")
+	assert.False(t, isPHPErrorLogged(ctx, warnLogger, 8192), "E_DEPRECATED must not be logged")
+
+	infoLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	assert.True(t, isPHPErrorLogged(ctx, infoLogger, 8192), "E_DEPRECATED must be logged")
+}
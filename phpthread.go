@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -22,6 +23,18 @@ type phpThread struct {
 	handler      threadHandler
 	state        *threadState
 	sandboxedEnv map[string]*C.zend_string
+	// the request currently handled by the thread, safe to read from other goroutines
+	activeRequest atomic.Pointer[frankenPHPContext]
+	// used to interrupt the script running on the thread (see interrupt.go)
+	interruptMu   sync.Mutex
+	vmInterrupt   *C.zend_atomic_bool
+	snapshotMu    sync.Mutex
+	snapshotChan  chan threadSnapshot
+	snapshotStack bool
+	// the request that should be terminated on the next interrupt
+	terminateRequest *frankenPHPContext
+	// the last error raised by PHP, logged if PHP decides to log it (see phperror.go)
+	pendingError *PHPError
 }
 
 // interface that defines how the callbacks from the C thread should be handled
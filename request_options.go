@@ -1,6 +1,7 @@
 package frankenphp
 
 import (
+	"crypto/x509"
 	"log/slog"
 	"net/http"
 	"path/filepath"
@@ -73,7 +74,7 @@ func WithRequestResolvedDocumentRoot(documentRoot string) RequestOption {
 //
 // Future enhancements should be careful to avoid CVE-2019-11043,
 // which can be mitigated with use of a try_files-like behavior
-// that 404s if the FastCGI path info is not found.
+// that 404s if the FastCGI path info is not found (see WithRequestTryFiles).
 func WithRequestSplitPath(splitPath []string) RequestOption {
 	return func(o *frankenPHPContext) error {
 		o.splitPath = splitPath
@@ -115,6 +116,18 @@ func WithOriginalRequest(r *http.Request) RequestOption {
 	}
 }
 
+// WithRequestServerCertificate sets the function returning the certificate presented to the client, used to populate SSL_SERVER_S_DN.
+//
+// It is only called for TLS requests, when the script uses $_SERVER.
+// By default, the certificate is selected from the TLSConfig of the http.Server handling the request.
+func WithRequestServerCertificate(serverCertificate func() *x509.Certificate) RequestOption {
+	return func(o *frankenPHPContext) error {
+		o.serverCertificate = serverCertificate
+
+		return nil
+	}
+}
+
 // WithRequestLogger sets the logger associated with the current request
 func WithRequestLogger(logger *slog.Logger) RequestOption {
 	return func(o *frankenPHPContext) error {
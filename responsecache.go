@@ -0,0 +1,588 @@
+package frankenphp
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// the status codes that can be cached, see https://www.rfc-editor.org/rfc/rfc9110#section-15.1
+var cacheableStatusCodes = []int{200, 203, 204, 300, 301, 308, 404, 405, 410, 414, 501}
+
+var responseCache *cache
+
+// cache is an in-memory LRU cache of the responses generated by PHP scripts
+type cache struct {
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	// the least recently used entries are at the back of the list
+	lru     *list.List
+	entries map[string]*list.Element
+	// the headers listed in the Vary header of the responses, by URL
+	variants map[string]*cacheVariants
+	// the keys of the entries, by Surrogate-Key tag
+	tags map[string]map[string]struct{}
+}
+
+type cacheVariants struct {
+	headers []string
+	count   int
+}
+
+// cachedResponse is a response stored in the cache
+type cachedResponse struct {
+	key                  string
+	url                  string
+	statusCode           int
+	header               http.Header
+	body                 []byte
+	tags                 []string
+	storedAt             time.Time
+	maxAge               time.Duration
+	staleWhileRevalidate time.Duration
+	revalidating         atomic.Bool
+}
+
+func newCache(maxSize int64) *cache {
+	return &cache{
+		maxSize:  maxSize,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+		variants: make(map[string]*cacheVariants),
+		tags:     make(map[string]map[string]struct{}),
+	}
+}
+
+// PurgeCacheTags removes the responses tagged with any of the given tags (Surrogate-Key header) from the cache.
+// It returns the number of removed responses.
+func PurgeCacheTags(tags ...string) int {
+	if responseCache == nil {
+		return 0
+	}
+
+	return responseCache.purgeTags(tags)
+}
+
+// cacheURL identifies the resource requested, the document root is included because different applications can serve the same URL
+func cacheURL(fc *frankenPHPContext) string {
+	return fc.documentRoot + "\n" + fc.request.Host + fc.requestURI()
+}
+
+// cacheKey identifies a variant of the resource, according to the headers listed in the Vary header.
+// The cookies are always part of the key: responses generated for a session must not be sent to other clients.
+func cacheKey(url string, varyHeaders []string, r *http.Request) string {
+	cookies := r.Header.Values("Cookie")
+	if len(varyHeaders) == 0 && len(cookies) == 0 {
+		return url
+	}
+
+	var b strings.Builder
+	b.WriteString(url)
+	for _, name := range varyHeaders {
+		b.WriteString("\n" + name + ":" + strings.Join(r.Header.Values(name), ","))
+	}
+	if len(cookies) > 0 && !slices.Contains(varyHeaders, "Cookie") {
+		b.WriteString("\nCookie:" + strings.Join(cookies, ","))
+	}
+
+	return b.String()
+}
+
+// isCacheableRequest checks if the response to the request can be stored in the cache.
+// As most reverse proxy caches, the Cache-Control header of the request is ignored.
+func isCacheableRequest(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// isAuthorizedRequest checks if the request contains credentials, see https://www.rfc-editor.org/rfc/rfc9111#section-3.5
+func isAuthorizedRequest(r *http.Request) bool {
+	return r.Header.Get("Authorization") != ""
+}
+
+func (c *cache) get(fc *frankenPHPContext) *cachedResponse {
+	url := cacheURL(fc)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var varyHeaders []string
+	if v, ok := c.variants[url]; ok {
+		varyHeaders = v.headers
+	}
+
+	e, ok := c.entries[cacheKey(url, varyHeaders, fc.request)]
+	if !ok {
+		return nil
+	}
+
+	entry := e.Value.(*cachedResponse)
+	if time.Since(entry.storedAt) > entry.maxAge+entry.staleWhileRevalidate {
+		c.remove(e)
+
+		return nil
+	}
+
+	c.lru.MoveToFront(e)
+
+	return entry
+}
+
+func (c *cache) set(entry *cachedResponse) {
+	entrySize := entry.size()
+
+	// prevent a single response to evict most of the cache, this is a totally arbitrary value
+	if entrySize > c.maxSize/8 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[entry.key]; ok {
+		c.remove(e)
+	}
+
+	for c.size+entrySize > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+
+	varyHeaders := varyHeaders(entry.header)
+	if v, ok := c.variants[entry.url]; ok && slices.Equal(v.headers, varyHeaders) {
+		v.count++
+	} else {
+		if ok {
+			// the variants listed by the previous responses are not reachable anymore
+			c.purgeURL(entry.url)
+		}
+
+		c.variants[entry.url] = &cacheVariants{headers: varyHeaders, count: 1}
+	}
+
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.size += entrySize
+
+	for _, tag := range entry.tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+
+		c.tags[tag][entry.key] = struct{}{}
+	}
+}
+
+// delete removes the entry with the given key, if it still exists
+func (c *cache) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+}
+
+func (c *cache) purgeTags(tags []string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	purged := 0
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if e, ok := c.entries[key]; ok {
+				c.remove(e)
+				purged++
+			}
+		}
+	}
+
+	return purged
+}
+
+// purgeURL removes all the variants of a URL, the lock must be held
+func (c *cache) purgeURL(url string) {
+	for _, e := range c.entries {
+		if entry := e.Value.(*cachedResponse); entry.url == url {
+			c.remove(e)
+		}
+	}
+}
+
+// remove removes an entry from the cache, the lock must be held
+func (c *cache) remove(e *list.Element) {
+	entry := e.Value.(*cachedResponse)
+
+	c.lru.Remove(e)
+	delete(c.entries, entry.key)
+	c.size -= entry.size()
+
+	for _, tag := range entry.tags {
+		delete(c.tags[tag], entry.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+
+	if v, ok := c.variants[entry.url]; ok {
+		v.count--
+		if v.count <= 0 {
+			delete(c.variants, entry.url)
+		}
+	}
+}
+
+// size is the approximate memory used by the response
+func (entry *cachedResponse) size() int64 {
+	size := len(entry.key) + len(entry.body)
+	for k, v := range entry.header {
+		size += len(k)
+		for _, s := range v {
+			size += len(s)
+		}
+	}
+
+	return int64(size)
+}
+
+// isStale checks if the response must be revalidated, stale responses can still be served while revalidating
+func (entry *cachedResponse) isStale() bool {
+	return time.Since(entry.storedAt) > entry.maxAge
+}
+
+// serveFromCache sends the cached response if any, it returns false if the request must be handled by PHP.
+// If the response is cacheable, it will be stored once the script has finished.
+func (fc *frankenPHPContext) serveFromCache() bool {
+	if responseCache == nil || !isCacheableRequest(fc.request) {
+		return false
+	}
+
+	authorized := isAuthorizedRequest(fc.request)
+
+	// the stored responses may not be valid for authenticated clients
+	if !fc.revalidating && !authorized {
+		if entry := responseCache.get(fc); entry != nil {
+			ttl := strconv.Itoa(int((entry.maxAge - time.Since(entry.storedAt)).Seconds()))
+			if !entry.isStale() {
+				fc.writeCachedResponse(entry, "FrankenPHP; hit; ttl="+ttl)
+
+				return true
+			}
+
+			if entry.revalidating.CompareAndSwap(false, true) {
+				go fc.revalidate(entry)
+			}
+
+			fc.writeCachedResponse(entry, "FrankenPHP; hit; ttl="+ttl+"; fwd=stale")
+
+			return true
+		}
+	}
+
+	// HEAD responses have no body, only GET responses are stored
+	if fc.request.Method == http.MethodGet {
+		fc.cacheRecorder = &cacheRecorder{ResponseWriter: fc.responseWriter, maxBodySize: responseCache.maxSize / 8, cacheStatus: true, authorized: authorized}
+		fc.responseWriter = fc.cacheRecorder
+	}
+
+	return false
+}
+
+// writeCachedResponse sends a stored response, without using a PHP thread
+func (fc *frankenPHPContext) writeCachedResponse(entry *cachedResponse, cacheStatus string) {
+	h := fc.responseWriter.Header()
+	for k, v := range entry.header {
+		h[k] = slices.Clone(v)
+	}
+
+	h.Set("Age", strconv.Itoa(int(time.Since(entry.storedAt).Seconds())))
+	h.Set("Cache-Status", cacheStatus)
+
+	if etag := entry.header.Get("ETag"); etag != "" && entry.statusCode == http.StatusOK && etagMatches(fc.request.Header.Get("If-None-Match"), etag) {
+		h.Del("Content-Length")
+		h.Del("Content-Type")
+		fc.responseWriter.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(entry.body)))
+	fc.responseWriter.WriteHeader(entry.statusCode)
+
+	if fc.request.Method != http.MethodHead {
+		_, _ = fc.responseWriter.Write(entry.body)
+	}
+}
+
+// revalidate executes the request in the background to refresh a stale response
+func (fc *frankenPHPContext) revalidate(entry *cachedResponse) {
+	defer entry.revalidating.Store(false)
+
+	bg := &frankenPHPContext{
+		documentRoot:    fc.documentRoot,
+		splitPath:       fc.splitPath,
+		env:             fc.env,
+		logger:          fc.logger,
+		worker:          fc.worker,
+		docURI:          fc.docURI,
+		pathInfo:        fc.pathInfo,
+		scriptName:      fc.scriptName,
+		scriptFilename:  fc.scriptFilename,
+		forwarded:       fc.forwarded,
+		responseFilters: fc.responseFilters,
+		sendfileRoot:    fc.sendfileRoot,
+		revalidating:    true,
+		done:            make(chan any),
+		startedAt:       time.Now(),
+	}
+
+	// the revalidation must not be canceled when the client that triggered it disconnects
+	ctx := context.WithValue(context.WithoutCancel(fc.request.Context()), contextKey, bg)
+	bg.request = fc.request.Clone(ctx)
+	bg.request.Method = http.MethodGet
+	bg.request.Body = http.NoBody
+	// a full response is needed to be stored
+	bg.request.Header.Del("If-None-Match")
+	bg.request.Header.Del("If-Modified-Since")
+	if fc.originalRequest != nil {
+		bg.originalRequest = fc.originalRequest.Clone(ctx)
+	}
+
+	if err := ServeHTTP(&discardResponseWriter{header: make(http.Header)}, bg.request); err != nil {
+		fc.logger.LogAttrs(ctx, slog.LevelWarn, "unable to revalidate the cached response", slog.String("uri", fc.requestURI()), slog.Any("error", err))
+
+		return
+	}
+
+	if bg.cacheRecorder == nil || !bg.cacheRecorder.cacheable {
+		// the new response isn't cacheable, don't serve the old one anymore
+		responseCache.delete(entry.key)
+	}
+}
+
+// storeInCache stores the response generated by the script, if it is cacheable
+func (fc *frankenPHPContext) storeInCache() {
+	if responseCache == nil {
+		return
+	}
+
+	entry := fc.cacheRecorder.response()
+	// the response may be truncated if the client disconnected
+	if entry == nil || fc.clientHasClosed() {
+		return
+	}
+
+	url := cacheURL(fc)
+	entry.url = url
+	entry.key = cacheKey(url, varyHeaders(entry.header), fc.request)
+
+	responseCache.set(entry)
+}
+
+// cacheRecorder captures the response generated by the script to store it in the cache
+type cacheRecorder struct {
+	http.ResponseWriter
+	maxBodySize int64
+	// add the Cache-Status header to the response
+	cacheStatus bool
+	// the request contains credentials, the response must explicitly allow shared caching
+	authorized  bool
+	wroteHeader bool
+	cacheable   bool
+	tooLarge    bool
+	entry       *cachedResponse
+	body        bytes.Buffer
+}
+
+// response returns the recorded response, or nil if it can't be stored
+func (r *cacheRecorder) response() *cachedResponse {
+	if !r.cacheable || r.tooLarge {
+		return nil
+	}
+
+	r.entry.body = r.body.Bytes()
+
+	return r.entry
+}
+
+func (r *cacheRecorder) WriteHeader(statusCode int) {
+	if statusCode < 200 || r.wroteHeader {
+		r.ResponseWriter.WriteHeader(statusCode)
+
+		return
+	}
+
+	r.wroteHeader = true
+
+	h := r.Header()
+	if entry := newCachedResponse(statusCode, h, r.authorized); entry != nil {
+		r.cacheable = true
+		r.entry = entry
+		if r.cacheStatus {
+			h.Set("Cache-Status", "FrankenPHP; fwd=uri-miss; stored")
+		}
+	} else if r.cacheStatus {
+		h.Set("Cache-Status", "FrankenPHP; fwd=uri-miss")
+	}
+
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *cacheRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.cacheable && !r.tooLarge {
+		if int64(r.body.Len()+len(b)) > r.maxBodySize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *cacheRecorder) Flush() {
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+func (r *cacheRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// newCachedResponse creates a cache entry if the response can be stored, according to RFC 9111
+func newCachedResponse(statusCode int, h http.Header, authorized bool) *cachedResponse {
+	if !slices.Contains(cacheableStatusCodes, statusCode) || h.Get("Set-Cookie") != "" || h.Get("Trailer") != "" {
+		return nil
+	}
+
+	if slices.Contains(varyHeaders(h), "*") {
+		return nil
+	}
+
+	directives := parseCacheControl(h.Values("Cache-Control"))
+	if hasAnyDirective(directives, "no-store", "no-cache", "private") {
+		return nil
+	}
+
+	// responses to requests containing credentials can only be stored if explicitly allowed,
+	// see https://www.rfc-editor.org/rfc/rfc9111#section-3.5
+	if authorized && !hasAnyDirective(directives, "public", "s-maxage", "must-revalidate") {
+		return nil
+	}
+
+	// s-maxage takes precedence for shared caches
+	maxAge, ok := directives["s-maxage"]
+	if !ok {
+		maxAge = directives["max-age"]
+	}
+
+	seconds, err := strconv.Atoi(maxAge)
+	if err != nil || seconds <= 0 {
+		return nil
+	}
+
+	entry := &cachedResponse{
+		statusCode: statusCode,
+		header:     h.Clone(),
+		storedAt:   time.Now(),
+		maxAge:     time.Duration(seconds) * time.Second,
+		tags:       strings.Fields(strings.Join(h.Values("Surrogate-Key"), " ")),
+	}
+
+	if swr, err := strconv.Atoi(directives["stale-while-revalidate"]); err == nil && swr > 0 {
+		entry.staleWhileRevalidate = time.Duration(swr) * time.Second
+	}
+
+	// these headers are computed when the response is served
+	entry.header.Del("Age")
+	entry.header.Del("Cache-Status")
+	entry.header.Del("Content-Length")
+	entry.header.Del("Date")
+
+	return entry
+}
+
+func hasAnyDirective(directives map[string]string, names ...string) bool {
+	for _, name := range names {
+		if _, ok := directives[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// varyHeaders returns the canonical names of the headers listed in the Vary header
+func varyHeaders(h http.Header) []string {
+	var names []string
+	for _, value := range h.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return names
+}
+
+// parseCacheControl returns the directives of Cache-Control headers, names are lowercased
+func parseCacheControl(values []string) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range values {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+
+			directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+		}
+	}
+
+	return directives
+}
+
+// etagMatches implements the weak comparison of the If-None-Match header
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// discardResponseWriter is used for background requests, only the cache recorder reads the response
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
+
+func (w *discardResponseWriter) Flush() {}
@@ -0,0 +1,123 @@
+package frankenphp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCachedResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		header      http.Header
+		authorized  bool
+		cacheable   bool
+		maxAge      time.Duration
+		staleMaxAge time.Duration
+	}{
+		{"max-age", 200, http.Header{"Cache-Control": {"public, max-age=60"}}, false, true, time.Minute, 0},
+		{"s-maxage has precedence", 200, http.Header{"Cache-Control": {"max-age=60, s-maxage=10"}}, false, true, 10 * time.Second, 0},
+		{"stale-while-revalidate", 404, http.Header{"Cache-Control": {"max-age=1", "stale-while-revalidate=30"}}, false, true, time.Second, 30 * time.Second},
+		{"no freshness", 200, http.Header{}, false, false, 0, 0},
+		{"private", 200, http.Header{"Cache-Control": {"private, max-age=60"}}, false, false, 0, 0},
+		{"no-store", 200, http.Header{"Cache-Control": {"No-Store, max-age=60"}}, false, false, 0, 0},
+		{"cookie", 200, http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"a=b"}}, false, false, 0, 0},
+		{"vary all", 200, http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}}, false, false, 0, 0},
+		{"status", 500, http.Header{"Cache-Control": {"max-age=60"}}, false, false, 0, 0},
+		{"authorization", 200, http.Header{"Cache-Control": {"max-age=60"}}, true, false, 0, 0},
+		{"authorization and public", 200, http.Header{"Cache-Control": {"public, max-age=60"}}, true, true, time.Minute, 0},
+		{"authorization and s-maxage", 200, http.Header{"Cache-Control": {"s-maxage=60"}}, true, true, time.Minute, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := newCachedResponse(test.statusCode, test.header, test.authorized)
+			if !test.cacheable {
+				assert.Nil(t, entry)
+
+				return
+			}
+
+			assert.Equal(t, test.maxAge, entry.maxAge)
+			assert.Equal(t, test.staleMaxAge, entry.staleWhileRevalidate)
+		})
+	}
+}
+
+func TestCacheTagsAndEviction(t *testing.T) {
+	c := newCache(8 * 1024)
+	body := []byte(strings.Repeat("a", 512))
+
+	for _, key := range []string{"a", "b", "c"} {
+		c.set(&cachedResponse{key: key, url: key, header: http.Header{}, body: body, tags: []string{"tag-" + key, "all"}, storedAt: time.Now(), maxAge: time.Minute})
+	}
+	assert.Len(t, c.entries, 3)
+
+	assert.Equal(t, 1, c.purgeTags([]string{"tag-b"}))
+	assert.NotContains(t, c.entries, "b")
+	assert.Equal(t, 2, c.purgeTags([]string{"all"}))
+	assert.Empty(t, c.entries)
+	assert.Empty(t, c.tags)
+	assert.Zero(t, c.size)
+
+	// the least recently used entries are evicted first
+	for i := range 16 {
+		key := string(rune('a' + i))
+		c.set(&cachedResponse{key: key, url: key, header: http.Header{}, body: body, storedAt: time.Now(), maxAge: time.Minute})
+	}
+	assert.NotContains(t, c.entries, "a")
+	assert.Contains(t, c.entries, "p")
+	assert.LessOrEqual(t, c.size, c.maxSize)
+}
+
+func TestCacheVary(t *testing.T) {
+	c := newCache(1024 * 1024)
+	fc := &frankenPHPContext{request: httptest.NewRequest("GET", "http://example.com/", nil)}
+	fc.request.Header.Set("Accept-Language", "fr")
+	fc.request.Header.Set("Accept-Encoding", "gzip")
+
+	entry := newCachedResponse(200, http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"accept-language, Accept-Encoding"}}, false)
+	entry.url = cacheURL(fc)
+	entry.key = cacheKey(entry.url, varyHeaders(entry.header), fc.request)
+	c.set(entry)
+
+	assert.Same(t, entry, c.get(fc))
+
+	fc.request.Header.Set("Accept-Language", "en")
+	assert.Nil(t, c.get(fc))
+
+	fc.request.Header.Set("Accept-Language", "fr")
+	fc.request.Header.Set("Accept-Encoding", "br")
+	assert.Nil(t, c.get(fc))
+}
+
+func TestCacheKeyContainsCookies(t *testing.T) {
+	c := newCache(1024 * 1024)
+	fc := &frankenPHPContext{request: httptest.NewRequest("GET", "http://example.com/", nil)}
+	fc.request.Header.Set("Cookie", "session=alice")
+
+	entry := newCachedResponse(200, http.Header{"Cache-Control": {"max-age=60"}}, false)
+	entry.url = cacheURL(fc)
+	entry.key = cacheKey(entry.url, varyHeaders(entry.header), fc.request)
+	c.set(entry)
+
+	assert.Same(t, entry, c.get(fc))
+
+	fc.request.Header.Set("Cookie", "session=bob")
+	assert.Nil(t, c.get(fc), "a response generated for a session must not be sent to another one")
+
+	fc.request.Header.Del("Cookie")
+	assert.Nil(t, c.get(fc))
+}
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"a", W/"b"`, `"b"`))
+	assert.True(t, etagMatches(`*`, `"b"`))
+	assert.False(t, etagMatches(`"a"`, `"b"`))
+	assert.False(t, etagMatches(``, `"b"`))
+}
@@ -0,0 +1,92 @@
+package frankenphp
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+)
+
+// FilteredResponse is the response generated by a PHP script, before its headers are written.
+type FilteredResponse struct {
+	Request *http.Request
+	// StatusCode can be changed, invalid values (below 200 or above 999) are replaced by 500
+	StatusCode int
+	// Header contains the headers set by the script, it can be modified in place
+	Header http.Header
+	// Body receives the output of the script, it can be replaced by a writer wrapping it.
+	// If the new writer implements io.Closer, it is closed when the script has finished.
+	Body io.Writer
+}
+
+// ResponseFilter post-processes the responses generated by PHP scripts,
+// for instance to add security headers, rewrite cookies or transform the body.
+//
+// Filters are called on the PHP thread: they must return quickly.
+type ResponseFilter func(response *FilteredResponse)
+
+var responseFilters []ResponseFilter
+
+// WithRequestResponseFilter adds a filter applied to the response of the request, after the global filters.
+func WithRequestResponseFilter(filter ResponseFilter) RequestOption {
+	return func(o *frankenPHPContext) error {
+		o.responseFilters = append(o.responseFilters, filter)
+
+		return nil
+	}
+}
+
+// applyResponseFilters runs the filters on the response before the headers are written, it returns the status code to send
+func (fc *frankenPHPContext) applyResponseFilters(status int) int {
+	if len(responseFilters) == 0 && len(fc.responseFilters) == 0 {
+		return status
+	}
+
+	response := &FilteredResponse{
+		Request:    fc.request,
+		StatusCode: status,
+		Header:     fc.responseWriter.Header(),
+		Body:       fc.responseWriter,
+	}
+
+	for _, filter := range responseFilters {
+		filter(response)
+	}
+	for _, filter := range fc.responseFilters {
+		filter(response)
+	}
+
+	if !isSameWriter(response.Body, fc.responseWriter) {
+		fc.bodyWriter = response.Body
+	}
+
+	// http.ResponseWriter.WriteHeader panics on invalid status codes, and informational responses can't be final
+	if response.StatusCode < 200 || response.StatusCode > 999 {
+		fc.logger.LogAttrs(fc.request.Context(), slog.LevelError, "invalid status code set by a response filter", slog.Int("status", response.StatusCode))
+
+		return http.StatusInternalServerError
+	}
+
+	return response.StatusCode
+}
+
+// isSameWriter reports whether the writer is the response writer,
+// comparing interfaces holding a non-comparable type with == panics
+func isSameWriter(w io.Writer, rw http.ResponseWriter) bool {
+	if w == nil || !reflect.TypeOf(w).Comparable() {
+		return false
+	}
+
+	return w == io.Writer(rw)
+}
+
+// closeBodyWriter closes the writer set by a response filter, if any
+func (fc *frankenPHPContext) closeBodyWriter() {
+	if c, ok := fc.bodyWriter.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			fc.logger.LogAttrs(fc.request.Context(), slog.LevelWarn, "unable to close the response body writer", slog.Any("error", err))
+		}
+	}
+
+	fc.bodyWriter = nil
+}
@@ -101,6 +101,7 @@ func scaleWorkerThread(worker *worker) {
 	autoScaledThreads = append(autoScaledThreads, thread)
 
 	logger.LogAttrs(context.Background(), slog.LevelInfo, "upscaling worker thread", slog.String("worker", worker.name), slog.Int("thread", thread.threadIndex), slog.Int("num_threads", len(autoScaledThreads)))
+	emitWorkerEvent(WorkerEventScaledUp, worker, thread)
 }
 
 // scaleRegularThread adds a regular PHP thread automatically
@@ -201,7 +202,14 @@ func deactivateThreads() {
 
 		// convert threads to inactive if they have been idle for too long
 		if thread.state.is(stateReady) && waitTime > maxThreadIdleTime.Milliseconds() {
+			thread.handlerMu.Lock()
+			workerHandler, isWorkerThread := thread.handler.(*workerThread)
+			thread.handlerMu.Unlock()
+
 			convertToInactiveThread(thread)
+			if isWorkerThread {
+				emitWorkerEvent(WorkerEventScaledDown, workerHandler.worker, thread)
+			}
 			stoppedThreadCount++
 			autoScaledThreads = append(autoScaledThreads[:i], autoScaledThreads[i+1:]...)
 			logger.LogAttrs(context.Background(), slog.LevelInfo, "downscaling thread", slog.Int("thread", thread.threadIndex), slog.Int64("wait_time", waitTime), slog.Int("num_threads", len(autoScaledThreads)))
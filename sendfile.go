@@ -0,0 +1,125 @@
+package frankenphp
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dunglas/frankenphp/internal/fastabs"
+)
+
+// sendfileResponse is a file to send instead of the output of the PHP script
+type sendfileResponse struct {
+	path      string
+	forbidden bool
+}
+
+// WithRequestSendfileRoot enables X-Sendfile and X-Accel-Redirect responses.
+//
+// When the script responds with a 200 status code and one of these headers,
+// its output is discarded and the file is sent instead, with support for
+// range and conditional requests. The X-Sendfile header contains an absolute path
+// or a path relative to root, the X-Accel-Redirect header contains a path relative to root.
+// Files outside of root are never sent.
+func WithRequestSendfileRoot(root string) RequestOption {
+	return func(o *frankenPHPContext) error {
+		absRoot, err := fastabs.FastAbs(root)
+		if err != nil {
+			return err
+		}
+
+		o.sendfileRoot = absRoot
+
+		return nil
+	}
+}
+
+// interceptSendfile checks if the script asked to send a file, the headers are removed from the response
+func (fc *frankenPHPContext) interceptSendfile() bool {
+	h := fc.responseWriter.Header()
+
+	value := h.Get("X-Sendfile")
+	isAccelRedirect := false
+	if value == "" {
+		value = h.Get("X-Accel-Redirect")
+		isAccelRedirect = true
+	}
+	if value == "" {
+		return false
+	}
+
+	h.Del("X-Sendfile")
+	h.Del("X-Accel-Redirect")
+	// the length of the file will be computed by http.ServeContent
+	h.Del("Content-Length")
+
+	path, ok := resolveSendfilePath(fc.sendfileRoot, value, !isAccelRedirect)
+	fc.sendfile = &sendfileResponse{path: path, forbidden: !ok}
+
+	return true
+}
+
+// resolveSendfilePath returns the path of the file to send, and false if it is outside of root
+func resolveSendfilePath(root string, value string, allowAbsolute bool) (string, bool) {
+	var path string
+	if allowAbsolute && filepath.IsAbs(value) {
+		path = filepath.Clean(value)
+	} else {
+		path = sanitizedPathJoin(root, value)
+	}
+
+	// symbolic links must not point outside of root
+	if resolvedRoot, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolvedRoot
+	}
+	if resolvedPath, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolvedPath
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+separator) {
+		return "", false
+	}
+
+	return path, true
+}
+
+// serveSendfile sends the file requested by the script, once the script has finished
+func (fc *frankenPHPContext) serveSendfile() {
+	w := fc.responseWriter
+
+	if fc.sendfile.forbidden {
+		fc.logger.LogAttrs(fc.request.Context(), slog.LevelWarn, "X-Sendfile path outside of the allowed root", slog.String("uri", fc.requestURI()))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("Forbidden"))
+
+		return
+	}
+
+	f, err := os.Open(fc.sendfile.path)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Not Found"))
+
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Not Found"))
+
+		return
+	}
+
+	if w.Header().Get("ETag") == "" {
+		// same format as Caddy's file_server
+		w.Header().Set("ETag", `"`+strconv.FormatInt(info.ModTime().UnixNano(), 36)+strconv.FormatInt(info.Size(), 36)+`"`)
+	}
+
+	http.ServeContent(w, fc.request, info.Name(), info.ModTime(), f)
+}
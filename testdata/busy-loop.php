@@ -0,0 +1,14 @@
+<?php
+
+require_once __DIR__ . '/_executor.php';
+
+return function () {
+    $until = microtime(true) + (int)($_GET['ms'] ?? 0) / 1000;
+
+    // keep the VM busy so that the thread can be interrupted
+    while (microtime(true) < $until) {
+        $a = 1;
+    }
+
+    echo 'done';
+};
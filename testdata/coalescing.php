@@ -0,0 +1,17 @@
+<?php
+
+require_once __DIR__.'/_executor.php';
+
+return function () {
+    $noStore = isset($_GET['no-store']);
+    // only the first response can't be shared
+    if (isset($_GET['first-no-store']) && !file_exists($_GET['first-no-store'])) {
+        touch($_GET['first-no-store']);
+        $noStore = true;
+    }
+
+    header($noStore ? 'Cache-Control: no-store' : 'Cache-Control: public, max-age=60');
+    usleep(100000);
+
+    echo hrtime(true);
+};
@@ -0,0 +1,20 @@
+<?php
+
+require_once __DIR__.'/_executor.php';
+
+return function () {
+    frankenphp_early_hints([
+        '/style.css' => ['as' => 'style'],
+        '/app.js' => ['rel' => 'modulepreload'],
+        '</font.woff2>; rel=preload; as=font; crossorigin',
+    ]);
+
+    // links already sent are ignored
+    frankenphp_early_hints([
+        '/style.css' => ['as' => 'style'],
+        'https://cdn.example.com' => ['rel' => 'preconnect', 'crossorigin' => true],
+    ]);
+
+    header('Link: </style.css>; rel=preload; as=style');
+    echo 'Hello';
+};
@@ -0,0 +1,18 @@
+<?php
+
+require_once __DIR__.'/_executor.php';
+
+return function () {
+    if (isset($_GET['purge'])) {
+        header('Cache-Control: no-store');
+        echo frankenphp_cache_purge_tags([$_GET['purge']]);
+
+        return;
+    }
+
+    header(isset($_GET['swr']) ? 'Cache-Control: public, max-age=1, stale-while-revalidate=30' : 'Cache-Control: public, max-age=60');
+    header("Surrogate-Key: tag-{$_GET['i']} all");
+    header("ETag: \"{$_GET['i']}\"");
+
+    echo hrtime(true);
+};
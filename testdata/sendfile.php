@@ -0,0 +1,9 @@
+<?php
+
+require_once __DIR__.'/_executor.php';
+
+return function () {
+    header(($_GET['header'] ?? 'X-Sendfile') . ': ' . $_GET['file']);
+    header('Content-Type: text/plain');
+    echo 'This output must not be sent';
+};
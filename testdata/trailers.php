@@ -0,0 +1,23 @@
+<?php
+
+require_once __DIR__.'/_executor.php';
+
+return function () {
+    if (isset($_GET['early'])) {
+        // sent before the body, the trailers are announced automatically
+        frankenphp_send_trailers(['Grpc-Status' => '0']);
+        echo 'Hello';
+
+        return;
+    }
+
+    if (!isset($_GET['unannounced'])) {
+        header('Trailer: Grpc-Status');
+    }
+    echo 'Hello';
+
+    frankenphp_send_trailers([
+        'Grpc-Status' => '0',
+        'Server-Timing' => ['db;dur=53', 'app;dur=47.2'],
+    ]);
+};
@@ -0,0 +1,9 @@
+<?php
+
+function warn(string $i): void
+{
+    trigger_error("warning $i", E_USER_WARNING);
+}
+
+warn($_GET['i']);
+echo 'done';
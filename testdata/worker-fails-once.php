@@ -0,0 +1,13 @@
+<?php
+
+// the first boot of the worker fails before reaching frankenphp_handle_request()
+$marker = $_SERVER['BOOT_MARKER'];
+if (!file_exists($marker)) {
+    touch($marker);
+    exit(1);
+}
+
+while (frankenphp_handle_request(function () {
+    echo 'ok';
+})) {
+}
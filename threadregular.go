@@ -2,6 +2,7 @@ package frankenphp
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
 // representation of a non-worker PHP thread
@@ -17,6 +18,8 @@ var (
 	regularThreads     []*phpThread
 	regularThreadMu    = &sync.RWMutex{}
 	regularRequestChan chan *frankenPHPContext
+	// number of requests waiting for a regular thread
+	queuedRegularRequests atomic.Int64
 )
 
 func convertToRegularThread(thread *phpThread) {
@@ -73,6 +76,7 @@ func (handler *regularThread) waitForRequest() string {
 	}
 
 	handler.requestContext = fc
+	handler.thread.activeRequest.Store(fc)
 	handler.state.markAsWaiting(false)
 
 	// set the scriptFilename that should be executed
@@ -82,6 +86,7 @@ func (handler *regularThread) waitForRequest() string {
 func (handler *regularThread) afterRequest() {
 	handler.requestContext.closeContext()
 	handler.requestContext = nil
+	handler.thread.activeRequest.Store(nil)
 }
 
 func handleRequestWithRegularPHPThreads(fc *frankenPHPContext) {
@@ -98,10 +103,12 @@ func handleRequestWithRegularPHPThreads(fc *frankenPHPContext) {
 
 	// if no thread was available, mark the request as queued and fan it out to all threads
 	metrics.QueuedRequest()
+	queuedRegularRequests.Add(1)
 	for {
 		select {
 		case regularRequestChan <- fc:
 			metrics.DequeuedRequest()
+			queuedRegularRequests.Add(-1)
 			<-fc.done
 			metrics.StopRequest()
 			return
@@ -110,6 +117,7 @@ func handleRequestWithRegularPHPThreads(fc *frankenPHPContext) {
 		case <-timeoutChan(maxWaitTime):
 			// the request has timed out stalling
 			metrics.DequeuedRequest()
+			queuedRegularRequests.Add(-1)
 			fc.reject(504, "Gateway Timeout")
 			return
 		}
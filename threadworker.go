@@ -96,6 +96,7 @@ func setupWorkerScript(handler *workerThread, worker *worker) {
 	handler.isBootingScript = true
 	clearSandboxedEnv(handler.thread)
 	logger.LogAttrs(context.Background(), slog.LevelDebug, "starting", slog.String("worker", worker.name), slog.Int("thread", handler.thread.threadIndex))
+	emitWorkerEvent(WorkerEventBooting, worker, handler.thread)
 }
 
 func tearDownWorkerScript(handler *workerThread, exitStatus int) {
@@ -109,6 +110,12 @@ func tearDownWorkerScript(handler *workerThread, exitStatus int) {
 	if handler.workerContext != nil {
 		handler.workerContext.closeContext()
 		handler.workerContext = nil
+		handler.thread.activeRequest.Store(nil)
+	}
+
+	// the thread stopped handling requests because of a restart or a shutdown (see drainWorkerThreads)
+	if handler.state.is(stateRestarting) {
+		emitWorkerEvent(WorkerEventDrained, worker, handler.thread)
 	}
 
 	// on exit status 0 we just run the worker script again
@@ -116,6 +123,7 @@ func tearDownWorkerScript(handler *workerThread, exitStatus int) {
 		metrics.StopWorker(worker.name, StopReasonRestart)
 		handler.backoff.recordSuccess()
 		logger.LogAttrs(ctx, slog.LevelDebug, "restarting", slog.String("worker", worker.name), slog.Int("thread", handler.thread.threadIndex), slog.Int("exit_status", exitStatus))
+		emitWorkerEventWithStatus(WorkerEventRestarted, worker, handler.thread, exitStatus, 0)
 
 		return
 	}
@@ -126,6 +134,7 @@ func tearDownWorkerScript(handler *workerThread, exitStatus int) {
 	if !handler.isBootingScript {
 		// fatal error (could be due to exit(1), timeouts, etc.)
 		logger.LogAttrs(ctx, slog.LevelDebug, "restarting", slog.String("worker", worker.name), slog.Int("thread", handler.thread.threadIndex), slog.Int("exit_status", exitStatus))
+		emitWorkerEventWithStatus(WorkerEventCrashed, worker, handler.thread, exitStatus, handler.backoff.failureCount)
 
 		return
 	}
@@ -133,7 +142,10 @@ func tearDownWorkerScript(handler *workerThread, exitStatus int) {
 	logger.LogAttrs(ctx, slog.LevelError, "worker script has not reached frankenphp_handle_request()", slog.String("worker", worker.name), slog.Int("thread", handler.thread.threadIndex))
 
 	// panic after exponential backoff if the worker has never reached frankenphp_handle_request
-	if handler.backoff.recordFailure() {
+	tooManyFailures := handler.backoff.recordFailure()
+	emitWorkerEventWithStatus(WorkerEventCrashed, worker, handler.thread, exitStatus, handler.backoff.failureCount)
+	if tooManyFailures {
+		emitWorkerEventWithStatus(WorkerEventTooManyFailures, worker, handler.thread, exitStatus, handler.backoff.failureCount)
 		if !watcherIsEnabled && !handler.state.is(stateReady) {
 			logger.LogAttrs(ctx, slog.LevelError, "too many consecutive worker failures", slog.String("worker", worker.name), slog.Int("thread", handler.thread.threadIndex), slog.Int("failures", handler.backoff.failureCount))
 			panic("too many consecutive worker failures")
@@ -156,6 +168,10 @@ func (handler *workerThread) waitForWorkerRequest() bool {
 		if !C.frankenphp_shutdown_dummy_request() {
 			panic("Not in CGI context")
 		}
+
+		// the script booted successfully, previous boot failures must not keep the thread in crash backoff
+		handler.backoff.recordSuccess()
+		emitWorkerEvent(WorkerEventReady, handler.worker, handler.thread)
 	}
 
 	// worker threads are 'ready' after they first reach frankenphp_handle_request()
@@ -185,6 +201,7 @@ func (handler *workerThread) waitForWorkerRequest() bool {
 	}
 
 	handler.workerContext = fc
+	handler.thread.activeRequest.Store(fc)
 	handler.state.markAsWaiting(false)
 
 	logger.LogAttrs(ctx, slog.LevelDebug, "request handling started", slog.String("worker", handler.worker.name), slog.Int("thread", handler.thread.threadIndex), slog.String("url", fc.request.RequestURI))
@@ -209,6 +226,7 @@ func go_frankenphp_finish_worker_request(threadIndex C.uintptr_t) {
 
 	fc.closeContext()
 	thread.handler.(*workerThread).workerContext = nil
+	thread.activeRequest.Store(nil)
 
 	fc.logger.LogAttrs(context.Background(), slog.LevelDebug, "request handling finished", slog.String("worker", fc.scriptFilename), slog.Int("thread", thread.threadIndex), slog.String("url", fc.request.RequestURI))
 }
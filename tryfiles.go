@@ -0,0 +1,174 @@
+package frankenphp
+
+import (
+	"container/list"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// how long the existence of a file is cached by WithRequestTryFiles
+	tryFilesCacheTTL = time.Second
+	// the maximum number of files in the cache, this is a totally arbitrary value
+	tryFilesCacheSize = 4096
+)
+
+var statCache = newStatCache(tryFilesCacheSize)
+
+// fileStatCache is an LRU cache of the existence of files
+type fileStatCache struct {
+	mu      sync.Mutex
+	maxLen  int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type statCacheEntry struct {
+	path      string
+	exists    bool
+	expiresAt time.Time
+}
+
+func newStatCache(maxLen int) *fileStatCache {
+	return &fileStatCache{
+		maxLen:  maxLen,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached existence of the file, ok is false if it isn't cached or has expired
+func (c *fileStatCache) get(path string, now time.Time) (exists bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[path]
+	if !ok {
+		return false, false
+	}
+
+	entry := e.Value.(*statCacheEntry)
+	if !now.Before(entry.expiresAt) {
+		c.lru.Remove(e)
+		delete(c.entries, path)
+
+		return false, false
+	}
+
+	c.lru.MoveToFront(e)
+
+	return entry.exists, true
+}
+
+// set caches the existence of the file, evicting the least recently used entry if the cache is full
+func (c *fileStatCache) set(path string, exists bool, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[path]; ok {
+		entry := e.Value.(*statCacheEntry)
+		entry.exists = exists
+		entry.expiresAt = expiresAt
+		c.lru.MoveToFront(e)
+
+		return
+	}
+
+	for c.lru.Len() >= c.maxLen {
+		back := c.lru.Back()
+		c.lru.Remove(back)
+		delete(c.entries, back.Value.(*statCacheEntry).path)
+	}
+
+	c.entries[path] = c.lru.PushFront(&statCacheEntry{path: path, exists: exists, expiresAt: expiresAt})
+}
+
+// WithRequestTryFiles sets the candidates to try, in order, to find the PHP script handling the request.
+//
+// Candidates are paths relative to the document root, "{path}" is replaced with the path of the request.
+// Only candidates matching the split path (.php by default) are executed: static files must be served
+// by another handler. If a candidate exists, the request is rewritten to it and $_SERVER['REQUEST_URI']
+// keeps the original URI. If no candidate exists, a 404 response is sent.
+//
+// This is the equivalent of the try_files directive of php_server:
+//
+//	WithRequestTryFiles([]string{"{path}", "{path}/index.php", "index.php"})
+func WithRequestTryFiles(files []string) RequestOption {
+	return func(o *frankenPHPContext) error {
+		o.tryFiles = files
+
+		return nil
+	}
+}
+
+// resolveTryFiles rewrites the request to the first existing candidate
+func resolveTryFiles(fc *frankenPHPContext) {
+	splitPath := fc.splitPath
+	if splitPath == nil {
+		splitPath = []string{".php"}
+	}
+
+	requestPath := fc.request.URL.Path
+	for _, file := range fc.tryFiles {
+		candidate := strings.ReplaceAll(file, "{path}", requestPath)
+		if cleaned := path.Clean("/" + candidate); strings.HasSuffix(candidate, "/") && cleaned != "/" {
+			candidate = cleaned + "/"
+		} else {
+			candidate = cleaned
+		}
+
+		// only PHP scripts may be executed, the part after the split is the PATH_INFO
+		pos := splitPos(candidate, splitPath)
+		if pos == -1 {
+			continue
+		}
+
+		if !fileExists(sanitizedPathJoin(fc.documentRoot, candidate[:pos])) {
+			continue
+		}
+
+		if candidate != requestPath {
+			rewriteRequestPath(fc, candidate)
+		}
+
+		return
+	}
+
+	fc.notFound = true
+}
+
+// rewriteRequestPath changes the path of the request, the original request is kept for REQUEST_URI
+func rewriteRequestPath(fc *frankenPHPContext, newPath string) {
+	if fc.originalRequest == nil {
+		fc.originalRequest = fc.request
+	}
+
+	r := new(http.Request)
+	*r = *fc.request
+	u := new(url.URL)
+	*u = *fc.request.URL
+	u.Path = newPath
+	u.RawPath = ""
+	r.URL = u
+
+	fc.request = r
+}
+
+// fileExists checks if a regular file exists, results are cached for a short time
+func fileExists(filePath string) bool {
+	now := time.Now()
+	if exists, ok := statCache.get(filePath, now); ok {
+		return exists
+	}
+
+	info, err := os.Stat(filePath)
+	exists := err == nil && info.Mode().IsRegular()
+	statCache.set(filePath, exists, now.Add(tryFilesCacheTTL))
+
+	return exists
+}
@@ -0,0 +1,55 @@
+package frankenphp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newStatCache(2)
+	now := time.Now()
+	expiresAt := now.Add(time.Minute)
+
+	c.set("/a", true, expiresAt)
+	c.set("/b", false, expiresAt)
+
+	// "/a" becomes the most recently used entry
+	exists, ok := c.get("/a", now)
+	assert.True(t, ok)
+	assert.True(t, exists)
+
+	c.set("/c", true, expiresAt)
+
+	_, ok = c.get("/b", now)
+	assert.False(t, ok, "the least recently used entry must be evicted")
+	_, ok = c.get("/a", now)
+	assert.True(t, ok)
+	_, ok = c.get("/c", now)
+	assert.True(t, ok)
+}
+
+func TestStatCacheExpiration(t *testing.T) {
+	c := newStatCache(2)
+	now := time.Now()
+
+	c.set("/a", true, now.Add(time.Second))
+
+	_, ok := c.get("/a", now.Add(2*time.Second))
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.lru.Len(), "expired entries must be removed")
+}
+
+func TestStatCacheIsBounded(t *testing.T) {
+	c := newStatCache(10)
+	expiresAt := time.Now().Add(time.Minute)
+
+	for i := range 100 {
+		c.set(fmt.Sprintf("/%d", i), true, expiresAt)
+	}
+
+	assert.Equal(t, 10, c.lru.Len())
+	assert.Len(t, c.entries, 10)
+}
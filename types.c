@@ -31,6 +31,271 @@ void __zval_double__(zval *zv, double val) { ZVAL_DOUBLE(zv, val); }
 
 void __zval_string__(zval *zv, zend_string *str) { ZVAL_STR(zv, str); }
 
+void __zval_empty_string__(zval *zv) { ZVAL_EMPTY_STRING(zv); }
+
 void __zval_arr__(zval *zv, zend_array *arr) { ZVAL_ARR(zv, arr); }
 
 zend_array *__zend_new_array__(uint32_t size) { return zend_new_array(size); }
+
+zval *__zval_deref__(zval *zv) {
+  ZVAL_DEREF(zv);
+  return zv;
+}
+
+/* copies the public properties of an object to a new array */
+zend_array *__zend_object_public_properties__(zend_object *obj) {
+  zend_array *result = zend_new_array(0);
+  zend_string *key;
+  zval *value;
+
+  HashTable *properties = obj->handlers->get_properties(obj);
+  ZEND_HASH_FOREACH_STR_KEY_VAL_IND(properties, key, value) {
+    /* protected and private properties have mangled names */
+    if (key == NULL || ZSTR_VAL(key)[0] == '\0') {
+      continue;
+    }
+
+    ZVAL_DEREF(value);
+    Z_TRY_ADDREF_P(value);
+    zend_hash_update(result, key, value);
+  }
+  ZEND_HASH_FOREACH_END();
+
+  return result;
+}
+
+/* returns the value of a backed enum case, or the name of a pure enum case */
+zval *__zend_enum_value__(zend_object *obj) {
+  if (!(obj->ce->ce_flags & ZEND_ACC_ENUM)) {
+    return NULL;
+  }
+
+  if (obj->ce->enum_backing_type == IS_UNDEF) {
+    return zend_enum_fetch_case_name(obj);
+  }
+
+  return zend_enum_fetch_case_value(obj);
+}
+
+bool __zend_object_json_serialize__(zend_object *obj, zval *retval) {
+  if (!instanceof_function(obj->ce, php_json_serializable_ce)) {
+    return false;
+  }
+
+  zend_call_method_with_0_params(obj, obj->ce, NULL, "jsonserialize",
+                                 retval);
+
+  return !EG(exception) && Z_TYPE_P(retval) != IS_UNDEF;
+}
+
+/* formats a DateTimeInterface object as a RFC 3339 string with microseconds
+ */
+zend_string *__zend_date_format__(zend_object *obj) {
+  if (!instanceof_function(obj->ce, php_date_get_interface_ce())) {
+    return NULL;
+  }
+
+  zval format, retval;
+  ZVAL_STRING(&format, "Y-m-d\\TH:i:s.uP");
+  zend_call_method_with_1_params(obj, obj->ce, NULL, "format", &retval,
+                                 &format);
+  zval_ptr_dtor(&format);
+
+  if (EG(exception) || Z_TYPE(retval) != IS_STRING) {
+    zval_ptr_dtor(&retval);
+    return NULL;
+  }
+
+  return Z_STR(retval);
+}
+
+bool __zval_date_immutable__(zval *zv, char *str, size_t len) {
+  object_init_ex(zv, php_date_get_immutable_ce());
+
+  if (!php_date_initialize(Z_PHPDATE_P(zv), str, len, NULL, NULL, 0)) {
+    zval_ptr_dtor(zv);
+    return false;
+  }
+
+  return true;
+}
+
+/* instantiates a class without calling its constructor */
+bool __zval_object__(zval *zv, char *class_name, size_t len) {
+  if (class_name == NULL) {
+    object_init(zv);
+    return true;
+  }
+
+  zend_string *name = zend_string_init(class_name, len, 0);
+  zend_class_entry *ce = zend_lookup_class(name);
+  zend_string_release(name);
+
+  if (ce == NULL || object_init_ex(zv, ce) != SUCCESS) {
+    return false;
+  }
+
+  return true;
+}
+
+/* sets a property, the value is released */
+bool __zend_update_property__(zval *obj, char *name, size_t len,
+                              zval *value) {
+  zend_update_property(Z_OBJCE_P(obj), Z_OBJ_P(obj), name, len, value);
+  zval_ptr_dtor(value);
+
+  return !EG(exception);
+}
+
+/* returns the message of the current exception and clears it */
+zend_string *__zend_exception_message__(void) {
+  if (!EG(exception)) {
+    return NULL;
+  }
+
+  zval rv;
+  zval *message = zend_read_property_ex(EG(exception)->ce, EG(exception),
+                                        ZSTR_KNOWN(ZEND_STR_MESSAGE), 1, &rv);
+  zend_string *result =
+      Z_TYPE_P(message) == IS_STRING ? zend_string_copy(Z_STR_P(message)) : NULL;
+  zend_clear_exception();
+
+  return result;
+}
+
+void __zval_copy__(zval *dst, zval *src) {
+  ZVAL_DEREF(src);
+  ZVAL_COPY(dst, src);
+}
+
+/* set when a bailout (fatal error) is caught while calling a PHP callable
+ * from Go */
+static __thread bool has_pending_bailout = false;
+
+/* calls a PHP callable, fatal errors are caught to not unwind the Go stack,
+ * the bailout is raised again on the next VM interrupt, once the Go function
+ * has returned. Callables aren't called anymore once a fatal error occurred */
+bool __call_user_function__(zval *callable, zval *retval, uint32_t argc,
+                            zval *argv) {
+  if (has_pending_bailout) {
+    ZVAL_UNDEF(retval);
+
+    return false;
+  }
+
+  zend_execute_data *prev_execute_data = EG(current_execute_data);
+  volatile bool success = false;
+
+  zend_try {
+    success = call_user_function(NULL, NULL, callable, retval, argc, argv) ==
+              SUCCESS;
+  }
+  zend_catch {
+    /* the bailout skipped the cleanup of the frames of the callable */
+    EG(current_execute_data) = prev_execute_data;
+    ZVAL_UNDEF(retval);
+    has_pending_bailout = true;
+    zend_atomic_bool_store(&EG(vm_interrupt), true);
+  }
+  zend_end_try();
+
+  return success;
+}
+
+/* checks if a fatal error occurred in a PHP callable called from Go */
+bool __has_pending_bailout__(void) { return has_pending_bailout; }
+
+/* raises the bailout caught while calling a PHP callable from Go, if any */
+void __raise_pending_bailout__(void) {
+  if (has_pending_bailout) {
+    has_pending_bailout = false;
+    zend_bailout();
+  }
+}
+
+/* forgets the bailout that couldn't be raised before the end of the request */
+void __clear_pending_bailout__(void) { has_pending_bailout = false; }
+
+zend_string *__zend_interned_string__(char *str, size_t len) {
+  return zend_string_init_interned(str, len, 0);
+}
+
+#if ZEND_DEBUG
+/* the zend_strings viewed from Go during the current request, no reference is
+ * held to not change their lifetime */
+typedef struct {
+  zend_string *str;
+  size_t len;
+  zend_ulong hash;
+} string_view;
+
+static __thread string_view *string_views = NULL;
+static __thread size_t string_views_len = 0;
+static __thread size_t string_views_cap = 0;
+
+/* checks, without dereferencing freed memory, that the viewed zend_string is
+ * still alive: its memory is still allocated by ZendMM and it still has the
+ * hash cached when the view was created */
+static bool string_view_is_alive(string_view *view) {
+  zend_string *str = view->str;
+
+  if (!ZSTR_IS_INTERNED(str) && !is_zend_ptr(str)) {
+    return false;
+  }
+
+  return GC_TYPE(str) == IS_STRING && GC_REFCOUNT(str) > 0 &&
+         ZSTR_LEN(str) == view->len && ZSTR_H(str) == view->hash;
+}
+#endif
+
+/* records the hash of the viewed zend_string to detect modifications at the
+ * end of the request, only in debug builds */
+void __zend_string_view__(zend_string *str) {
+#if ZEND_DEBUG
+  ZEND_ASSERT(GC_TYPE(str) == IS_STRING &&
+              "viewing a zend_string that has been released");
+
+  if (string_views_len == string_views_cap) {
+    string_views_cap = string_views_cap == 0 ? 16 : string_views_cap * 2;
+    string_views =
+        realloc(string_views, string_views_cap * sizeof(string_view));
+  }
+
+  /* the hash is cached in the string, it identifies it at the end of the
+   * request even if its memory has been reused by another string */
+  string_views[string_views_len++] = (string_view){
+      str,
+      ZSTR_LEN(str),
+      zend_string_hash_val(str),
+  };
+#endif
+}
+
+/* checks that the zend_strings viewed during the request and still alive
+ * haven't been modified */
+void __zend_string_views_release__(void) {
+#if ZEND_DEBUG
+  for (size_t i = 0; i < string_views_len; i++) {
+    string_view *view = &string_views[i];
+    if (!string_view_is_alive(view)) {
+      continue;
+    }
+
+    ZEND_ASSERT(zend_inline_hash_func(ZSTR_VAL(view->str), view->len) ==
+                    view->hash &&
+                "a zend_string has been modified through a view");
+  }
+
+  string_views_len = 0;
+#endif
+}
+
+/* frees the list of views, when the thread stops */
+void __zend_string_views_free__(void) {
+#if ZEND_DEBUG
+  free(string_views);
+  string_views = NULL;
+  string_views_len = 0;
+  string_views_cap = 0;
+#endif
+}
@@ -5,7 +5,16 @@ package frankenphp
 */
 import "C"
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -37,6 +46,75 @@ func PHPString(s string, persistent bool) unsafe.Pointer {
 	return unsafe.Pointer(zendStr)
 }
 
+// EXPERIMENTAL: UnsafeStringView returns a read-only view of a zend_string, without copying it.
+//
+// The returned string shares the memory of the zend_string: it is only valid as long as the zend_string is,
+// at most until the end of the current request, and must not be retained (use strings.Clone() to keep a copy).
+// With debug builds of PHP, an assertion fails at the end of the request if a viewed zend_string
+// that is still alive has been modified, the lifetime of the zend_string isn't changed.
+func UnsafeStringView(s unsafe.Pointer) string {
+	data, length := stringView(s)
+	if length == 0 {
+		return ""
+	}
+
+	return unsafe.String(data, length)
+}
+
+// EXPERIMENTAL: UnsafeBytesView returns a read-only view of a zend_string as a byte slice, without copying it.
+//
+// The slice must never be modified, the validity rules of UnsafeStringView apply.
+func UnsafeBytesView(s unsafe.Pointer) []byte {
+	data, length := stringView(s)
+	if length == 0 {
+		return nil
+	}
+
+	return unsafe.Slice(data, length)
+}
+
+func stringView(s unsafe.Pointer) (*byte, int) {
+	if s == nil {
+		return nil, 0
+	}
+
+	zendStr := (*C.zend_string)(s)
+	if C.ZEND_DEBUG != 0 {
+		C.__zend_string_view__(zendStr)
+	}
+
+	return (*byte)(unsafe.Pointer(&zendStr.val)), int(zendStr.len)
+}
+
+// EXPERIMENTAL: PHPBytes converts a Go byte slice to a zend_string with a single copy,
+// persistent strings must be freed as explained in PHPString.
+func PHPBytes(b []byte, persistent bool) unsafe.Pointer {
+	if len(b) == 0 {
+		return nil
+	}
+
+	zendStr := C.zend_string_init(
+		(*C.char)(unsafe.Pointer(unsafe.SliceData(b))),
+		C.size_t(len(b)),
+		C._Bool(persistent),
+	)
+
+	return unsafe.Pointer(zendStr)
+}
+
+// EXPERIMENTAL: PHPInternedString converts a Go byte slice to an interned zend_string.
+//
+// Interned strings are shared: the string is only copied if an identical interned string doesn't exist yet,
+// which is useful for values returned repeatedly such as array keys. They are freed at the end of the PHP request,
+// in worker mode when the worker script stops: don't intern an unbounded number of different strings.
+func PHPInternedString(b []byte) unsafe.Pointer {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return unsafe.Pointer(C.__zend_interned_string__((*C.char)(unsafe.Pointer(unsafe.SliceData(b))), C.size_t(len(b))))
+}
+
 // AssociativeArray represents a PHP array with ordered key-value pairs
 type AssociativeArray struct {
 	Map   map[string]any
@@ -67,56 +145,8 @@ func goArray(arr unsafe.Pointer, ordered bool) (map[string]any, []string) {
 		panic("received a *zval that wasn't a HashTable on array conversion")
 	}
 
-	nNumUsed := hashTable.nNumUsed
-	entries := make(map[string]any)
-	var order []string
-	if ordered {
-		order = make([]string, 0, nNumUsed)
-	}
-
-	if htIsPacked(hashTable) {
-		// if the HashTable is packed, convert all integer keys to strings
-		// this is probably a bug by the dev using this function
-		// still, we'll (inefficiently) convert to an associative array
-		for i := C.uint32_t(0); i < nNumUsed; i++ {
-			v := C.get_ht_packed_data(hashTable, i)
-			if v != nil && C.zval_get_type(v) != C.IS_UNDEF {
-				strIndex := strconv.Itoa(int(i))
-				entries[strIndex] = convertZvalToGo(v)
-				if ordered {
-					order = append(order, strIndex)
-				}
-			}
-		}
-
-		return entries, order
-	}
-
-	for i := C.uint32_t(0); i < nNumUsed; i++ {
-		bucket := C.get_ht_bucket_data(hashTable, i)
-		if bucket == nil || C.zval_get_type(&bucket.val) == C.IS_UNDEF {
-			continue
-		}
-
-		v := convertZvalToGo(&bucket.val)
-
-		if bucket.key != nil {
-			keyStr := GoString(unsafe.Pointer(bucket.key))
-			entries[keyStr] = v
-			if ordered {
-				order = append(order, keyStr)
-			}
-
-			continue
-		}
-
-		// as fallback convert the bucket index to a string key
-		strIndex := strconv.Itoa(int(bucket.h))
-		entries[strIndex] = v
-		if ordered {
-			order = append(order, strIndex)
-		}
-	}
+	c := goConverter{lenient: true}
+	entries, order, _ := c.associativeArray(hashTable, ordered, false)
 
 	return entries, order
 }
@@ -134,27 +164,8 @@ func GoPackedArray(arr unsafe.Pointer) []any {
 		panic("GoPackedArray received *zval that wasn't a HashTable")
 	}
 
-	nNumUsed := hashTable.nNumUsed
-	result := make([]any, 0, nNumUsed)
-
-	if htIsPacked(hashTable) {
-		for i := C.uint32_t(0); i < nNumUsed; i++ {
-			v := C.get_ht_packed_data(hashTable, i)
-			if v != nil && C.zval_get_type(v) != C.IS_UNDEF {
-				result = append(result, convertZvalToGo(v))
-			}
-		}
-
-		return result
-	}
-
-	// fallback if ht isn't packed - equivalent to array_values()
-	for i := C.uint32_t(0); i < nNumUsed; i++ {
-		bucket := C.get_ht_bucket_data(hashTable, i)
-		if bucket != nil && C.zval_get_type(&bucket.val) != C.IS_UNDEF {
-			result = append(result, convertZvalToGo(&bucket.val))
-		}
-	}
+	c := goConverter{lenient: true}
+	result, _ := c.packedArray(hashTable)
 
 	return result
 }
@@ -207,76 +218,462 @@ func PHPPackedArray(slice []any) unsafe.Pointer {
 	return unsafe.Pointer(&zval)
 }
 
-// convertZvalToGo converts a PHP zval to a Go any
-func convertZvalToGo(zval *C.zval) any {
+// the maximum nesting level of converted values, deeper values are most likely recursive
+const maxConversionDepth = 512
+
+var errMaxConversionDepth = fmt.Errorf("maximum nesting level of %d reached, the value is probably recursive", maxConversionDepth)
+
+// goConverter converts PHP values to Go, it keeps track of the objects being converted to detect recursion.
+// In lenient mode, nested values that can't be converted are nil instead of returning an error.
+type goConverter struct {
+	lenient bool
+	depth   int
+	objects map[*C.zend_object]struct{}
+}
+
+// zvalPathError is an error returned when a nested PHP value can't be converted
+type zvalPathError struct {
+	path string
+	err  error
+}
+
+func (e *zvalPathError) Error() string {
+	return fmt.Sprintf("%s at %q", e.err, strings.TrimPrefix(e.path, "."))
+}
+
+func (e *zvalPathError) Unwrap() error {
+	return e.err
+}
+
+// zvalToGo converts a PHP zval to a Go any
+func zvalToGo(zval *C.zval) (any, error) {
+	c := goConverter{}
+
+	return c.convert(zval)
+}
+
+// convertEntry converts a value contained in an array or an object, segment is its path relative to the container
+func (c *goConverter) convertEntry(zval *C.zval, segment string) (any, error) {
+	v, err := c.convert(zval)
+	if err == nil || c.lenient {
+		return v, nil
+	}
+
+	if pathErr, ok := err.(*zvalPathError); ok {
+		return nil, &zvalPathError{segment + pathErr.path, pathErr.err}
+	}
+
+	return nil, &zvalPathError{segment, err}
+}
+
+func (c *goConverter) convert(zval *C.zval) (any, error) {
+	if c.depth >= maxConversionDepth {
+		return nil, errMaxConversionDepth
+	}
+	c.depth++
+	defer func() { c.depth-- }()
+
+	zval = C.__zval_deref__(zval)
+
 	t := C.zval_get_type(zval)
 	switch t {
-	case C.IS_NULL:
-		return nil
+	case C.IS_NULL, C.IS_UNDEF:
+		return nil, nil
 	case C.IS_FALSE:
-		return false
+		return false, nil
 	case C.IS_TRUE:
-		return true
+		return true, nil
 	case C.IS_LONG:
 		longPtr := (*C.zend_long)(castZval(zval, C.IS_LONG))
 		if longPtr != nil {
-			return int64(*longPtr)
+			return int64(*longPtr), nil
 		}
-		return int64(0)
+		return int64(0), nil
 	case C.IS_DOUBLE:
 		doublePtr := (*C.double)(castZval(zval, C.IS_DOUBLE))
 		if doublePtr != nil {
-			return float64(*doublePtr)
+			return float64(*doublePtr), nil
 		}
-		return float64(0)
+		return float64(0), nil
 	case C.IS_STRING:
 		str := (*C.zend_string)(castZval(zval, C.IS_STRING))
 		if str == nil {
-			return ""
+			return "", nil
 		}
 
-		return GoString(unsafe.Pointer(str))
+		return GoString(unsafe.Pointer(str)), nil
 	case C.IS_ARRAY:
 		hashTable := (*C.HashTable)(castZval(zval, C.IS_ARRAY))
-		if hashTable != nil && htIsPacked(hashTable) {
-			return GoPackedArray(unsafe.Pointer(zval))
+		if hashTable == nil {
+			return AssociativeArray{Map: map[string]any{}, Order: []string{}}, nil
 		}
 
-		return GoAssociativeArray(unsafe.Pointer(zval))
+		if htIsPacked(hashTable) {
+			return c.packedArray(hashTable)
+		}
+
+		entries, order, err := c.associativeArray(hashTable, true, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return AssociativeArray{entries, order}, nil
+	case C.IS_OBJECT:
+		return c.object((*C.zend_object)(castZval(zval, C.IS_OBJECT)))
 	default:
+		return nil, fmt.Errorf("unsupported PHP type %d", int(t))
+	}
+}
+
+// associativeArray converts a zend_array to a Go map, the keys of packed arrays are converted to strings.
+// If properties is true, the entries are the properties of an object.
+func (c *goConverter) associativeArray(hashTable *C.HashTable, ordered, properties bool) (map[string]any, []string, error) {
+	nNumUsed := hashTable.nNumUsed
+	entries := make(map[string]any)
+	var order []string
+	if ordered {
+		order = make([]string, 0, nNumUsed)
+	}
+
+	if htIsPacked(hashTable) {
+		// if the HashTable is packed, convert all integer keys to strings
+		// this is probably a bug by the dev using this function
+		// still, we'll (inefficiently) convert to an associative array
+		for i := C.uint32_t(0); i < nNumUsed; i++ {
+			v := C.get_ht_packed_data(hashTable, i)
+			if v != nil && C.zval_get_type(v) != C.IS_UNDEF {
+				strIndex := strconv.Itoa(int(i))
+				value, err := c.convertEntry(v, pathSegment(strIndex, properties))
+				if err != nil {
+					return nil, nil, err
+				}
+
+				entries[strIndex] = value
+				if ordered {
+					order = append(order, strIndex)
+				}
+			}
+		}
+
+		return entries, order, nil
+	}
+
+	for i := C.uint32_t(0); i < nNumUsed; i++ {
+		bucket := C.get_ht_bucket_data(hashTable, i)
+		if bucket == nil || C.zval_get_type(&bucket.val) == C.IS_UNDEF {
+			continue
+		}
+
+		var key string
+		if bucket.key != nil {
+			key = GoString(unsafe.Pointer(bucket.key))
+		} else {
+			// as fallback convert the bucket index to a string key
+			key = strconv.Itoa(int(bucket.h))
+		}
+
+		v, err := c.convertEntry(&bucket.val, pathSegment(key, properties))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entries[key] = v
+		if ordered {
+			order = append(order, key)
+		}
+	}
+
+	return entries, order, nil
+}
+
+// pathSegment returns the path of an array entry or of an object property, for error messages
+func pathSegment(key string, property bool) string {
+	if property {
+		return "." + key
+	}
+
+	return "[" + key + "]"
+}
+
+// packedArray converts a zend_array to a Go slice, the keys of associative arrays are ignored
+func (c *goConverter) packedArray(hashTable *C.HashTable) ([]any, error) {
+	nNumUsed := hashTable.nNumUsed
+	result := make([]any, 0, nNumUsed)
+
+	if htIsPacked(hashTable) {
+		for i := C.uint32_t(0); i < nNumUsed; i++ {
+			v := C.get_ht_packed_data(hashTable, i)
+			if v != nil && C.zval_get_type(v) != C.IS_UNDEF {
+				value, err := c.convertEntry(v, pathSegment(strconv.Itoa(len(result)), false))
+				if err != nil {
+					return nil, err
+				}
+
+				result = append(result, value)
+			}
+		}
+
+		return result, nil
+	}
+
+	// fallback if ht isn't packed - equivalent to array_values()
+	for i := C.uint32_t(0); i < nNumUsed; i++ {
+		bucket := C.get_ht_bucket_data(hashTable, i)
+		if bucket != nil && C.zval_get_type(&bucket.val) != C.IS_UNDEF {
+			value, err := c.convertEntry(&bucket.val, pathSegment(strconv.Itoa(len(result)), false))
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, value)
+		}
+	}
+
+	return result, nil
+}
+
+// object converts a PHP object to a Go value:
+// enum cases are converted to their value, DateTimeInterface objects to time.Time,
+// JsonSerializable objects to the value returned by jsonSerialize() and other objects to their public properties
+func (c *goConverter) object(obj *C.zend_object) (any, error) {
+	if value := C.__zend_enum_value__(obj); value != nil {
+		return c.convert(value)
+	}
+
+	if str := C.__zend_date_format__(obj); str != nil {
+		defer C.zend_string_release(str)
+
+		return time.Parse(time.RFC3339Nano, GoString(unsafe.Pointer(str)))
+	}
+
+	// the object is already being converted: it contains itself
+	if _, ok := c.objects[obj]; ok {
+		return nil, errors.New("cannot convert recursive PHP object")
+	}
+	if c.objects == nil {
+		c.objects = make(map[*C.zend_object]struct{})
+	}
+	c.objects[obj] = struct{}{}
+	defer delete(c.objects, obj)
+
+	var retval C.zval
+	if C.__zend_object_json_serialize__(obj, &retval) {
+		defer C.zval_ptr_dtor(&retval)
+
+		return c.convert(&retval)
+	}
+	if err := pendingException(); err != nil {
+		return nil, err
+	}
+
+	properties := C.__zend_object_public_properties__(obj)
+	defer C.zend_array_release(properties)
+
+	entries, order, err := c.associativeArray((*C.HashTable)(properties), true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return AssociativeArray{entries, order}, nil
+}
+
+// pendingException returns the PHP exception thrown during a conversion as an error, and clears it
+func pendingException() error {
+	message := C.__zend_exception_message__()
+	if message == nil {
 		return nil
 	}
+	defer C.zend_string_release(message)
+
+	return fmt.Errorf("PHP exception: %s", GoString(unsafe.Pointer(message)))
 }
 
-// convertGoToZval converts a Go any to a PHP zval
+// convertGoToZval converts a Go any to a PHP zval, values that can't be converted are null
 func convertGoToZval(value any) *C.zval {
 	var zval C.zval
+	if err := goToZval(value, &zval); err != nil {
+		C.__zval_null__(&zval)
+	}
+
+	return &zval
+}
+
+// zvalConverter converts Go values to PHP, it keeps track of the pointers being converted to detect recursion
+type zvalConverter struct {
+	depth    int
+	pointers map[visitedPointer]struct{}
+}
+
+// visitedPointer identifies a pointer being converted, pointers to a struct and to its first field have the same address
+type visitedPointer struct {
+	addr uintptr
+	t    reflect.Type
+}
+
+// goToZval converts a Go any to a PHP zval
+func goToZval(value any, zval *C.zval) error {
+	c := zvalConverter{}
+
+	return c.convert(value, zval)
+}
+
+func (c *zvalConverter) convert(value any, zval *C.zval) error {
+	if c.depth >= maxConversionDepth {
+		return errMaxConversionDepth
+	}
+	c.depth++
+	defer func() { c.depth-- }()
 
 	switch v := value.(type) {
 	case nil:
-		C.__zval_null__(&zval)
+		C.__zval_null__(zval)
 	case bool:
-		C.__zval_bool__(&zval, C._Bool(v))
+		C.__zval_bool__(zval, C._Bool(v))
 	case int:
-		C.__zval_long__(&zval, C.zend_long(v))
+		C.__zval_long__(zval, C.zend_long(v))
 	case int64:
-		C.__zval_long__(&zval, C.zend_long(v))
+		C.__zval_long__(zval, C.zend_long(v))
 	case float64:
-		C.__zval_double__(&zval, C.double(v))
+		C.__zval_double__(zval, C.double(v))
 	case string:
+		if v == "" {
+			C.__zval_empty_string__(zval)
+
+			break
+		}
+
 		str := (*C.zend_string)(PHPString(v, false))
-		C.__zval_string__(&zval, str)
+		C.__zval_string__(zval, str)
 	case AssociativeArray:
-		return (*C.zval)(PHPAssociativeArray(v))
+		return c.associativeArray(v, zval)
 	case map[string]any:
-		return (*C.zval)(PHPAssociativeArray(AssociativeArray{Map: v}))
+		return c.associativeArray(AssociativeArray{Map: v}, zval)
 	case []any:
-		return (*C.zval)(PHPPackedArray(v))
+		return c.sliceValue(reflect.ValueOf(v), zval)
 	default:
-		C.__zval_null__(&zval)
+		return c.reflectValue(reflect.ValueOf(value), zval)
 	}
 
-	return &zval
+	return nil
+}
+
+// EXPERIMENTAL: PHPValue converts a Go value to a PHP zval.
+//
+// In addition to the types supported by PHPMap and PHPPackedArray, typed slices, arrays and maps are converted to PHP arrays,
+// time.Time to DateTimeImmutable objects and structs to objects: instances of the class registered with RegisterPHPClass,
+// or stdClass. Exported fields are converted to properties, named after the "php" struct tag if any:
+//
+//	type Point struct {
+//		X int `php:"x"`
+//		Y int `php:"y"`
+//		Label string `php:"label,omitempty"`
+//		Internal string `php:"-"`
+//	}
+//
+// An error is returned if the value contains unsupported types (channels, functions...) instead of converting them to null.
+func PHPValue(value any) (unsafe.Pointer, error) {
+	var zval C.zval
+	if err := goToZval(value, &zval); err != nil {
+		return nil, err
+	}
+
+	return unsafe.Pointer(&zval), nil
+}
+
+// EXPERIMENTAL: GoValue converts a PHP zval to a Go value of type T.
+//
+// Objects are converted to their public properties, except backed enum cases which are converted to their value,
+// DateTimeInterface objects which are converted to time.Time and JsonSerializable objects which are converted
+// to the value returned by jsonSerialize(). Arrays and objects can be decoded into structs using the same "php" struct tags
+// as PHPValue, into typed slices and maps, numbers are converted to any numeric type if they fit.
+// An error describing the path of the invalid value is returned if the conversion isn't possible.
+func GoValue[T any](zval unsafe.Pointer) (T, error) {
+	var result T
+
+	value, err := zvalToGo((*C.zval)(zval))
+	if err != nil {
+		return result, err
+	}
+
+	if err := assignGoValue(reflect.ValueOf(&result).Elem(), value, ""); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// EXPERIMENTAL: PHPCallable is a PHP callable (closure, function name, invokable object...) that can be called from Go.
+//
+// Callables must only be used on the PHP thread handling the current request,
+// usually in the function receiving them as a parameter.
+type PHPCallable struct {
+	zval C.zval
+}
+
+// EXPERIMENTAL: NewPHPCallable creates a handle to a callable zval, such as a callable parameter of an extension function.
+// Release must be called once the callable isn't needed anymore.
+func NewPHPCallable(callable unsafe.Pointer) (*PHPCallable, error) {
+	if callable == nil || !C.zend_is_callable((*C.zval)(callable), 0, nil) {
+		return nil, errors.New("the value is not callable")
+	}
+
+	c := &PHPCallable{}
+	C.__zval_copy__(&c.zval, (*C.zval)(callable))
+
+	return c, nil
+}
+
+// EXPERIMENTAL: Call calls the PHP callable with the given arguments, converted as with PHPValue, and returns its result.
+//
+// If the callable throws, the exception is cleared and returned as an error.
+// If it triggers a fatal error, an error is returned and the script is stopped once the Go function returns to PHP,
+// subsequent calls return an error without calling the callable.
+func (c *PHPCallable) Call(args ...any) (any, error) {
+	argv := make([]C.zval, len(args))
+	defer func() {
+		for i := range argv {
+			C.zval_ptr_dtor(&argv[i])
+		}
+	}()
+
+	for i, arg := range args {
+		if err := goToZval(arg, &argv[i]); err != nil {
+			argv = argv[:i]
+
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+	}
+
+	var argvPtr *C.zval
+	if len(argv) > 0 {
+		argvPtr = &argv[0]
+	}
+
+	var retval C.zval
+	if !C.__call_user_function__(&c.zval, &retval, C.uint32_t(len(argv)), argvPtr) {
+		if C.__has_pending_bailout__() {
+			return nil, errors.New("a fatal error occurred in a PHP callable, the script will be stopped")
+		}
+
+		if err := pendingException(); err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("unable to call the PHP callable")
+	}
+	defer C.zval_ptr_dtor(&retval)
+
+	if err := pendingException(); err != nil {
+		return nil, err
+	}
+
+	return zvalToGo(&retval)
+}
+
+// EXPERIMENTAL: Release releases the PHP callable, it must not be used anymore.
+func (c *PHPCallable) Release() {
+	C.zval_ptr_dtor(&c.zval)
+	C.__zval_null__(&c.zval)
 }
 
 // createNewArray creates a new zend_array with the specified size.
@@ -309,7 +706,560 @@ func castZval(zval *C.zval, expectedType C.uint8_t) unsafe.Pointer {
 		return unsafe.Pointer(*(**C.zend_string)(v))
 	case C.IS_ARRAY:
 		return unsafe.Pointer(*(**C.zend_array)(v))
+	case C.IS_OBJECT:
+		return unsafe.Pointer(*(**C.zend_object)(v))
+	default:
+		return nil
+	}
+}
+
+// the format used to convert time.Time to DateTimeImmutable, PHP supports microseconds
+const phpDateFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+var (
+	timeType   = reflect.TypeFor[time.Time]()
+	phpClasses sync.Map
+)
+
+// EXPERIMENTAL: RegisterPHPClass registers the PHP class to instantiate when converting values of the type of sample
+// (a struct or a pointer to a struct) to PHP. The constructor of the class isn't called, properties are set directly.
+func RegisterPHPClass(className string, sample any) {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("RegisterPHPClass: %T is not a struct", sample))
+	}
+
+	phpClasses.Store(t, className)
+}
+
+// reflectValue converts the Go types not handled by convert
+func (c *zvalConverter) reflectValue(v reflect.Value, zval *C.zval) error {
+	if !v.IsValid() {
+		C.__zval_null__(zval)
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			C.__zval_null__(zval)
+
+			return nil
+		}
+
+		if v.Kind() == reflect.Pointer {
+			// the pointer is already being converted: the value contains itself
+			ptr := visitedPointer{v.Pointer(), v.Type()}
+			if _, ok := c.pointers[ptr]; ok {
+				return fmt.Errorf("cannot convert Go %s to PHP: recursive value", v.Type())
+			}
+			if c.pointers == nil {
+				c.pointers = make(map[visitedPointer]struct{})
+			}
+			c.pointers[ptr] = struct{}{}
+			defer delete(c.pointers, ptr)
+		}
+
+		return c.convert(v.Elem().Interface(), zval)
+	case reflect.Bool:
+		C.__zval_bool__(zval, C._Bool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		C.__zval_long__(zval, C.zend_long(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return fmt.Errorf("cannot convert Go %s %d to PHP: overflows int", v.Type(), v.Uint())
+		}
+
+		C.__zval_long__(zval, C.zend_long(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		C.__zval_double__(zval, C.double(v.Float()))
+	case reflect.String:
+		return c.convert(v.String(), zval)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			C.__zval_null__(zval)
+
+			return nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte and [N]byte are converted to strings
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+
+			return c.convert(string(b), zval)
+		}
+
+		return c.sliceValue(v, zval)
+	case reflect.Map:
+		if v.IsNil() {
+			C.__zval_null__(zval)
+
+			return nil
+		}
+
+		return c.mapValue(v, zval)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			date := v.Interface().(time.Time).Format(phpDateFormat)
+			if !C.__zval_date_immutable__(zval, toUnsafeChar(date), C.size_t(len(date))) {
+				return fmt.Errorf("cannot convert Go time %q to PHP DateTimeImmutable", date)
+			}
+
+			return nil
+		}
+
+		return c.structValue(v, zval)
+	default:
+		return fmt.Errorf("cannot convert Go %s to PHP: unsupported type", v.Type())
+	}
+
+	return nil
+}
+
+func (c *zvalConverter) sliceValue(v reflect.Value, zval *C.zval) error {
+	zendArray := createNewArray(uint32(v.Len()))
+	C.__zval_arr__(zval, zendArray)
+
+	for i := 0; i < v.Len(); i++ {
+		var item C.zval
+		if err := c.convert(v.Index(i).Interface(), &item); err != nil {
+			C.zval_ptr_dtor(zval)
+
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		C.zend_hash_next_index_insert(zendArray, &item)
+	}
+
+	return nil
+}
+
+// associativeArray converts the entries in the order of the keys, or sorted by key,
+// unlike phpArray it returns an error instead of converting unsupported values to null
+func (c *zvalConverter) associativeArray(arr AssociativeArray, zval *C.zval) error {
+	keys := arr.Order
+	if len(keys) == 0 {
+		keys = slices.Sorted(maps.Keys(arr.Map))
+	}
+
+	zendArray := createNewArray(uint32(len(keys)))
+	C.__zval_arr__(zval, zendArray)
+
+	for _, key := range keys {
+		var item C.zval
+		if err := c.convert(arr.Map[key], &item); err != nil {
+			C.zval_ptr_dtor(zval)
+
+			return fmt.Errorf("[%s]: %w", key, err)
+		}
+
+		C.zend_hash_str_update(zendArray, toUnsafeChar(key), C.size_t(len(key)), &item)
+	}
+
+	return nil
+}
+
+func (c *zvalConverter) mapValue(v reflect.Value, zval *C.zval) error {
+	zendArray := createNewArray(uint32(v.Len()))
+	C.__zval_arr__(zval, zendArray)
+
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+
+	for _, key := range keys {
+		var item C.zval
+		if err := c.convert(v.MapIndex(key).Interface(), &item); err != nil {
+			C.zval_ptr_dtor(zval)
+
+			return fmt.Errorf("[%v]: %w", key.Interface(), err)
+		}
+
+		switch key.Kind() {
+		case reflect.String:
+			k := key.String()
+			C.zend_hash_str_update(zendArray, toUnsafeChar(k), C.size_t(len(k)), &item)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			C.zend_hash_index_update(zendArray, C.zend_ulong(key.Int()), &item)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			C.zend_hash_index_update(zendArray, C.zend_ulong(key.Uint()), &item)
+		default:
+			C.zval_ptr_dtor(&item)
+			C.zval_ptr_dtor(zval)
+
+			return fmt.Errorf("cannot convert Go %s to PHP: unsupported key type", v.Type())
+		}
+	}
+
+	return nil
+}
+
+func (c *zvalConverter) structValue(v reflect.Value, zval *C.zval) error {
+	var className *C.char
+	var classNameLen C.size_t
+	if name, ok := phpClasses.Load(v.Type()); ok {
+		className = toUnsafeChar(name.(string))
+		classNameLen = C.size_t(len(name.(string)))
+	}
+
+	if !C.__zval_object__(zval, className, classNameLen) {
+		name, _ := phpClasses.Load(v.Type())
+
+		return fmt.Errorf("cannot convert Go %s to PHP: class %q not found", v.Type(), name)
+	}
+
+	for _, field := range structFields(v.Type()) {
+		f, ok := fieldByIndex(v, field.index, false)
+		if !ok || field.omitEmpty && f.IsZero() {
+			continue
+		}
+
+		var property C.zval
+		if err := c.convert(f.Interface(), &property); err != nil {
+			C.zval_ptr_dtor(zval)
+
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+
+		if !C.__zend_update_property__(zval, toUnsafeChar(field.name), C.size_t(len(field.name)), &property) {
+			C.zval_ptr_dtor(zval)
+
+			err := pendingException()
+			if err == nil {
+				err = errors.New("unable to set the property")
+			}
+
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+	}
+
+	return nil
+}
+
+// structField is an exported field of a struct converted to a PHP property
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields returns the fields of a struct converted to PHP properties, fields of embedded structs are promoted
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct || !isReachable(t, f.Index) {
+			continue
+		}
+
+		tag := f.Tag.Get("php")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, structField{name: name, index: f.Index, omitEmpty: options == "omitempty"})
+	}
+
+	return fields
+}
+
+// fieldByIndex returns the nested field of a struct, nil embedded pointers are allocated if alloc is true
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// isReachable checks that a promoted field isn't embedded through an unexported struct
+func isReachable(t reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		f := indirectType(t).Field(x)
+		if !f.IsExported() {
+			return false
+		}
+
+		t = f.Type
+	}
+
+	return true
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// phpTypeName returns the name of the PHP type a Go value has been converted from, for error messages
+func phpTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case []any, AssociativeArray:
+		return "array"
+	case time.Time:
+		return "DateTimeInterface"
 	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func conversionError(path string, value any, t reflect.Type) error {
+	if path == "" {
+		return fmt.Errorf("cannot convert PHP %s to Go %s", phpTypeName(value), t)
+	}
+
+	return fmt.Errorf("cannot convert PHP %s to Go %s at %q", phpTypeName(value), t, path)
+}
+
+// assignGoValue stores a value converted by zvalToGo into dst, converting it to the type of dst
+func assignGoValue(dst reflect.Value, value any, path string) error {
+	t := dst.Type()
+
+	if value == nil {
+		dst.SetZero()
+
+		return nil
+	}
+
+	if t == timeType {
+		switch v := value.(type) {
+		case time.Time:
+			dst.Set(reflect.ValueOf(v))
+		case string:
+			parsed, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return conversionError(path, value, t)
+			}
+
+			dst.Set(reflect.ValueOf(parsed))
+		default:
+			return conversionError(path, value, t)
+		}
+
 		return nil
 	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		v := reflect.ValueOf(value)
+		if !v.Type().AssignableTo(t) {
+			return conversionError(path, value, t)
+		}
+
+		dst.Set(v)
+	case reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		if err := assignGoValue(elem.Elem(), value, path); err != nil {
+			return err
+		}
+
+		dst.Set(elem)
+	case reflect.Bool:
+		v, ok := value.(bool)
+		if !ok {
+			return conversionError(path, value, t)
+		}
+
+		dst.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, ok := value.(int64)
+		if !ok || dst.OverflowInt(v) {
+			return conversionError(path, value, t)
+		}
+
+		dst.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, ok := value.(int64)
+		if !ok || v < 0 || dst.OverflowUint(uint64(v)) {
+			return conversionError(path, value, t)
+		}
+
+		dst.SetUint(uint64(v))
+	case reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case float64:
+			dst.SetFloat(v)
+		case int64:
+			dst.SetFloat(float64(v))
+		default:
+			return conversionError(path, value, t)
+		}
+	case reflect.String:
+		v, ok := value.(string)
+		if !ok {
+			return conversionError(path, value, t)
+		}
+
+		dst.SetString(v)
+	case reflect.Slice:
+		if s, ok := value.(string); ok && t.Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes([]byte(s))
+
+			return nil
+		}
+
+		items, ok := arrayValues(value)
+		if !ok {
+			return conversionError(path, value, t)
+		}
+
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := assignGoValue(slice.Index(i), item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+
+		dst.Set(slice)
+	case reflect.Array:
+		items, ok := arrayValues(value)
+		if !ok || len(items) > t.Len() {
+			return conversionError(path, value, t)
+		}
+
+		for i, item := range items {
+			if err := assignGoValue(dst.Index(i), item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys, values, ok := arrayEntries(value)
+		if !ok {
+			return conversionError(path, value, t)
+		}
+
+		m := reflect.MakeMapWithSize(t, len(keys))
+		for i, key := range keys {
+			k := reflect.New(t.Key()).Elem()
+			switch t.Key().Kind() {
+			case reflect.String:
+				k.SetString(key)
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n, err := strconv.ParseInt(key, 10, t.Key().Bits())
+				if err != nil {
+					return fmt.Errorf("cannot convert PHP array key %q to Go %s at %q", key, t.Key(), path)
+				}
+
+				k.SetInt(n)
+			default:
+				return conversionError(path, value, t)
+			}
+
+			v := reflect.New(t.Elem()).Elem()
+			if err := assignGoValue(v, values[i], path+"["+key+"]"); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(k, v)
+		}
+
+		dst.Set(m)
+	case reflect.Struct:
+		arr, ok := value.(AssociativeArray)
+		if !ok {
+			return conversionError(path, value, t)
+		}
+
+		for _, field := range structFields(t) {
+			v, ok := arr.Map[field.name]
+			if !ok {
+				// same behavior as encoding/json
+				for _, key := range arr.Order {
+					if strings.EqualFold(key, field.name) {
+						v, ok = arr.Map[key], true
+
+						break
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+
+			fieldPath := field.name
+			if path != "" {
+				fieldPath = path + "." + field.name
+			}
+
+			f, _ := fieldByIndex(dst, field.index, true)
+			if err := assignGoValue(f, v, fieldPath); err != nil {
+				return err
+			}
+		}
+	default:
+		return conversionError(path, value, t)
+	}
+
+	return nil
+}
+
+// arrayValues returns the values of a PHP array in order, as array_values()
+func arrayValues(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		return v, true
+	case AssociativeArray:
+		values := make([]any, len(v.Order))
+		for i, key := range v.Order {
+			values[i] = v.Map[key]
+		}
+
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// arrayEntries returns the keys and the values of a PHP array in order
+func arrayEntries(value any) ([]string, []any, bool) {
+	switch v := value.(type) {
+	case []any:
+		keys := make([]string, len(v))
+		for i := range v {
+			keys[i] = strconv.Itoa(i)
+		}
+
+		return keys, v, true
+	case AssociativeArray:
+		values, _ := arrayValues(v)
+
+		return v.Order, values, true
+	default:
+		return nil, nil, false
+	}
 }
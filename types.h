@@ -1,10 +1,15 @@
 #ifndef TYPES_H
 #define TYPES_H
 
+#include <ext/date/php_date.h>
+#include <ext/json/php_json.h>
 #include <zend.h>
 #include <zend_API.h>
 #include <zend_alloc.h>
+#include <zend_enum.h>
+#include <zend_exceptions.h>
 #include <zend_hash.h>
+#include <zend_interfaces.h>
 #include <zend_types.h>
 
 zval *get_ht_packed_data(HashTable *, uint32_t index);
@@ -19,7 +24,28 @@ void __zval_bool__(zval *zv, bool val);
 void __zval_long__(zval *zv, zend_long val);
 void __zval_double__(zval *zv, double val);
 void __zval_string__(zval *zv, zend_string *str);
+void __zval_empty_string__(zval *zv);
 void __zval_arr__(zval *zv, zend_array *arr);
 zend_array *__zend_new_array__(uint32_t size);
+zval *__zval_deref__(zval *zv);
+
+zend_array *__zend_object_public_properties__(zend_object *obj);
+zval *__zend_enum_value__(zend_object *obj);
+bool __zend_object_json_serialize__(zend_object *obj, zval *retval);
+zend_string *__zend_date_format__(zend_object *obj);
+bool __zval_date_immutable__(zval *zv, char *str, size_t len);
+bool __zval_object__(zval *zv, char *class_name, size_t len);
+bool __zend_update_property__(zval *obj, char *name, size_t len, zval *value);
+zend_string *__zend_exception_message__(void);
+void __zval_copy__(zval *dst, zval *src);
+zend_string *__zend_interned_string__(char *str, size_t len);
+void __zend_string_view__(zend_string *str);
+void __zend_string_views_release__(void);
+void __zend_string_views_free__(void);
+bool __call_user_function__(zval *callable, zval *retval, uint32_t argc,
+                            zval *argv);
+bool __has_pending_bailout__(void);
+void __raise_pending_bailout__(void);
+void __clear_pending_bailout__(void);
 
 #endif
@@ -34,6 +34,26 @@ func TestGoString(t *testing.T) {
 	})
 }
 
+func TestUnsafeStringView(t *testing.T) {
+	testOnDummyPHPThread(t, func() {
+		zendString := PHPString("Hello, World!", false)
+
+		assert.Equal(t, "Hello, World!", UnsafeStringView(zendString))
+		assert.Equal(t, []byte("Hello, World!"), UnsafeBytesView(zendString))
+		assert.Equal(t, "", UnsafeStringView(nil))
+		assert.Nil(t, UnsafeBytesView(nil))
+	})
+}
+
+func TestPHPBytes(t *testing.T) {
+	testOnDummyPHPThread(t, func() {
+		original := []byte("Hello, World!")
+
+		assert.Equal(t, string(original), GoString(PHPBytes(original, false)), "[]byte -> zend_string -> string should yield an equal string")
+		assert.Nil(t, PHPBytes(nil, false))
+	})
+}
+
 func TestPHPMap(t *testing.T) {
 	testOnDummyPHPThread(t, func() {
 		originalMap := map[string]any{
@@ -125,3 +145,68 @@ func TestNestedMixedArray(t *testing.T) {
 		assert.Equal(t, originalArray, convertedArray, "nested mixed array should be equal after conversion")
 	})
 }
+
+func TestTypedCollections(t *testing.T) {
+	testOnDummyPHPThread(t, func() {
+		original := map[string][]int{"odd": {1, 3}, "even": {2, 4}}
+
+		zval, err := PHPValue(original)
+		assert.NoError(t, err)
+
+		converted, err := GoValue[map[string][]int](zval)
+		assert.NoError(t, err)
+		assert.Equal(t, original, converted)
+	})
+}
+
+func TestConversionErrors(t *testing.T) {
+	testOnDummyPHPThread(t, func() {
+		_, err := PHPValue(map[string]any{"chan": make(chan int)})
+		assert.ErrorContains(t, err, "chan")
+
+		zval, err := PHPValue(map[string]any{"points": []any{map[string]any{"x": 1}, map[string]any{"x": 300}}})
+		assert.NoError(t, err)
+
+		_, err = GoValue[struct {
+			Points []struct {
+				X int8 `php:"x"`
+			} `php:"points"`
+		}](zval)
+		assert.EqualError(t, err, `cannot convert PHP int to Go int8 at "points[1].x"`)
+	})
+}
+
+type node struct {
+	Name string `php:"name"`
+	Next *node  `php:"next"`
+}
+
+func TestRecursiveValues(t *testing.T) {
+	testOnDummyPHPThread(t, func() {
+		n := &node{Name: "a"}
+		n.Next = &node{Name: "b", Next: n}
+
+		_, err := PHPValue(n)
+		assert.ErrorContains(t, err, "recursive value")
+
+		s := []any{nil}
+		s[0] = s
+		_, err = PHPValue(s)
+		assert.ErrorIs(t, err, errMaxConversionDepth)
+
+		// a pointer to the first field of a struct isn't a pointer to the struct itself
+		type wrapper struct {
+			Node  node  `php:"node"`
+			First *node `php:"first"`
+		}
+		w := &wrapper{Node: node{Name: "c"}}
+		w.First = &w.Node
+
+		zval, err := PHPValue(w)
+		assert.NoError(t, err)
+
+		converted, err := GoValue[wrapper](zval)
+		assert.NoError(t, err)
+		assert.Equal(t, "c", converted.First.Name)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dunglas/frankenphp/internal/fastabs"
@@ -23,15 +24,19 @@ type worker struct {
 	threadMutex            sync.RWMutex
 	allowPathMatching      bool
 	maxConsecutiveFailures int
+	queuedRequests         atomic.Int64
 }
 
 var (
-	workers          []*worker
-	watcherIsEnabled bool
+	workers []*worker
+	// incremented each time the workers are initialized, to invalidate the workers resolved by path
+	workersGeneration atomic.Uint64
+	watcherIsEnabled  bool
 )
 
 func initWorkers(opt []workerOpt) error {
 	workers = make([]*worker, 0, len(opt))
+	workersGeneration.Add(1)
 	workersReady := sync.WaitGroup{}
 	directoriesToWatch := getDirectoriesToWatch(opt)
 	watcherIsEnabled = len(directoriesToWatch) > 0
@@ -234,10 +239,12 @@ func (worker *worker) handleRequest(fc *frankenPHPContext) {
 
 	// if no thread was available, mark the request as queued and apply the scaling strategy
 	metrics.QueuedWorkerRequest(worker.name)
+	worker.queuedRequests.Add(1)
 	for {
 		select {
 		case worker.requestChan <- fc:
 			metrics.DequeuedWorkerRequest(worker.name)
+			worker.queuedRequests.Add(-1)
 			<-fc.done
 			metrics.StopWorkerRequest(worker.name, time.Since(fc.startedAt))
 			return
@@ -245,6 +252,7 @@ func (worker *worker) handleRequest(fc *frankenPHPContext) {
 			// the request has triggered scaling, continue to wait for a thread
 		case <-timeoutChan(maxWaitTime):
 			metrics.DequeuedWorkerRequest(worker.name)
+			worker.queuedRequests.Add(-1)
 			// the request has timed out stalling
 			fc.reject(504, "Gateway Timeout")
 			return
@@ -9,8 +9,10 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -119,6 +121,19 @@ func TestWorkerGetOpt(t *testing.T) {
 	}
 }
 
+func TestWorkerReadyAfterBootFailure(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "booted")
+
+	runTest(t, func(handler func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		body, _ := testGet("http://example.com/worker-fails-once.php", handler, t)
+		assert.Equal(t, "ok", body)
+
+		report := frankenphp.Health()
+		assert.True(t, report.Ready, report.Reasons)
+		assert.Zero(t, report.Workers[0].FailingThreads)
+	}, &testOptions{workerScript: "worker-fails-once.php", nbWorkers: 1, nbParallelRequests: 1, env: map[string]string{"BOOT_MARKER": marker}})
+}
+
 func ExampleServeHTTP_workers() {
 	if err := frankenphp.Init(
 		frankenphp.WithWorkers("worker1", "worker1.php", 4,
@@ -164,3 +179,45 @@ func TestWorkerHasOSEnvironmentVariableInSERVER(t *testing.T) {
 		assert.Contains(t, string(body), "custom_env_variable_value")
 	}, &testOptions{workerScript: "worker.php", nbWorkers: 1, nbParallelRequests: 1})
 }
+
+func TestWorkerEvents(t *testing.T) {
+	var mu sync.Mutex
+	var events []frankenphp.WorkerEventType
+	eventHandler := func(e frankenphp.WorkerEvent) {
+		assert.Equal(t, "workerName", e.Worker)
+
+		mu.Lock()
+		events = append(events, e.Type)
+		mu.Unlock()
+	}
+
+	runTest(t, func(handler func(http.ResponseWriter, *http.Request), _ *httptest.Server, i int) {
+		body, _ := testGet("http://example.com/worker-with-counter.php", handler, t)
+		assert.Equal(t, "requests:1", body)
+
+		frankenphp.RestartWorkers()
+
+		body, _ = testGet("http://example.com/worker-with-counter.php", handler, t)
+		assert.Equal(t, "requests:1", body)
+	}, &testOptions{
+		workerScript:       "worker-with-counter.php",
+		nbWorkers:          1,
+		nbParallelRequests: 1,
+		initOpts:           []frankenphp.Option{frankenphp.WithWorkerEventHandler(eventHandler)},
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.GreaterOrEqual(t, len(events), 6)
+	assert.Equal(t, []frankenphp.WorkerEventType{
+		// initial boot
+		frankenphp.WorkerEventBooting,
+		frankenphp.WorkerEventReady,
+		// RestartWorkers()
+		frankenphp.WorkerEventDrained,
+		frankenphp.WorkerEventRestarted,
+		frankenphp.WorkerEventBooting,
+		frankenphp.WorkerEventReady,
+	}, events[:6])
+}
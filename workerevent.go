@@ -0,0 +1,80 @@
+package frankenphp
+
+import (
+	"time"
+)
+
+// WorkerEventType is the kind of a WorkerEvent.
+type WorkerEventType int
+
+const (
+	// WorkerEventBooting is emitted before a worker script is (re)started
+	WorkerEventBooting WorkerEventType = iota
+	// WorkerEventReady is emitted when a worker script reaches frankenphp_handle_request() for the first time
+	WorkerEventReady
+	// WorkerEventCrashed is emitted when a worker script exits with a non-zero status or before reaching frankenphp_handle_request()
+	WorkerEventCrashed
+	// WorkerEventRestarted is emitted when a worker script exits gracefully and is run again
+	WorkerEventRestarted
+	// WorkerEventTooManyFailures is emitted when the maximum number of consecutive failures is reached
+	WorkerEventTooManyFailures
+	// WorkerEventScaledUp is emitted when a thread is added to a worker by the autoscaler
+	WorkerEventScaledUp
+	// WorkerEventScaledDown is emitted when an idle thread is removed from a worker by the autoscaler
+	WorkerEventScaledDown
+	// WorkerEventDrained is emitted when a worker thread has stopped handling requests (restart or shutdown)
+	WorkerEventDrained
+)
+
+var workerEventTypeNames = map[WorkerEventType]string{
+	WorkerEventBooting:         "booting",
+	WorkerEventReady:           "ready",
+	WorkerEventCrashed:         "crashed",
+	WorkerEventRestarted:       "restarted",
+	WorkerEventTooManyFailures: "too many failures",
+	WorkerEventScaledUp:        "scaled up",
+	WorkerEventScaledDown:      "scaled down",
+	WorkerEventDrained:         "drained",
+}
+
+func (t WorkerEventType) String() string {
+	if name, ok := workerEventTypeNames[t]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// WorkerEvent describes a change in the lifecycle of a worker thread.
+type WorkerEvent struct {
+	Type        WorkerEventType
+	Worker      string
+	ThreadIndex int
+	// ExitStatus is the exit status of the worker script, only set for WorkerEventCrashed and WorkerEventRestarted
+	ExitStatus int
+	// Failures is the current count of consecutive failures, only set for WorkerEventCrashed and WorkerEventTooManyFailures
+	Failures int
+	Time     time.Time
+}
+
+var workerEventHandler func(WorkerEvent)
+
+// emitWorkerEvent passes the event to the configured handler, if any
+func emitWorkerEvent(eventType WorkerEventType, worker *worker, thread *phpThread) {
+	emitWorkerEventWithStatus(eventType, worker, thread, 0, 0)
+}
+
+func emitWorkerEventWithStatus(eventType WorkerEventType, worker *worker, thread *phpThread, exitStatus int, failures int) {
+	if workerEventHandler == nil {
+		return
+	}
+
+	workerEventHandler(WorkerEvent{
+		Type:        eventType,
+		Worker:      worker.name,
+		ThreadIndex: thread.threadIndex,
+		ExitStatus:  exitStatus,
+		Failures:    failures,
+		Time:        time.Now(),
+	})
+}